@@ -2,7 +2,11 @@ module github.com/kumarasakti/passgen
 
 go 1.21
 
-require github.com/spf13/cobra v1.9.1
+require (
+	github.com/spf13/cobra v1.9.1
+	golang.org/x/crypto v0.31.0
+	golang.org/x/text v0.21.0
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
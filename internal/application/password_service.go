@@ -1,27 +1,37 @@
 package application
 
 import (
+	"fmt"
+
 	"github.com/kumarasakti/passgen/internal/domain/entities"
 	"github.com/kumarasakti/passgen/internal/domain/services"
 )
 
 // GeneratePasswordRequest represents a request to generate passwords
 type GeneratePasswordRequest struct {
-	Config entities.PasswordConfig
+	Config      entities.PasswordConfig
+	Attacks     []entities.AttackScenario   // optional; defaults to entities.DefaultAttackScenarios
+	BannedWords *entities.BannedWordMatcher // optional; passwords containing these are regenerated
+	Standard    string                      // optional; audits Config against a standard such as services.StandardNIST80063B
+	MFA         bool                        // the passwords are one factor of multi-factor authentication
+	Profile     string                      // optional compliance profile every password must satisfy
 }
 
 // GeneratePasswordResponse represents the response from password generation
 type GeneratePasswordResponse struct {
-	Passwords []entities.Password
-	Analyses  []services.PasswordAnalysis
+	Passwords    []entities.Password
+	Analyses     []services.PasswordAnalysis
+	PolicyChecks []services.ComplianceCheck  // where Config falls short of the requested standard
+	Profile      *entities.ComplianceProfile // the profile the passwords satisfy, if one was requested
 }
 
 // GenerateWordPasswordRequest represents a request to generate word-based passwords
 type GenerateWordPasswordRequest struct {
-	Word       string
-	Strategy   entities.TransformationStrategy
-	Complexity entities.ComplexityLevel
-	Count      int
+	Word        string
+	Strategy    entities.TransformationStrategy
+	Complexity  entities.ComplexityLevel
+	Count       int
+	BannedWords *entities.BannedWordMatcher // optional; passwords containing these are regenerated
 }
 
 // GenerateWordPasswordResponse represents the response from word-based password generation
@@ -33,30 +43,138 @@ type GenerateWordPasswordResponse struct {
 
 // CheckPasswordRequest represents a request to check password strength
 type CheckPasswordRequest struct {
-	Password string
+	Password      string
+	Attacks       []entities.AttackScenario   // optional; defaults to entities.DefaultAttackScenarios
+	BreachLookups []BreachLookup              // optional breach corpora to consult
+	BannedWords   *entities.BannedWordMatcher // optional context-specific words to reject
+	UserContext   entities.UserContext        // optional details of the account holder
+	Standard      string                      // optional; also evaluates the password against a standard
+	MFA           bool                        // the password is one factor of multi-factor authentication
+	Profile       string                      // optional; evaluates the password against a compliance profile
+	Estimators    []string                    // optional strength estimators to also rate the password with
+	Aggregation   string                      // how to combine the estimators' ratings; defaults to services.AggregateMin
+}
+
+// BreachLookup checks a password against a breach corpus
+type BreachLookup interface {
+	Lookup(password string) (entities.BreachResult, error)
 }
 
 // CheckPasswordResponse represents the response from password strength checking
 type CheckPasswordResponse struct {
-	Result services.StrengthCheckResult
+	Result     services.StrengthCheckResult
+	Estimate   services.GuessEstimate
+	CrackTimes []services.CrackTimeEstimate
+	Compliance *services.ComplianceResult // set when a standard or profile was requested
+	Estimates  []services.StrengthEstimate
+	Combined   entities.PasswordStrength  // the estimates' combined rating, when estimators were requested
+	Patterns   []entities.PasswordPattern // recognized patterns, each with the characters it spans
+}
+
+// ExplainPasswordRequest represents a request to explain a password's ratings
+type ExplainPasswordRequest struct {
+	Password    string
+	BannedWords *entities.BannedWordMatcher // optional context-specific words to reject
+	UserContext entities.UserContext        // optional details of the account holder
+	Estimators  []string                    // optional strength estimators whose factors to add
+	Aggregation string                      // how to combine the estimators' ratings; defaults to services.AggregateMin
+}
+
+// ExplainPasswordResponse holds the factors behind each of a password's ratings
+type ExplainPasswordResponse struct {
+	Result      services.StrengthCheckResult // checklist score; its factors add up to the score
+	Analysis    services.PasswordAnalysis    // entropy for the character types present; its factors add up to the bits
+	Estimate    services.GuessEstimate       // the cheapest decomposition into patterns, covering every character
+	Estimates   []services.StrengthEstimate  // from the requested estimators, each with its own factors
+	Combined    entities.PasswordStrength    // the estimates' combined rating, when estimators were requested
+	Aggregation string                       // how Combined was combined
+	Patterns    []entities.PasswordPattern   // recognized patterns, each with the characters it spans
+}
+
+// AuditPolicyRequest represents a request to audit a password policy against a standard
+type AuditPolicyRequest struct {
+	Policy   entities.PasswordPolicy
+	Standard string // defaults to services.StandardNIST80063B
+	MFA      bool   // the policy governs one factor of multi-factor authentication
+}
+
+// AuditPolicyResponse represents the response from a policy audit
+type AuditPolicyResponse struct {
+	Standard  string
+	Checks    []services.ComplianceCheck
+	Compliant bool // no check failed
+}
+
+// AuditPasswordsRequest represents a request to audit a batch of passwords
+type AuditPasswordsRequest struct {
+	Entries     []services.AuditEntry
+	Top         int                         // worst offenders to report; defaults to services.DefaultAuditTop
+	Display     services.PasswordDisplay    // how the report shows passwords; masked by default
+	BannedWords *entities.BannedWordMatcher // optional context-specific words to flag
+}
+
+// AuditPasswordsResponse represents the response from a password audit
+type AuditPasswordsResponse struct {
+	Report services.AuditReport
 }
 
+// ScanSecretsRequest represents a request to look for hardcoded secrets
+type ScanSecretsRequest struct {
+	Lines   []services.ScanLine
+	Display services.PasswordDisplay // how findings show secrets; masked by default
+}
+
+// ScanSecretsResponse represents the response from a secret scan
+type ScanSecretsResponse struct {
+	Findings []services.SecretFinding
+}
+
+// ComparePasswordsRequest represents a request to compare two passwords
+type ComparePasswordsRequest struct {
+	First     string
+	Second    string
+	Threshold float64 // optional; defaults to services.DefaultSimilarityThreshold
+}
+
+// ComparePasswordsResponse represents the response from a password comparison
+type ComparePasswordsResponse struct {
+	Result services.SimilarityResult
+}
+
+// maxRegenerations bounds how often a password containing a banned word, or
+// missing a character type its profile requires, is regenerated before giving up
+const maxRegenerations = 100
+
 // PasswordService orchestrates password-related operations
 type PasswordService struct {
 	generator             *services.PasswordGenerator
 	analyzer              *services.PasswordAnalyzer
 	strengthChecker       *services.PasswordStrengthChecker
 	wordPasswordGenerator *services.WordPasswordGenerator
+	guessEstimator        *services.GuessEstimator
+	similarityEngine      *services.PasswordSimilarityEngine
+	nistChecker           *services.NISTComplianceChecker
+	policyChecker         *services.PolicyChecker
+	profiles              *services.ProfileLibrary
+	estimators            *services.EstimatorRegistry
 }
 
 // NewPasswordService creates a new PasswordService instance
 func NewPasswordService() *PasswordService {
 	analyzer := services.NewPasswordAnalyzer()
+	guessEstimator := services.NewGuessEstimator()
+	strengthChecker := services.NewPasswordStrengthChecker()
 	return &PasswordService{
 		generator:             services.NewPasswordGenerator(),
 		analyzer:              analyzer,
-		strengthChecker:       services.NewPasswordStrengthChecker(),
+		strengthChecker:       strengthChecker,
 		wordPasswordGenerator: services.NewWordPasswordGenerator(analyzer),
+		guessEstimator:        guessEstimator,
+		similarityEngine:      services.NewPasswordSimilarityEngine(),
+		nistChecker:           services.NewNISTComplianceChecker(guessEstimator),
+		policyChecker:         services.NewPolicyChecker(guessEstimator),
+		profiles:              services.NewProfileLibrary(),
+		estimators:            services.NewEstimatorRegistry(strengthChecker, analyzer, guessEstimator),
 	}
 }
 
@@ -65,31 +183,297 @@ func (ps *PasswordService) GeneratePasswords(req GeneratePasswordRequest) (Gener
 	if err := req.Config.Validate(); err != nil {
 		return GeneratePasswordResponse{}, err
 	}
+	if err := validateStandard(req.Standard); err != nil {
+		return GeneratePasswordResponse{}, err
+	}
+
+	var profile *entities.ComplianceProfile
+	if req.Profile != "" {
+		found, err := ps.profiles.Get(req.Profile)
+		if err != nil {
+			return GeneratePasswordResponse{}, err
+		}
+		if err := found.CheckConfig(req.Config); err != nil {
+			return GeneratePasswordResponse{}, err
+		}
+		profile = &found
+	}
+	rejected := func(password string) bool {
+		if len(req.BannedWords.Match(password)) > 0 {
+			return true
+		}
+		return profile != nil && !ps.policyChecker.Satisfies(password, *profile)
+	}
 
 	passwords, err := ps.generator.GenerateMultiplePasswords(req.Config)
 	if err != nil {
 		return GeneratePasswordResponse{}, err
 	}
 
+	for i := range passwords {
+		for attempt := 0; rejected(passwords[i].Value); attempt++ {
+			if attempt == maxRegenerations {
+				return GeneratePasswordResponse{}, entities.NewPasswordError("could not generate a password free of banned words that satisfies the profile; try a longer or different character set")
+			}
+			if passwords[i], err = ps.generator.GeneratePassword(req.Config); err != nil {
+				return GeneratePasswordResponse{}, err
+			}
+		}
+	}
+
 	analyses := make([]services.PasswordAnalysis, len(passwords))
 	for i, password := range passwords {
-		analyses[i] = ps.analyzer.AnalyzePassword(password, req.Config)
+		analyses[i] = ps.analyzer.AnalyzeGeneratedPassword(password, req.Config)
+		if len(req.Attacks) > 0 {
+			analyses[i].CrackTimes = ps.analyzer.EstimateCrackTimes(analyses[i].Guesses, req.Attacks)
+		}
 	}
 
-	return GeneratePasswordResponse{
+	resp := GeneratePasswordResponse{
 		Passwords: passwords,
 		Analyses:  analyses,
-	}, nil
+		Profile:   profile,
+	}
+	if req.Standard != "" {
+		resp.PolicyChecks = ps.nistChecker.AuditConfig(req.Config, req.MFA)
+	}
+	return resp, nil
 }
 
 // CheckPasswordStrength checks the strength of a given password
-func (ps *PasswordService) CheckPasswordStrength(req CheckPasswordRequest) CheckPasswordResponse {
+func (ps *PasswordService) CheckPasswordStrength(req CheckPasswordRequest) (CheckPasswordResponse, error) {
+	if err := validateStandard(req.Standard); err != nil {
+		return CheckPasswordResponse{}, err
+	}
+	if req.Standard != "" && req.Profile != "" {
+		return CheckPasswordResponse{}, entities.NewPasswordError("choose either a standard or a profile, not both")
+	}
+	var profile entities.ComplianceProfile
+	if req.Profile != "" {
+		var err error
+		if profile, err = ps.profiles.Get(req.Profile); err != nil {
+			return CheckPasswordResponse{}, err
+		}
+	}
+
+	// Every check sees the NFKC-normalized password, the form verifiers
+	// store and blocklists hold, so a password typed with fullwidth or other
+	// compatibility characters is rated as the one it stands for
+	password := entities.NewPassword(req.Password)
+	result := ps.strengthChecker.CheckPasswordStrength(password)
+
+	if len(req.BreachLookups) > 0 {
+		breaches := make([]entities.BreachResult, 0, len(req.BreachLookups))
+		for _, lookup := range req.BreachLookups {
+			breach, err := lookup.Lookup(password.Value)
+			if err != nil {
+				return CheckPasswordResponse{}, err
+			}
+			breaches = append(breaches, breach)
+		}
+		result = ps.strengthChecker.ApplyBreachResults(result, breaches)
+	}
+
+	result, userInputs := ps.applyAccountContext(result, password.Value, req.BannedWords, req.UserContext)
+	estimate := ps.guessEstimator.Estimate(password.Value, userInputs...)
+
+	attacks := req.Attacks
+	if len(attacks) == 0 {
+		attacks = entities.DefaultAttackScenarios()
+	}
+
+	estimates, combined, err := ps.estimate(password, req.Estimators, req.Aggregation)
+	if err != nil {
+		return CheckPasswordResponse{}, err
+	}
+
+	resp := CheckPasswordResponse{
+		Result:     result,
+		Estimate:   estimate,
+		CrackTimes: ps.analyzer.EstimateCrackTimes(estimate.Guesses, attacks),
+		Estimates:  estimates,
+		Combined:   combined,
+		Patterns:   detectPatterns(password.Value, req.BannedWords),
+	}
+	switch {
+	case req.Standard != "":
+		compliance := ps.nistChecker.Check(password.Value, req.MFA, result, userInputs...)
+		resp.Compliance = &compliance
+	case req.Profile != "":
+		compliance := ps.policyChecker.Check(password.Value, profile, result, userInputs...)
+		resp.Compliance = &compliance
+	}
+	return resp, nil
+}
+
+// ExplainPassword rates a password every way passgen can, keeping the factors
+// behind each rating so they can be shown next to the characters they concern
+func (ps *PasswordService) ExplainPassword(req ExplainPasswordRequest) (ExplainPasswordResponse, error) {
 	password := entities.NewPassword(req.Password)
+	if password.IsEmpty() {
+		return ExplainPasswordResponse{}, entities.NewPasswordError("password cannot be empty")
+	}
+
 	result := ps.strengthChecker.CheckPasswordStrength(password)
+	result, userInputs := ps.applyAccountContext(result, password.Value, req.BannedWords, req.UserContext)
+
+	aggregation := aggregationOrDefault(req.Aggregation)
+	estimates, combined, err := ps.estimate(password, req.Estimators, aggregation)
+	if err != nil {
+		return ExplainPasswordResponse{}, err
+	}
+
+	return ExplainPasswordResponse{
+		Result:      result,
+		Analysis:    ps.analyzer.AnalyzePassword(password, password.ObservedConfig()),
+		Estimate:    ps.guessEstimator.Estimate(password.Value, userInputs...),
+		Estimates:   estimates,
+		Combined:    combined,
+		Aggregation: aggregation,
+		Patterns:    detectPatterns(password.Value, req.BannedWords),
+	}, nil
+}
+
+// detectPatterns finds sequences, repeats, mirrored segments, keyboard walks,
+// words and dates in a password, including any banned words
+func detectPatterns(password string, banned *entities.BannedWordMatcher) []entities.PasswordPattern {
+	return entities.NewPasswordPatternDetector().SetBannedWords(banned).DetectPatterns(password)
+}
+
+// applyAccountContext records banned words and resemblances to the account
+// holder on a result, returning the words a targeted attacker would try first
+func (ps *PasswordService) applyAccountContext(result services.StrengthCheckResult, password string, banned *entities.BannedWordMatcher, context entities.UserContext) (services.StrengthCheckResult, []string) {
+	var userInputs []string
+	if banned != nil {
+		result = ps.strengthChecker.ApplyBannedWords(result, banned.Match(password))
+		userInputs = banned.Words()
+	}
+
+	if !context.IsEmpty() {
+		result = ps.strengthChecker.ApplyUserContext(result, context.Match(password))
+		for _, values := range context.Tokens() {
+			userInputs = append(userInputs, values...)
+		}
+	}
+	return result, userInputs
+}
+
+// AuditPolicy lists where a password policy conflicts with a standard
+func (ps *PasswordService) AuditPolicy(req AuditPolicyRequest) (AuditPolicyResponse, error) {
+	standard := req.Standard
+	if standard == "" {
+		standard = services.StandardNIST80063B
+	}
+	if err := validateStandard(standard); err != nil {
+		return AuditPolicyResponse{}, err
+	}
+
+	resp := AuditPolicyResponse{Standard: standard, Checks: ps.nistChecker.AuditPolicy(req.Policy, req.MFA), Compliant: true}
+	for _, check := range resp.Checks {
+		if check.Status == services.ComplianceStatusFail {
+			resp.Compliant = false
+		}
+	}
+	return resp, nil
+}
+
+// AddProfiles makes additional compliance profiles available by name,
+// replacing built-in profiles of the same name
+func (ps *PasswordService) AddProfiles(profiles ...entities.ComplianceProfile) {
+	ps.profiles.Add(profiles...)
+}
+
+// GetProfile returns the compliance profile with the given name
+func (ps *PasswordService) GetProfile(name string) (entities.ComplianceProfile, error) {
+	return ps.profiles.Get(name)
+}
+
+// ProfileNames lists the available compliance profiles
+func (ps *PasswordService) ProfileNames() []string {
+	return ps.profiles.Names()
+}
+
+// RegisterEstimators makes additional strength estimators available by name.
+// Names of built-in estimators are refused.
+func (ps *PasswordService) RegisterEstimators(estimators ...services.StrengthEstimator) error {
+	return ps.estimators.Register(estimators...)
+}
 
-	return CheckPasswordResponse{
-		Result: result,
+// UseGuessModel makes the pcfg estimator rate with a trained model
+func (ps *PasswordService) UseGuessModel(model *services.PCFGModel) {
+	ps.estimators.UsePCFGModel(model)
+}
+
+// EstimatorNames lists the available strength estimators
+func (ps *PasswordService) EstimatorNames() []string {
+	return ps.estimators.Names()
+}
+
+// estimate rates a password with the named estimators and combines their
+// ratings; it returns nothing when no estimators were named
+func (ps *PasswordService) estimate(password entities.Password, names []string, aggregation string) ([]services.StrengthEstimate, entities.PasswordStrength, error) {
+	if len(names) == 0 {
+		return nil, entities.VeryWeak, nil
+	}
+	estimates, err := ps.estimators.EstimateAll(password, names)
+	if err != nil {
+		return nil, entities.VeryWeak, err
+	}
+	combined, err := services.CombineStrengths(estimates, aggregationOrDefault(aggregation))
+	if err != nil {
+		return nil, entities.VeryWeak, err
 	}
+	return estimates, combined, nil
+}
+
+// aggregationOrDefault returns the named way of combining estimator
+// ratings, or services.AggregateMin when none was named
+func aggregationOrDefault(aggregation string) string {
+	if aggregation == "" {
+		return services.AggregateMin
+	}
+	return aggregation
+}
+
+// validateStandard rejects compliance standards other than those supported.
+// An empty name means no standard was requested.
+func validateStandard(name string) error {
+	switch name {
+	case "", services.StandardNIST80063B:
+		return nil
+	default:
+		return entities.NewPasswordError("unknown standard: " + name + " (available: " + services.StandardNIST80063B + ")")
+	}
+}
+
+// AuditPasswords analyzes a batch of passwords and aggregates the findings
+func (ps *PasswordService) AuditPasswords(req AuditPasswordsRequest) (AuditPasswordsResponse, error) {
+	if len(req.Entries) == 0 {
+		return AuditPasswordsResponse{}, entities.NewPasswordError("no passwords to audit")
+	}
+
+	auditor := services.NewPasswordAuditor(ps.strengthChecker, ps.guessEstimator, ps.similarityEngine).SetBannedWords(req.BannedWords)
+	return AuditPasswordsResponse{Report: auditor.Audit(req.Entries, req.Top, req.Display)}, nil
+}
+
+// ScanSecrets finds candidate secrets in lines of text and rates each with
+// the password analyzer and the guess estimator
+func (ps *PasswordService) ScanSecrets(req ScanSecretsRequest) ScanSecretsResponse {
+	scanner := services.NewSecretScanner(ps.analyzer, ps.guessEstimator)
+	return ScanSecretsResponse{Findings: scanner.Scan(req.Lines, req.Display)}
+}
+
+// ComparePasswords reports how similar two passwords are
+func (ps *PasswordService) ComparePasswords(req ComparePasswordsRequest) (ComparePasswordsResponse, error) {
+	engine := ps.similarityEngine
+	if req.Threshold != 0 {
+		if req.Threshold < 0 || req.Threshold > 1 {
+			return ComparePasswordsResponse{}, entities.NewPasswordError("similarity threshold must be between 0 and 1")
+		}
+		engine = services.NewPasswordSimilarityEngine().SetThreshold(req.Threshold)
+	}
+
+	return ComparePasswordsResponse{Result: engine.Compare(req.First, req.Second)}, nil
 }
 
 // GeneratePresetPassword generates a password using predefined presets
@@ -132,6 +516,12 @@ func (ps *PasswordService) getPresetConfig(presetType string) (entities.Password
 
 // GenerateWordPasswords generates word-based passwords
 func (ps *PasswordService) GenerateWordPasswords(req GenerateWordPasswordRequest) (GenerateWordPasswordResponse, error) {
+	// A banned base word survives every transformation, so refuse it up front
+	if matches := req.BannedWords.Match(req.Word); len(matches) > 0 {
+		return GenerateWordPasswordResponse{}, entities.NewPasswordError(
+			fmt.Sprintf("word '%s' matches banned %s word '%s'", req.Word, matches[0].List, matches[0].Word))
+	}
+
 	// Create word pattern
 	pattern := entities.NewWordPattern(req.Word)
 
@@ -168,6 +558,17 @@ func (ps *PasswordService) GenerateWordPasswords(req GenerateWordPasswordRequest
 		}
 	}
 
+	for i := range passwords {
+		for attempt := 0; len(req.BannedWords.Match(passwords[i])) > 0; attempt++ {
+			if attempt == maxRegenerations {
+				return GenerateWordPasswordResponse{}, entities.NewPasswordError("could not generate a password free of banned words")
+			}
+			if passwords[i], err = ps.wordPasswordGenerator.GenerateWordPassword(pattern); err != nil {
+				return GenerateWordPasswordResponse{}, err
+			}
+		}
+	}
+
 	// Analyze each password
 	analyses := make([]services.PasswordAnalysis, len(passwords))
 	for i, password := range passwords {
@@ -4,8 +4,19 @@ import (
 	"testing"
 
 	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/domain/services"
 )
 
+// recordingLookup is a breach corpus that remembers what it was asked for
+type recordingLookup struct {
+	looked []string
+}
+
+func (rl *recordingLookup) Lookup(password string) (entities.BreachResult, error) {
+	rl.looked = append(rl.looked, password)
+	return entities.BreachResult{Source: "test"}, nil
+}
+
 func TestPasswordService_CreatePasswordService(t *testing.T) {
 	service := NewPasswordService()
 	if service == nil {
@@ -102,3 +113,64 @@ func TestGenerateWordPasswordResponse_Structure(t *testing.T) {
 		t.Errorf("Expected second password 'p@ssw0rd123', got %s", response.Passwords[1])
 	}
 }
+
+func TestPasswordService_ExplainPassword(t *testing.T) {
+	service := NewPasswordService()
+
+	resp, err := service.ExplainPassword(ExplainPasswordRequest{
+		Password:    "Acme-qwerty-2024",
+		BannedWords: entities.NewBannedWordMatcher(0, entities.NewBannedWordList("company", []string{"acme"})),
+	})
+	if err != nil {
+		t.Fatalf("ExplainPassword() unexpected error: %v", err)
+	}
+	if !resp.Result.Rejected() || len(resp.Result.Factors) == 0 {
+		t.Errorf("Result = %+v, want a rejected result with factors", resp.Result)
+	}
+	if len(resp.Analysis.Factors) == 0 || len(resp.Estimate.Sequence) == 0 {
+		t.Error("ExplainPassword() should include entropy factors and a guess decomposition")
+	}
+
+	if _, err := service.ExplainPassword(ExplainPasswordRequest{Password: "  "}); err == nil {
+		t.Error("expected error for an empty password")
+	}
+}
+
+func TestPasswordService_CheckPasswordStrength_NormalizesBreachLookups(t *testing.T) {
+	service := NewPasswordService()
+	lookup := &recordingLookup{}
+
+	// Fullwidth letters normalize to ASCII under NFKC
+	resp, err := service.CheckPasswordStrength(CheckPasswordRequest{
+		Password:      "ｈｕｎｔｅｒ２ correct horse",
+		Standard:      services.StandardNIST80063B,
+		BreachLookups: []BreachLookup{lookup},
+	})
+	if err != nil {
+		t.Fatalf("CheckPasswordStrength() unexpected error: %v", err)
+	}
+	if want := "hunter2 correct horse"; len(lookup.looked) != 1 || lookup.looked[0] != want || resp.Compliance.Normalized != want {
+		t.Errorf("looked up %q and normalized to %q, want both %q", lookup.looked, resp.Compliance.Normalized, want)
+	}
+}
+
+func TestPasswordService_CheckPasswordStrength_RatesTheNormalizedPassword(t *testing.T) {
+	service := NewPasswordService()
+	lookup := &recordingLookup{}
+
+	resp, err := service.CheckPasswordStrength(CheckPasswordRequest{
+		Password:      "ＡＣＭＥ-rocks-2024",
+		BreachLookups: []BreachLookup{lookup},
+		BannedWords:   entities.NewBannedWordMatcher(0, entities.NewBannedWordList("company", []string{"acme"})),
+	})
+	if err != nil {
+		t.Fatalf("CheckPasswordStrength() unexpected error: %v", err)
+	}
+	want := "ACME-rocks-2024"
+	if len(lookup.looked) != 1 || lookup.looked[0] != want || resp.Estimate.Password != want {
+		t.Errorf("looked up %q and estimated %q, want both %q", lookup.looked, resp.Estimate.Password, want)
+	}
+	if len(resp.Result.BannedWords) != 1 || resp.Result.BannedWords[0].Start != 0 || resp.Result.BannedWords[0].End != 3 {
+		t.Errorf("BannedWords = %+v, want 'acme' at 0-3", resp.Result.BannedWords)
+	}
+}
@@ -0,0 +1,222 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Attack scenario names accepted by ParseAttackScenario
+const (
+	AttackOnlineThrottled   = "online-throttled"
+	AttackOnlineUnthrottled = "online-unthrottled"
+	AttackOfflineSlow       = "offline-slow"
+	AttackOfflineFast       = "offline-fast"
+	AttackCustom            = "custom"
+)
+
+// Reference guessing rates. Slow hashes are quoted for a single cracking rig
+// at the given cost; fast hashes for an eight-GPU rig.
+const (
+	onlineThrottledRate   = 100.0 / 3600 // 100 guesses per hour
+	onlineUnthrottledRate = 10.0
+	bcryptReferenceCost   = 10
+	bcryptReferenceRate   = 1e4
+	argon2ReferenceTime   = 2
+	argon2ReferenceRate   = 1e3
+	defaultFastHashRate   = 1e12
+)
+
+// fastHashRates holds reference rates for unsalted fast hashes
+var fastHashRates = map[string]float64{
+	"md5":  1.3e12,
+	"ntlm": 2.3e12,
+	"sha1": 4.0e11,
+}
+
+// AttackScenario describes how quickly an attacker can test guesses, which
+// depends on how the credential is exposed and stored
+type AttackScenario struct {
+	Name             string
+	Description      string
+	GuessesPerSecond float64
+}
+
+// DefaultAttackScenarios returns the scenarios reported when none are selected
+func DefaultAttackScenarios() []AttackScenario {
+	scenarios := make([]AttackScenario, 0, 4)
+	for _, spec := range []string{AttackOnlineThrottled, AttackOnlineUnthrottled, AttackOfflineSlow, AttackOfflineFast} {
+		scenario, _ := ParseAttackScenario(spec)
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios
+}
+
+// ParseAttackScenario parses a scenario specification:
+//   - online-throttled                rate-limited login form (100 guesses/hour)
+//   - online-unthrottled              login endpoint without rate limiting (10 guesses/s)
+//   - offline-slow[:bcrypt[:COST]]    stolen bcrypt hashes (default cost 10)
+//   - offline-slow:argon2[:TIME]      stolen Argon2id hashes (19 MiB, default time cost 2)
+//   - offline-fast[:md5|ntlm|sha1]    stolen unsalted fast hashes on a GPU rig
+//   - custom:RATE                     any rate in guesses per second (e.g. custom:1e6)
+func ParseAttackScenario(spec string) (AttackScenario, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(spec)), ":")
+
+	switch parts[0] {
+	case AttackOnlineThrottled:
+		return AttackScenario{
+			Name:             AttackOnlineThrottled,
+			Description:      "Online attack against a rate-limited service (100 guesses/hour)",
+			GuessesPerSecond: onlineThrottledRate,
+		}, nil
+	case AttackOnlineUnthrottled:
+		return AttackScenario{
+			Name:             AttackOnlineUnthrottled,
+			Description:      "Online attack without rate limiting (10 guesses/second)",
+			GuessesPerSecond: onlineUnthrottledRate,
+		}, nil
+	case AttackOfflineSlow:
+		return parseSlowHashScenario(parts[1:])
+	case AttackOfflineFast:
+		if len(parts) == 1 {
+			return AttackScenario{
+				Name:             AttackOfflineFast,
+				Description:      fmt.Sprintf("Offline attack on fast unsalted hashes with a GPU rig (%.1e guesses/second)", defaultFastHashRate),
+				GuessesPerSecond: defaultFastHashRate,
+			}, nil
+		}
+		rate, ok := fastHashRates[parts[1]]
+		if !ok {
+			return AttackScenario{}, NewPasswordError("unknown fast hash algorithm: " + parts[1] + " (available: md5, ntlm, sha1)")
+		}
+		return AttackScenario{
+			Name:             AttackOfflineFast + ":" + parts[1],
+			Description:      fmt.Sprintf("Offline attack on %s hashes with a GPU rig (%.1e guesses/second)", strings.ToUpper(parts[1]), rate),
+			GuessesPerSecond: rate,
+		}, nil
+	case AttackCustom:
+		if len(parts) != 2 {
+			return AttackScenario{}, NewPasswordError("custom attack requires a rate, e.g. custom:1e6")
+		}
+		rate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || rate <= 0 || math.IsInf(rate, 0) {
+			return AttackScenario{}, NewPasswordError("invalid custom attack rate: " + parts[1])
+		}
+		return AttackScenario{
+			Name:             AttackCustom + ":" + parts[1],
+			Description:      fmt.Sprintf("Custom attack (%.3g guesses/second)", rate),
+			GuessesPerSecond: rate,
+		}, nil
+	default:
+		return AttackScenario{}, NewPasswordError("unknown attack scenario: " + spec +
+			" (available: online-throttled, online-unthrottled, offline-slow, offline-fast, custom:RATE)")
+	}
+}
+
+// parseSlowHashScenario handles the offline-slow variants
+func parseSlowHashScenario(args []string) (AttackScenario, error) {
+	algorithm := "bcrypt"
+	if len(args) > 0 {
+		algorithm = args[0]
+	}
+
+	switch algorithm {
+	case "bcrypt":
+		cost := bcryptReferenceCost
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 4 || parsed > 31 {
+				return AttackScenario{}, NewPasswordError("bcrypt cost must be between 4 and 31")
+			}
+			cost = parsed
+		}
+		// Each cost step doubles the work per guess
+		rate := bcryptReferenceRate * math.Pow(2, float64(bcryptReferenceCost-cost))
+		return AttackScenario{
+			Name:             fmt.Sprintf("%s:bcrypt:%d", AttackOfflineSlow, cost),
+			Description:      fmt.Sprintf("Offline attack on bcrypt hashes, cost %d (%.3g guesses/second)", cost, rate),
+			GuessesPerSecond: rate,
+		}, nil
+	case "argon2", "argon2id":
+		timeCost := argon2ReferenceTime
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				return AttackScenario{}, NewPasswordError("argon2 time cost must be a positive integer")
+			}
+			timeCost = parsed
+		}
+		rate := argon2ReferenceRate * float64(argon2ReferenceTime) / float64(timeCost)
+		return AttackScenario{
+			Name:             fmt.Sprintf("%s:argon2id:%d", AttackOfflineSlow, timeCost),
+			Description:      fmt.Sprintf("Offline attack on Argon2id hashes, time cost %d (%.3g guesses/second)", timeCost, rate),
+			GuessesPerSecond: rate,
+		}, nil
+	default:
+		return AttackScenario{}, NewPasswordError("unknown slow hash algorithm: " + algorithm + " (available: bcrypt, argon2)")
+	}
+}
+
+// ParseAttackScenarios parses several specifications, keeping their order
+func ParseAttackScenarios(specs []string) ([]AttackScenario, error) {
+	scenarios := make([]AttackScenario, 0, len(specs))
+	for _, spec := range specs {
+		if strings.TrimSpace(spec) == "" {
+			continue
+		}
+		scenario, err := ParseAttackScenario(spec)
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return scenarios, nil
+}
+
+// CrackDuration is an expected time to crack, expressed both in seconds and
+// in the largest unit that keeps the value readable
+type CrackDuration struct {
+	Seconds float64
+	Value   float64
+	Unit    string // "seconds", "minutes", "hours", "days" or "years"
+}
+
+// NewCrackDuration converts seconds into a CrackDuration
+func NewCrackDuration(seconds float64) CrackDuration {
+	const (
+		minute = 60.0
+		hour   = 60 * minute
+		day    = 24 * hour
+		year   = 365 * day
+	)
+
+	switch {
+	case seconds < minute:
+		return CrackDuration{Seconds: seconds, Value: seconds, Unit: "seconds"}
+	case seconds < hour:
+		return CrackDuration{Seconds: seconds, Value: seconds / minute, Unit: "minutes"}
+	case seconds < day:
+		return CrackDuration{Seconds: seconds, Value: seconds / hour, Unit: "hours"}
+	case seconds < year:
+		return CrackDuration{Seconds: seconds, Value: seconds / day, Unit: "days"}
+	default:
+		return CrackDuration{Seconds: seconds, Value: seconds / year, Unit: "years"}
+	}
+}
+
+// String renders the duration for display
+func (cd CrackDuration) String() string {
+	switch {
+	case cd.Seconds < 1:
+		return "Less than a second"
+	case cd.Unit != "years":
+		return fmt.Sprintf("%.1f %s", cd.Value, cd.Unit)
+	case cd.Value < 1000:
+		return fmt.Sprintf("%.1f years", cd.Value)
+	case cd.Value > 1e15:
+		return fmt.Sprintf("%.1e years", cd.Value)
+	default:
+		return fmt.Sprintf("%.0f years", cd.Value)
+	}
+}
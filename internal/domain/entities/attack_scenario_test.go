@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseAttackScenario(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantName string
+		wantRate float64
+		wantErr  bool
+	}{
+		{name: "throttled", spec: "online-throttled", wantName: "online-throttled", wantRate: 100.0 / 3600},
+		{name: "unthrottled", spec: "online-unthrottled", wantName: "online-unthrottled", wantRate: 10},
+		{name: "default slow hash", spec: "offline-slow", wantName: "offline-slow:bcrypt:10", wantRate: 1e4},
+		{name: "bcrypt cost", spec: "offline-slow:bcrypt:12", wantName: "offline-slow:bcrypt:12", wantRate: 2.5e3},
+		{name: "argon2", spec: "offline-slow:argon2:4", wantName: "offline-slow:argon2id:4", wantRate: 500},
+		{name: "default fast hash", spec: "offline-fast", wantName: "offline-fast", wantRate: 1e12},
+		{name: "ntlm", spec: "OFFLINE-FAST:NTLM", wantName: "offline-fast:ntlm", wantRate: 2.3e12},
+		{name: "custom", spec: "custom:1e6", wantName: "custom:1e6", wantRate: 1e6},
+		{name: "bad bcrypt cost", spec: "offline-slow:bcrypt:99", wantErr: true},
+		{name: "bad fast hash", spec: "offline-fast:crc32", wantErr: true},
+		{name: "custom without rate", spec: "custom", wantErr: true},
+		{name: "negative custom rate", spec: "custom:-5", wantErr: true},
+		{name: "unknown", spec: "quantum", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenario, err := ParseAttackScenario(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttackScenario(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if scenario.Name != tt.wantName {
+				t.Errorf("Name = %v, want %v", scenario.Name, tt.wantName)
+			}
+			if math.Abs(scenario.GuessesPerSecond-tt.wantRate) > tt.wantRate*1e-9 {
+				t.Errorf("GuessesPerSecond = %v, want %v", scenario.GuessesPerSecond, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestNewCrackDuration(t *testing.T) {
+	tests := []struct {
+		seconds    float64
+		wantUnit   string
+		wantValue  float64
+		wantString string
+	}{
+		{seconds: 0.5, wantUnit: "seconds", wantValue: 0.5, wantString: "Less than a second"},
+		{seconds: 90, wantUnit: "minutes", wantValue: 1.5, wantString: "1.5 minutes"},
+		{seconds: 7200, wantUnit: "hours", wantValue: 2, wantString: "2.0 hours"},
+		{seconds: 3 * 86400, wantUnit: "days", wantValue: 3, wantString: "3.0 days"},
+		{seconds: 5 * 31536000, wantUnit: "years", wantValue: 5, wantString: "5.0 years"},
+		{seconds: 2e22 * 31536000, wantUnit: "years", wantValue: 2e22, wantString: "2.0e+22 years"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wantString, func(t *testing.T) {
+			duration := NewCrackDuration(tt.seconds)
+			if duration.Unit != tt.wantUnit {
+				t.Errorf("Unit = %v, want %v", duration.Unit, tt.wantUnit)
+			}
+			if math.Abs(duration.Value-tt.wantValue) > tt.wantValue*1e-9 {
+				t.Errorf("Value = %v, want %v", duration.Value, tt.wantValue)
+			}
+			if duration.String() != tt.wantString {
+				t.Errorf("String() = %v, want %v", duration.String(), tt.wantString)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package entities
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Banned word matching limits
+const (
+	minBannedWordLength = 3  // shorter entries match too much to be useful
+	minFuzzyWordLength  = 5  // shorter words are only matched exactly
+	maxLeetVariants     = 64 // cap on readings produced for ambiguous substitutions
+)
+
+// defaultCommonWords are the words every password is checked against
+var defaultCommonWords = []string{
+	"password", "admin", "user", "login", "welcome", "qwerty",
+	"letmein", "monkey", "dragon", "master", "shadow", "123456",
+	"password123", "admin123", "root", "toor", "guest",
+}
+
+// BannedWordList is a named list of context-specific words, such as company,
+// product or city names, that passwords must not contain
+type BannedWordList struct {
+	Name  string
+	Words []string
+}
+
+// NewBannedWordList creates a list, lowercasing words and dropping blanks,
+// duplicates and entries too short to match meaningfully
+func NewBannedWordList(name string, words []string) BannedWordList {
+	seen := make(map[string]bool, len(words))
+	list := BannedWordList{Name: name}
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if len([]rune(word)) < minBannedWordLength || seen[word] {
+			continue
+		}
+		seen[word] = true
+		list.Words = append(list.Words, word)
+	}
+	return list
+}
+
+// ParseBannedWordList reads one word per line, ignoring blank lines and
+// lines starting with '#'
+func ParseBannedWordList(name string, r io.Reader) (BannedWordList, error) {
+	var words []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return BannedWordList{}, fmt.Errorf("reading banned list %s: %w", name, err)
+	}
+	return NewBannedWordList(name, words), nil
+}
+
+// DefaultBannedWordList returns the built-in list of common password words
+func DefaultBannedWordList() BannedWordList {
+	return NewBannedWordList("common", defaultCommonWords)
+}
+
+// BannedWordMatch is an occurrence of a banned word in a password
+type BannedWordMatch struct {
+	List     string // name of the list the word came from
+	Word     string // the banned word
+	Token    string // the part of the password that matched
+	Start    int    // rune index of the first matched character
+	End      int    // rune index of the last matched character (inclusive)
+	Distance int    // edit distance between Token and Word after normalization
+	Leet     bool   // whether Token only matched after undoing l33t substitutions
+}
+
+// BannedWordMatcher finds banned words in passwords after lowercasing,
+// undoing l33t substitutions and allowing a small edit distance
+type BannedWordMatcher struct {
+	lists       []BannedWordList
+	maxDistance int
+}
+
+// NewBannedWordMatcher creates a matcher over lists. Words of at least five
+// characters also match with up to maxDistance edits; zero disables fuzzy
+// matching.
+func NewBannedWordMatcher(maxDistance int, lists ...BannedWordList) *BannedWordMatcher {
+	if maxDistance < 0 {
+		maxDistance = 0
+	}
+	return &BannedWordMatcher{lists: lists, maxDistance: maxDistance}
+}
+
+// Lists returns the banned word lists the matcher checks
+func (m *BannedWordMatcher) Lists() []BannedWordList {
+	return m.lists
+}
+
+// Words returns every banned word across all lists
+func (m *BannedWordMatcher) Words() []string {
+	var words []string
+	for _, list := range m.lists {
+		words = append(words, list.Words...)
+	}
+	return words
+}
+
+// Match returns the banned words found in password, at most one per list and
+// word (the closest occurrence), ordered by position
+func (m *BannedWordMatcher) Match(password string) []BannedWordMatch {
+	if m == nil || password == "" {
+		return nil
+	}
+
+	original := []rune(password)
+	lower := make([]rune, len(original))
+	for i, r := range original {
+		lower[i] = unicode.ToLower(r)
+	}
+	inverse := leetSubstitutions()
+
+	var matches []BannedWordMatch
+	for _, list := range m.lists {
+		for _, word := range list.Words {
+			start, end, distance, ok := m.find(lower, []rune(word), inverse)
+			if !ok {
+				continue
+			}
+			matches = append(matches, BannedWordMatch{
+				List:     list.Name,
+				Word:     word,
+				Token:    string(original[start : end+1]),
+				Start:    start,
+				End:      end,
+				Distance: distance,
+				Leet:     string(lower[start:end+1]) != word && usesLeet(lower[start:end+1], inverse),
+			})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// find locates word in text exactly or, for long enough words, within the
+// matcher's edit distance, preferring the closest and then earliest window.
+// A l33t substitute counts as equal to any letter it may stand for.
+func (m *BannedWordMatcher) find(text, word []rune, inverse map[rune][]rune) (start, end, distance int, ok bool) {
+	for i := 0; i+len(word) <= len(text); i++ {
+		if leetMatches(text[i:i+len(word)], word, inverse) {
+			return i, i + len(word) - 1, 0, true
+		}
+	}
+
+	maxDistance := m.maxDistance
+	if len(word) < minFuzzyWordLength || maxDistance == 0 {
+		return 0, 0, 0, false
+	}
+
+	// Windows closest to the word's length win ties
+	distance = maxDistance + 1
+	for offset := 0; offset <= 2*maxDistance; offset++ {
+		size := len(word) + (offset+1)/2
+		if offset%2 == 1 {
+			size = len(word) - (offset+1)/2
+		}
+		if size < minBannedWordLength || size > len(text) {
+			continue
+		}
+		for i := 0; i+size <= len(text); i++ {
+			if d := leetDistance(text[i:i+size], word, inverse); d < distance {
+				start, end, distance = i, i+size-1, d
+			}
+		}
+	}
+	return start, end, distance, distance <= maxDistance
+}
+
+// usesLeet reports whether token contains any l33t substitute
+func usesLeet(token []rune, inverse map[rune][]rune) bool {
+	for _, r := range token {
+		if _, ok := inverse[r]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// leetEqual reports whether a password rune reads as a word letter
+func leetEqual(r, letter rune, inverse map[rune][]rune) bool {
+	if r == letter {
+		return true
+	}
+	for _, candidate := range inverse[r] {
+		if candidate == letter {
+			return true
+		}
+	}
+	return false
+}
+
+// leetSubstitutions inverts the default l33t mappings; a substitute shared by
+// several letters (1 for i and l) maps back to all of them
+func leetSubstitutions() map[rune][]rune {
+	inverse := make(map[rune][]rune)
+	for letter, substitute := range getDefaultLeetSpeakMappings() {
+		inverse[substitute] = append(inverse[substitute], letter)
+	}
+	for _, letters := range inverse {
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	}
+	return inverse
+}
+
+// NormalizeLeet returns the lowercase readings of s with l33t substitutions
+// undone ("p@$$w0rd" becomes "password"). A substitute shared by several
+// letters yields one reading per letter ("1ce" reads as "ice" and "lce"), up
+// to a fixed number of readings.
+func NormalizeLeet(s string) []string {
+	inverse := leetSubstitutions()
+	readings := [][]rune{nil}
+
+	for _, r := range strings.ToLower(s) {
+		letters, ok := inverse[r]
+		if !ok {
+			letters = []rune{r}
+		}
+		expanded := make([][]rune, 0, len(readings)*len(letters))
+		for j, reading := range readings {
+			for k, letter := range letters {
+				// Alternatives only while every remaining reading still fits
+				if k > 0 && len(expanded)+len(readings)-j-1 >= maxLeetVariants {
+					break
+				}
+				expanded = append(expanded, append(append([]rune(nil), reading...), letter))
+			}
+		}
+		readings = expanded
+	}
+
+	normalized := make([]string, len(readings))
+	for i, reading := range readings {
+		normalized[i] = string(reading)
+	}
+	return normalized
+}
+
+// EditDistance returns the Levenshtein distance between a and b in runes
+func EditDistance(a, b string) int {
+	return leetDistance([]rune(a), []rune(b), nil)
+}
+
+// leetMatches reports whether token reads as word, treating l33t substitutes
+// as equal to their letters
+func leetMatches(token, word []rune, inverse map[rune][]rune) bool {
+	if len(token) != len(word) {
+		return false
+	}
+	for i := range token {
+		if !leetEqual(token[i], word[i], inverse) {
+			return false
+		}
+	}
+	return true
+}
+
+// leetDistance returns the Levenshtein distance between token and word,
+// treating l33t substitutes as equal to their letters
+func leetDistance(token, word []rune, inverse map[rune][]rune) int {
+	previous := make([]int, len(word)+1)
+	current := make([]int, len(word)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(token); i++ {
+		current[0] = i
+		for j := 1; j <= len(word); j++ {
+			cost := 1
+			if leetEqual(token[i-1], word[j-1], inverse) {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(word)]
+}
+
+// min3 returns the smallest of three ints
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
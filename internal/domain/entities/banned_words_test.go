@@ -0,0 +1,107 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBannedWordMatcher_Match(t *testing.T) {
+	company := NewBannedWordList("company", []string{"Acme", "Springfield", "widgetron", "hq"})
+	matcher := NewBannedWordMatcher(1, company)
+
+	tests := []struct {
+		name     string
+		password string
+		word     string
+		token    string
+		distance int
+		leet     bool
+	}{
+		{"exact", "acme-rocks", "acme", "acme", 0, false},
+		{"case insensitive", "ACMErocks", "acme", "ACME", 0, false},
+		{"leet", "@cm3Rocks!", "acme", "@cm3", 0, true},
+		{"fuzzy deletion", "Sprngfield#99", "springfield", "Sprngfield", 1, false},
+		{"fuzzy leet", "W1dgetr0m2024", "widgetron", "W1dgetr0m", 1, true},
+		{"none", "kD8#mQ2$vL9!xR4@", "", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := matcher.Match(tt.password)
+			if tt.word == "" {
+				if len(matches) != 0 {
+					t.Errorf("Match(%q) = %+v, want no matches", tt.password, matches)
+				}
+				return
+			}
+			if len(matches) != 1 {
+				t.Fatalf("Match(%q) = %+v, want one match", tt.password, matches)
+			}
+			match := matches[0]
+			if match.List != "company" || match.Word != tt.word || match.Token != tt.token ||
+				match.Distance != tt.distance || match.Leet != tt.leet {
+				t.Errorf("Match(%q) = %+v, want word %q token %q distance %d leet %v",
+					tt.password, match, tt.word, tt.token, tt.distance, tt.leet)
+			}
+		})
+	}
+}
+
+func TestBannedWordMatcher_ShortWordsMatchExactly(t *testing.T) {
+	matcher := NewBannedWordMatcher(2, NewBannedWordList("company", []string{"acme"}))
+
+	if matches := matcher.Match("acne-clinic"); len(matches) != 0 {
+		t.Errorf("Short words should not match fuzzily, got %+v", matches)
+	}
+	if list := NewBannedWordList("company", []string{"hq", "  ", "Acme", "acme"}); len(list.Words) != 1 {
+		t.Errorf("NewBannedWordList() words = %v, want only 'acme'", list.Words)
+	}
+}
+
+func TestParseBannedWordList(t *testing.T) {
+	input := "# products\nWidgetron\n\n  Gizmotron  \n"
+	list, err := ParseBannedWordList("products", strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseBannedWordList() error = %v", err)
+	}
+	if strings.Join(list.Words, ",") != "widgetron,gizmotron" {
+		t.Errorf("ParseBannedWordList() words = %v", list.Words)
+	}
+}
+
+func TestNormalizeLeet(t *testing.T) {
+	if got := NormalizeLeet("P@$$w0rd"); len(got) != 1 || got[0] != "password" {
+		t.Errorf("NormalizeLeet(P@$$w0rd) = %v, want [password]", got)
+	}
+
+	// 1 stands for both i and l
+	got := NormalizeLeet("1ce")
+	if len(got) != 2 || got[0] != "ice" || got[1] != "lce" {
+		t.Errorf("NormalizeLeet(1ce) = %v, want [ice lce]", got)
+	}
+
+	if got := NormalizeLeet(strings.Repeat("1", 20)); len(got) > maxLeetVariants {
+		t.Errorf("NormalizeLeet() returned %d readings, want at most %d", len(got), maxLeetVariants)
+	}
+}
+
+func TestPasswordPatternDetector_BannedWords(t *testing.T) {
+	detector := NewPasswordPatternDetector()
+
+	var common bool
+	for _, pattern := range detector.DetectPatterns("P@ssw0rd") {
+		common = common || pattern.Type == "common_word"
+	}
+	if !common {
+		t.Error("l33t-spelled common word should be detected")
+	}
+
+	detector.SetBannedWords(NewBannedWordMatcher(1, NewBannedWordList("city", []string{"springfield"})))
+	var banned bool
+	for _, pattern := range detector.DetectPatterns("Spr1ngf1eld!") {
+		banned = banned || pattern.Type == "banned_word"
+	}
+	if !banned {
+		t.Error("banned word should be detected once configured")
+	}
+}
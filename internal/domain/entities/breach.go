@@ -0,0 +1,31 @@
+package entities
+
+import "strings"
+
+// BreachHashType identifies how passwords are hashed in a breach corpus
+type BreachHashType string
+
+const (
+	BreachHashSHA1 BreachHashType = "sha1"
+	BreachHashNTLM BreachHashType = "ntlm"
+)
+
+// ParseBreachHashType validates a breach hash type name
+func ParseBreachHashType(name string) (BreachHashType, error) {
+	switch BreachHashType(strings.ToLower(strings.TrimSpace(name))) {
+	case BreachHashSHA1:
+		return BreachHashSHA1, nil
+	case BreachHashNTLM:
+		return BreachHashNTLM, nil
+	default:
+		return "", NewPasswordError("unknown breach hash type: " + name + " (available: sha1, ntlm)")
+	}
+}
+
+// BreachResult reports whether a password appears in a breach corpus
+type BreachResult struct {
+	Source   string // human-readable corpus name, e.g. a file path or API URL
+	HashType BreachHashType
+	Found    bool
+	Count    int64 // number of times the password was seen in breaches
+}
@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AttackCalibrated selects scenarios based on locally measured hash rates
+const AttackCalibrated = "calibrated"
+
+// HashRate is a measured hashing speed for one algorithm and cost setting
+type HashRate struct {
+	Algorithm       string  `json:"algorithm"`
+	Cost            int     `json:"cost,omitempty"`
+	HashesPerSecond float64 `json:"hashes_per_second"`
+}
+
+// Label identifies the rate, including its cost when it has one
+func (hr HashRate) Label() string {
+	if hr.Cost > 0 {
+		return fmt.Sprintf("%s:%d", hr.Algorithm, hr.Cost)
+	}
+	return hr.Algorithm
+}
+
+// Calibration holds hash rates measured on this machine, scaled by a
+// multiplier representing the attacker's hardware (GPUs, a cluster) relative
+// to it
+type Calibration struct {
+	MeasuredAt time.Time  `json:"measured_at"`
+	Multiplier float64    `json:"multiplier"`
+	Rates      []HashRate `json:"rates"`
+}
+
+// Scenarios returns one attack scenario per measured rate
+func (c *Calibration) Scenarios() []AttackScenario {
+	scenarios := make([]AttackScenario, 0, len(c.Rates))
+	for _, rate := range c.Rates {
+		scenarios = append(scenarios, c.scenario(rate))
+	}
+	return scenarios
+}
+
+// ParseAttackScenarios parses scenario specifications like the package-level
+// ParseAttackScenarios, additionally resolving calibrated[:ALGORITHM[:COST]]
+// against the measured rates
+func (c *Calibration) ParseAttackScenarios(specs []string) ([]AttackScenario, error) {
+	var scenarios []AttackScenario
+	for _, spec := range specs {
+		spec = strings.ToLower(strings.TrimSpace(spec))
+		if spec == "" {
+			continue
+		}
+		if spec != AttackCalibrated && !strings.HasPrefix(spec, AttackCalibrated+":") {
+			scenario, err := ParseAttackScenario(spec)
+			if err != nil {
+				return nil, err
+			}
+			scenarios = append(scenarios, scenario)
+			continue
+		}
+
+		if c == nil || len(c.Rates) == 0 {
+			return nil, NewPasswordError("no calibration data found; run 'passgen calibrate' first")
+		}
+		matched, err := c.match(strings.TrimPrefix(strings.TrimPrefix(spec, AttackCalibrated), ":"))
+		if err != nil {
+			return nil, err
+		}
+		scenarios = append(scenarios, matched...)
+	}
+	return scenarios, nil
+}
+
+// match returns scenarios for rates matching "algorithm[:cost]"; empty selects all
+func (c *Calibration) match(selector string) ([]AttackScenario, error) {
+	if selector == "" {
+		return c.Scenarios(), nil
+	}
+
+	parts := strings.SplitN(selector, ":", 2)
+	cost := 0
+	if len(parts) == 2 {
+		parsed, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, NewPasswordError("invalid calibrated cost: " + parts[1])
+		}
+		cost = parsed
+	}
+
+	var scenarios []AttackScenario
+	for _, rate := range c.Rates {
+		if rate.Algorithm == parts[0] && (cost == 0 || rate.Cost == cost) {
+			scenarios = append(scenarios, c.scenario(rate))
+		}
+	}
+	if len(scenarios) == 0 {
+		return nil, NewPasswordError("no calibrated rate for " + selector)
+	}
+	return scenarios, nil
+}
+
+// scenario converts a measured rate into an attack scenario
+func (c *Calibration) scenario(rate HashRate) AttackScenario {
+	multiplier := c.Multiplier
+	if multiplier <= 0 {
+		multiplier = 1
+	}
+	guessesPerSecond := rate.HashesPerSecond * multiplier
+
+	return AttackScenario{
+		Name: AttackCalibrated + ":" + rate.Label(),
+		Description: fmt.Sprintf("Offline attack on %s measured %s (x%g hardware, %.3g guesses/second)",
+			rate.Label(), c.MeasuredAt.Format("2006-01-02"), multiplier, guessesPerSecond),
+		GuessesPerSecond: guessesPerSecond,
+	}
+}
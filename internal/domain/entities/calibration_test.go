@@ -0,0 +1,81 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalibration_ParseAttackScenarios(t *testing.T) {
+	calibration := &Calibration{
+		MeasuredAt: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Multiplier: 100,
+		Rates: []HashRate{
+			{Algorithm: "bcrypt", Cost: 10, HashesPerSecond: 50},
+			{Algorithm: "bcrypt", Cost: 12, HashesPerSecond: 12.5},
+			{Algorithm: "md5", HashesPerSecond: 1e7},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		specs     []string
+		wantNames []string
+		wantRates []float64
+		wantErr   bool
+	}{
+		{
+			name:      "all calibrated rates",
+			specs:     []string{"calibrated"},
+			wantNames: []string{"calibrated:bcrypt:10", "calibrated:bcrypt:12", "calibrated:md5"},
+			wantRates: []float64{5000, 1250, 1e9},
+		},
+		{
+			name:      "algorithm and cost",
+			specs:     []string{"calibrated:bcrypt:12"},
+			wantNames: []string{"calibrated:bcrypt:12"},
+			wantRates: []float64{1250},
+		},
+		{
+			name:      "mixed with reference scenarios",
+			specs:     []string{"online-unthrottled", "calibrated:md5"},
+			wantNames: []string{"online-unthrottled", "calibrated:md5"},
+			wantRates: []float64{10, 1e9},
+		},
+		{
+			name:    "unmeasured algorithm",
+			specs:   []string{"calibrated:scrypt"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scenarios, err := calibration.ParseAttackScenarios(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAttackScenarios() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(scenarios) != len(tt.wantNames) {
+				t.Fatalf("ParseAttackScenarios() = %d scenarios, want %d", len(scenarios), len(tt.wantNames))
+			}
+			for i, scenario := range scenarios {
+				if scenario.Name != tt.wantNames[i] {
+					t.Errorf("Name = %v, want %v", scenario.Name, tt.wantNames[i])
+				}
+				if scenario.GuessesPerSecond != tt.wantRates[i] {
+					t.Errorf("GuessesPerSecond = %v, want %v", scenario.GuessesPerSecond, tt.wantRates[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCalibration_NilRequiresCalibrate(t *testing.T) {
+	var calibration *Calibration
+
+	if _, err := calibration.ParseAttackScenarios([]string{"offline-fast"}); err != nil {
+		t.Errorf("Reference scenarios should not need calibration data: %v", err)
+	}
+	if _, err := calibration.ParseAttackScenarios([]string{"calibrated"}); err == nil {
+		t.Error("Calibrated scenarios should fail without calibration data")
+	}
+}
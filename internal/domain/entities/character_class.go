@@ -0,0 +1,148 @@
+package entities
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// classNamePattern restricts class names to what is easy to type on a command line
+var classNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// builtinClasses are the Unicode sets available by name. Each lists only
+// single code points that NFC and NFKC leave unchanged.
+var builtinClasses = map[string]func() string{
+	// Latin-1 Supplement letters, without × and ÷
+	"latin1": func() string {
+		return runeRange(0xC0, 0xFF, 0xD7, 0xF7)
+	},
+	// Greek capitals (U+03A2 is unassigned) and small letters
+	"greek": func() string {
+		return runeRange(0x391, 0x3A9, 0x3A2) + runeRange(0x3B1, 0x3C9)
+	},
+	// Russian Cyrillic alphabet including Ё and ё
+	"cyrillic": func() string {
+		return runeRange(0x410, 0x44F) + "Ёё"
+	},
+	// Emoticons and animal faces that render as emoji without a variation
+	// selector (U+1F43F defaults to text presentation)
+	"emoji": func() string {
+		return runeRange(0x1F600, 0x1F64F) + runeRange(0x1F400, 0x1F43E)
+	},
+}
+
+// CharacterClass is a named set of characters to draw passwords from in
+// addition to the standard lowercase, uppercase, number and symbol sets
+type CharacterClass struct {
+	Name     string
+	Chars    string
+	Excluded []rune // characters dropped because they can't be typed back reliably
+}
+
+// NewCharacterClass creates a class from chars, dropping duplicates and any
+// character that NFC or NFKC normalization would change, since a password
+// containing it may not match after another system normalizes it. Combining
+// marks, control characters and whitespace are dropped for the same reason.
+func NewCharacterClass(name, chars string) CharacterClass {
+	class := CharacterClass{Name: name}
+	seen := make(map[rune]bool)
+	var kept strings.Builder
+	for _, r := range chars {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+
+		s := string(r)
+		if norm.NFC.String(s) != s || norm.NFKC.String(s) != s ||
+			unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) || unicode.IsControl(r) || unicode.IsSpace(r) {
+			class.Excluded = append(class.Excluded, r)
+			continue
+		}
+		kept.WriteRune(r)
+	}
+	class.Chars = kept.String()
+	return class
+}
+
+// BuiltinCharacterClassNames lists the Unicode sets available by name
+func BuiltinCharacterClassNames() []string {
+	names := make([]string, 0, len(builtinClasses))
+	for name := range builtinClasses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseCharacterClass reads a class specification: the name of a built-in
+// set such as "greek", or "name=characters" for a user-defined class
+func ParseCharacterClass(spec string) (CharacterClass, error) {
+	name, chars, custom := strings.Cut(spec, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if !custom {
+		build, ok := builtinClasses[name]
+		if !ok {
+			return CharacterClass{}, NewPasswordError(fmt.Sprintf(
+				"unknown character class %q (built-in: %s; define your own with name=characters)",
+				name, strings.Join(BuiltinCharacterClassNames(), ", ")))
+		}
+		return NewCharacterClass(name, build()), nil
+	}
+
+	if !classNamePattern.MatchString(name) {
+		return CharacterClass{}, NewPasswordError(fmt.Sprintf("invalid character class name %q: use lowercase letters, digits and hyphens", name))
+	}
+	class := NewCharacterClass(name, chars)
+	if class.Chars == "" {
+		return CharacterClass{}, NewPasswordError(fmt.Sprintf("character class %s has no usable characters", name))
+	}
+	return class, nil
+}
+
+// ParseCharacterClasses reads one class specification per line, ignoring
+// blank lines and lines starting with '#'
+func ParseCharacterClasses(r io.Reader) ([]CharacterClass, error) {
+	var classes []CharacterClass
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		class, err := ParseCharacterClass(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		classes = append(classes, class)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading character classes: %w", err)
+	}
+	return classes, nil
+}
+
+// runeRange returns the characters from first to last inclusive, leaving out skip
+func runeRange(first, last rune, skip ...rune) string {
+	var b strings.Builder
+	for r := first; r <= last; r++ {
+		skipped := false
+		for _, s := range skip {
+			if r == s {
+				skipped = true
+				break
+			}
+		}
+		if !skipped {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
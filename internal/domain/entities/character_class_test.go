@@ -0,0 +1,117 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestParseCharacterClass(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         string
+		wantName     string
+		wantChars    string
+		wantExcluded string
+		wantRunes    int
+		wantErr      bool
+	}{
+		{name: "greek", spec: "greek", wantName: "greek", wantRunes: 49},
+		{name: "built-in names ignore case", spec: "Cyrillic", wantName: "cyrillic", wantRunes: 66},
+		{name: "latin1", spec: "latin1", wantName: "latin1", wantRunes: 62},
+		{name: "custom", spec: "vowels=aeiouaei", wantName: "vowels", wantChars: "aeiou", wantRunes: 5},
+		{name: "drops combining and compatibility characters", spec: "mixed=é́ﬁ½x", wantName: "mixed", wantChars: "éx", wantExcluded: "́ﬁ½", wantRunes: 2},
+		{name: "drops decomposed-only characters", spec: "ohm=ΩΩ", wantName: "ohm", wantChars: "Ω", wantExcluded: "Ω", wantRunes: 1},
+		{name: "unknown built-in", spec: "klingon", wantErr: true},
+		{name: "invalid name", spec: "My Class=abc", wantErr: true},
+		{name: "nothing usable", spec: "marks=́̂", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, err := ParseCharacterClass(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseCharacterClass(%q) expected error, got %+v", tt.spec, class)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCharacterClass(%q) unexpected error: %v", tt.spec, err)
+			}
+			if class.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", class.Name, tt.wantName)
+			}
+			if tt.wantChars != "" && class.Chars != tt.wantChars {
+				t.Errorf("Chars = %q, want %q", class.Chars, tt.wantChars)
+			}
+			if got := utf8.RuneCountInString(class.Chars); got != tt.wantRunes {
+				t.Errorf("Chars has %d characters, want %d", got, tt.wantRunes)
+			}
+			if got := string(class.Excluded); got != tt.wantExcluded {
+				t.Errorf("Excluded = %q, want %q", got, tt.wantExcluded)
+			}
+		})
+	}
+}
+
+func TestBuiltinCharacterClasses_SurviveNormalization(t *testing.T) {
+	for _, name := range BuiltinCharacterClassNames() {
+		class, err := ParseCharacterClass(name)
+		if err != nil {
+			t.Fatalf("ParseCharacterClass(%q) unexpected error: %v", name, err)
+		}
+		if len(class.Excluded) > 0 {
+			t.Errorf("built-in class %s excludes %q", name, string(class.Excluded))
+		}
+		for _, r := range class.Chars {
+			if got := NewPassword(string(r)).Value; got != string(r) {
+				t.Errorf("class %s: %q becomes %q in a password", name, string(r), got)
+			}
+		}
+	}
+}
+
+func TestParseCharacterClasses(t *testing.T) {
+	input := "# extra sets\n\ngreek\nhex=0123456789abcdef\n"
+	classes, err := ParseCharacterClasses(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseCharacterClasses() unexpected error: %v", err)
+	}
+	if len(classes) != 2 || classes[0].Name != "greek" || classes[1].Chars != "0123456789abcdef" {
+		t.Errorf("ParseCharacterClasses() = %+v", classes)
+	}
+
+	_, err = ParseCharacterClasses(strings.NewReader("greek\nnope\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ParseCharacterClasses() error = %v, want one naming line 2", err)
+	}
+}
+
+func TestCharacterSet_ExtraClasses(t *testing.T) {
+	cs := NewCharacterSet()
+	greek, _ := ParseCharacterClass("greek")
+	overlap, _ := ParseCharacterClass("overlap=abcΩ")
+
+	config := PasswordConfig{Length: 12, IncludeLower: true, ExtraClasses: []CharacterClass{greek, overlap}, Count: 1}
+	charset, err := cs.BuildCharset(config)
+	if err != nil {
+		t.Fatalf("BuildCharset() unexpected error: %v", err)
+	}
+	if got, want := utf8.RuneCountInString(charset), 26+49; got != want {
+		t.Errorf("charset has %d characters, want %d unique", got, want)
+	}
+	if got := cs.CalculateCharsetSize(config); got != 26+49 {
+		t.Errorf("CalculateCharsetSize() = %d, want %d", got, 26+49)
+	}
+
+	// Extra classes alone are enough, and exclusions apply to them too
+	config = PasswordConfig{Length: 12, ExcludeChars: "αβ", ExtraClasses: []CharacterClass{greek}, Count: 1}
+	charset, err = cs.BuildCharset(config)
+	if err != nil {
+		t.Fatalf("BuildCharset() unexpected error: %v", err)
+	}
+	if strings.ContainsAny(charset, "αβ") || utf8.RuneCountInString(charset) != 47 {
+		t.Errorf("BuildCharset() = %q, want greek without α and β", charset)
+	}
+}
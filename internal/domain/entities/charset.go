@@ -1,6 +1,9 @@
 package entities
 
-import "strings"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // Character set constants
 const (
@@ -21,27 +24,39 @@ func NewCharacterSet() *CharacterSet {
 	return &CharacterSet{}
 }
 
-// BuildCharset builds a character set based on the provided configuration
+// BuildCharset builds a character set based on the provided configuration.
+// A character in several categories appears once, so each is equally likely.
 func (cs *CharacterSet) BuildCharset(config PasswordConfig) (string, error) {
 	categories, err := cs.BuildCategories(config)
 	if err != nil {
 		return "", err
 	}
 
-	charset := strings.Join(categories, "")
+	charset := uniqueRunes(strings.Join(categories, ""))
 
 	cs.charset = charset
 	return charset, nil
 }
 
 // BuildCategories returns individual character categories after applying exclusions.
-// Each enabled category (lowercase, uppercase, numbers, symbols) is returned as a
-// separate string with similar and explicitly excluded characters removed.
+// Each enabled category (lowercase, uppercase, numbers, symbols, then any extra
+// classes) is returned as a separate string with similar and explicitly
+// excluded characters removed.
 func (cs *CharacterSet) BuildCategories(config PasswordConfig) ([]string, error) {
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
 
+	categories := cs.categories(config)
+	if len(categories) == 0 {
+		return nil, NewPasswordError("no characters available after exclusions")
+	}
+
+	return categories, nil
+}
+
+// categories returns the non-empty categories of config without validating it
+func (cs *CharacterSet) categories(config PasswordConfig) []string {
 	applyExclusions := func(s string) string {
 		if config.ExcludeSimilar {
 			similar := "il1Lo0O"
@@ -70,6 +85,22 @@ func (cs *CharacterSet) BuildCategories(config PasswordConfig) ([]string, error)
 	if config.IncludeSymbols {
 		categories = append(categories, applyExclusions(Symbols))
 	}
+	for _, class := range config.ExtraClasses {
+		categories = append(categories, applyExclusions(class.Chars))
+	}
+
+	// Confusables depend on the whole charset: o is only ambiguous next to ο
+	if config.ExcludeConfusable {
+		confusable := ConfusableChars(strings.Join(categories, ""))
+		for i, cat := range categories {
+			categories[i] = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(confusable, r) {
+					return -1
+				}
+				return r
+			}, cat)
+		}
+	}
 
 	// Filter out empty categories (all characters excluded)
 	var nonEmpty []string
@@ -78,65 +109,25 @@ func (cs *CharacterSet) BuildCategories(config PasswordConfig) ([]string, error)
 			nonEmpty = append(nonEmpty, cat)
 		}
 	}
-
-	if len(nonEmpty) == 0 {
-		return nil, NewPasswordError("no characters available after exclusions")
-	}
-
-	return nonEmpty, nil
+	return nonEmpty
 }
 
 // CalculateCharsetSize calculates the size of the character set for entropy calculation
 func (cs *CharacterSet) CalculateCharsetSize(config PasswordConfig) int {
-	size := 0
-
-	if config.IncludeLower {
-		size += 26
-	}
-	if config.IncludeUpper {
-		size += 26
-	}
-	if config.IncludeNumbers {
-		size += 10
-	}
-	if config.IncludeSymbols {
-		size += len(Symbols)
-	}
-
-	// Adjust for excluded characters
-	if config.ExcludeSimilar {
-		similar := "il1Lo0O"
-		for _, char := range similar {
-			if config.IncludeLower && strings.Contains(Lowercase, string(char)) {
-				size--
-			}
-			if config.IncludeUpper && strings.Contains(Uppercase, string(char)) {
-				size--
-			}
-			if config.IncludeNumbers && strings.Contains(Numbers, string(char)) {
-				size--
-			}
-		}
-	}
+	return utf8.RuneCountInString(uniqueRunes(strings.Join(cs.categories(config), "")))
+}
 
-	if config.ExcludeChars != "" {
-		for _, char := range config.ExcludeChars {
-			if config.IncludeLower && strings.Contains(Lowercase, string(char)) {
-				size--
-			}
-			if config.IncludeUpper && strings.Contains(Uppercase, string(char)) {
-				size--
-			}
-			if config.IncludeNumbers && strings.Contains(Numbers, string(char)) {
-				size--
-			}
-			if config.IncludeSymbols && strings.Contains(Symbols, string(char)) {
-				size--
-			}
+// uniqueRunes returns s with repeated characters removed, keeping the first
+func uniqueRunes(s string) string {
+	seen := make(map[rune]bool)
+	var b strings.Builder
+	for _, r := range s {
+		if !seen[r] {
+			seen[r] = true
+			b.WriteRune(r)
 		}
 	}
-
-	return size
+	return b.String()
 }
 
 // GetCharset returns the current charset
@@ -0,0 +1,198 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// profileNamePattern restricts profile names to what is easy to type on a command line
+var profileNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// ComplianceProfile is a named rule set from a compliance standard or vendor
+// baseline. It configures how passwords are generated and which rules a
+// checked password must satisfy, and cites where each rule comes from.
+type ComplianceProfile struct {
+	Name        string            `json:"name"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Generation  ProfileGeneration `json:"generation"`
+	Policy      PasswordPolicy    `json:"policy"`
+	References  map[string]string `json:"references"` // policy field -> citation
+}
+
+// ProfileGeneration is the generation configuration a profile prescribes
+type ProfileGeneration struct {
+	Length            int  `json:"length"`
+	Lower             bool `json:"lower"`
+	Upper             bool `json:"upper"`
+	Numbers           bool `json:"numbers"`
+	Symbols           bool `json:"symbols"`
+	ExcludeSimilar    bool `json:"exclude_similar"`
+	ExcludeConfusable bool `json:"exclude_confusable"`
+}
+
+// ProfileRule is one rule of a profile in display form
+type ProfileRule struct {
+	Field     string // policy field name, the key of References
+	Rule      string
+	Reference string
+}
+
+// ParseComplianceProfile reads a profile from JSON and validates it
+func ParseComplianceProfile(r io.Reader) (ComplianceProfile, error) {
+	var profile ComplianceProfile
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&profile); err != nil {
+		return ComplianceProfile{}, NewPasswordError(fmt.Sprintf("invalid compliance profile: %v", err))
+	}
+	if err := profile.Validate(); err != nil {
+		return ComplianceProfile{}, err
+	}
+	return profile, nil
+}
+
+// Validate ensures the profile is well formed and that the passwords it
+// generates satisfy its own policy
+func (cp ComplianceProfile) Validate() error {
+	if !profileNamePattern.MatchString(cp.Name) {
+		return NewPasswordError(fmt.Sprintf("invalid compliance profile name %q: use lowercase letters, digits and hyphens", cp.Name))
+	}
+	invalid := func(format string, args ...interface{}) error {
+		return NewPasswordError(fmt.Sprintf("invalid compliance profile %s: ", cp.Name) + fmt.Sprintf(format, args...))
+	}
+
+	if err := cp.Policy.Validate(); err != nil {
+		return invalid("%v", err)
+	}
+	if err := cp.Config(1).Validate(); err != nil {
+		return invalid("generation: %v", err)
+	}
+	if err := cp.CheckConfig(cp.Config(1)); err != nil {
+		return invalid("generation: %v", err)
+	}
+
+	known := make(map[string]bool)
+	for _, rule := range cp.Rules() {
+		known[rule.Field] = true
+	}
+	for field := range cp.References {
+		if !known[field] {
+			return invalid("reference for %q, which is not a rule of this profile", field)
+		}
+	}
+	return nil
+}
+
+// CheckConfig reports whether passwords generated with config can satisfy the
+// profile's length and character-type rules
+func (cp ComplianceProfile) CheckConfig(config PasswordConfig) error {
+	policy := cp.Policy
+	if config.Length < policy.MinLength {
+		return NewPasswordErrorOfKind(KindPolicyViolation, fmt.Sprintf("length %d is below the %s minimum of %d", config.Length, cp.Name, policy.MinLength))
+	}
+	if policy.MaxLength > 0 && config.Length > policy.MaxLength {
+		return NewPasswordErrorOfKind(KindPolicyViolation, fmt.Sprintf("length %d is above the %s maximum of %d", config.Length, cp.Name, policy.MaxLength))
+	}
+
+	var missing []string
+	for _, rule := range []struct {
+		required, enabled bool
+		name              string
+	}{
+		{policy.RequireLetters, config.IncludeLower || config.IncludeUpper, "letters"},
+		{policy.RequireLower, config.IncludeLower, "lowercase letters"},
+		{policy.RequireUpper, config.IncludeUpper, "uppercase letters"},
+		{policy.RequireNumbers, config.IncludeNumbers, "numbers"},
+		{policy.RequireSymbols, config.IncludeSymbols, "symbols"},
+	} {
+		if rule.required && !rule.enabled {
+			missing = append(missing, rule.name)
+		}
+	}
+	if len(missing) > 0 {
+		return NewPasswordErrorOfKind(KindPolicyViolation, fmt.Sprintf("%s requires %s", cp.Name, strings.Join(missing, ", ")))
+	}
+
+	classes := 0
+	for _, enabled := range []bool{config.IncludeLower, config.IncludeUpper, config.IncludeNumbers, config.IncludeSymbols} {
+		if enabled {
+			classes++
+		}
+	}
+	if classes < policy.MinCharClasses {
+		return NewPasswordErrorOfKind(KindPolicyViolation, fmt.Sprintf("%s requires %d character types, only %d enabled", cp.Name, policy.MinCharClasses, classes))
+	}
+	return nil
+}
+
+// Config returns the generation configuration for count passwords
+func (cp ComplianceProfile) Config(count int) PasswordConfig {
+	return PasswordConfig{
+		Length:            cp.Generation.Length,
+		IncludeLower:      cp.Generation.Lower,
+		IncludeUpper:      cp.Generation.Upper,
+		IncludeNumbers:    cp.Generation.Numbers,
+		IncludeSymbols:    cp.Generation.Symbols,
+		ExcludeSimilar:    cp.Generation.ExcludeSimilar,
+		ExcludeConfusable: cp.Generation.ExcludeConfusable,
+		Count:             count,
+	}
+}
+
+// Rules lists the rules the profile's policy sets, each with its reference
+func (cp ComplianceProfile) Rules() []ProfileRule {
+	policy := cp.Policy
+	var rules []ProfileRule
+	add := func(field, rule string) {
+		rules = append(rules, ProfileRule{Field: field, Rule: rule, Reference: cp.References[field]})
+	}
+
+	add("min_length", fmt.Sprintf("At least %d characters", policy.MinLength))
+	if policy.MaxLength > 0 {
+		add("max_length", fmt.Sprintf("At most %d characters", policy.MaxLength))
+	}
+	if policy.RequireLetters {
+		add("require_letters", "Contains a letter")
+	}
+	if policy.RequireLower {
+		add("require_lower", "Contains a lowercase letter")
+	}
+	if policy.RequireUpper {
+		add("require_upper", "Contains an uppercase letter")
+	}
+	if policy.RequireNumbers {
+		add("require_numbers", "Contains a number")
+	}
+	if policy.RequireSymbols {
+		add("require_symbols", "Contains a symbol")
+	}
+	if policy.MinCharClasses > 0 {
+		add("min_char_classes", fmt.Sprintf("Uses at least %d of: lowercase, uppercase, numbers, symbols", policy.MinCharClasses))
+	}
+	if policy.AllowSpaces {
+		add("allow_spaces", "Spaces are allowed")
+	}
+	if policy.AllowUnicode {
+		add("allow_unicode", "Unicode characters are allowed")
+	}
+	if policy.Blocklist {
+		add("blocklist", "Not a breached, common or context-specific password")
+	}
+	if policy.ExpiryDays > 0 {
+		add("expiry_days", fmt.Sprintf("Changed at least every %d days", policy.ExpiryDays))
+	}
+	if policy.History > 0 {
+		add("history", fmt.Sprintf("Differs from the previous %d passwords", policy.History))
+	}
+	if policy.AllowHints {
+		add("allow_hints", "Password hints are allowed")
+	}
+	if policy.SecurityQuests {
+		add("security_questions", "Security questions are used")
+	}
+	return rules
+}
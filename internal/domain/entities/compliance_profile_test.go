@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseComplianceProfile(t *testing.T) {
+	input := `{
+		"name": "acme",
+		"title": "ACME baseline",
+		"generation": {"length": 20, "lower": true, "upper": true, "numbers": true},
+		"policy": {"min_length": 16, "require_numbers": true, "history": 5},
+		"references": {"min_length": "ACME 4.2", "history": "ACME 4.3"}
+	}`
+	profile, err := ParseComplianceProfile(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ParseComplianceProfile() error = %v", err)
+	}
+
+	rules := profile.Rules()
+	if len(rules) != 3 || rules[0].Reference != "ACME 4.2" || rules[2].Field != "history" {
+		t.Errorf("Rules() = %+v, want min_length, require_numbers and history with references", rules)
+	}
+	if config := profile.Config(3); config.Length != 20 || !config.IncludeNumbers || config.IncludeSymbols || config.Count != 3 {
+		t.Errorf("Config(3) = %+v", config)
+	}
+
+	invalid := map[string]string{
+		"bad name":            `{"name": "ACME Corp", "generation": {"length": 20, "lower": true}, "policy": {"min_length": 8}}`,
+		"short generation":    `{"name": "acme", "generation": {"length": 8, "lower": true}, "policy": {"min_length": 12}}`,
+		"missing type":        `{"name": "acme", "generation": {"length": 16, "lower": true}, "policy": {"require_symbols": true}}`,
+		"too few types":       `{"name": "acme", "generation": {"length": 16, "lower": true, "upper": true}, "policy": {"min_char_classes": 3}}`,
+		"stray reference":     `{"name": "acme", "generation": {"length": 16, "lower": true}, "policy": {}, "references": {"expiry_days": "x"}}`,
+		"unknown field":       `{"name": "acme", "generation": {"length": 16, "lower": true}, "policy": {}, "colour": "red"}`,
+		"no generation types": `{"name": "acme", "generation": {"length": 16}, "policy": {}}`,
+	}
+	for name, input := range invalid {
+		if _, err := ParseComplianceProfile(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: ParseComplianceProfile() succeeded, want an error", name)
+		}
+	}
+}
+
+func TestComplianceProfile_CheckConfig(t *testing.T) {
+	profile := ComplianceProfile{Name: "test", Policy: PasswordPolicy{MinLength: 12, MaxLength: 32, RequireLetters: true, MinCharClasses: 2}}
+
+	tests := []struct {
+		name    string
+		config  PasswordConfig
+		wantErr bool
+	}{
+		{"satisfies", PasswordConfig{Length: 16, IncludeUpper: true, IncludeNumbers: true}, false},
+		{"too short", PasswordConfig{Length: 8, IncludeUpper: true, IncludeNumbers: true}, true},
+		{"too long", PasswordConfig{Length: 40, IncludeUpper: true, IncludeNumbers: true}, true},
+		{"no letters", PasswordConfig{Length: 16, IncludeNumbers: true, IncludeSymbols: true}, true},
+		{"one type", PasswordConfig{Length: 16, IncludeLower: true}, true},
+	}
+
+	for _, tt := range tests {
+		err := profile.CheckConfig(tt.config)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: CheckConfig() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if err != nil && ErrorKindOf(err) != KindPolicyViolation {
+			t.Errorf("%s: CheckConfig() error kind = %v, want KindPolicyViolation", tt.name, ErrorKindOf(err))
+		}
+	}
+}
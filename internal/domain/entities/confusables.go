@@ -0,0 +1,250 @@
+package entities
+
+import (
+	_ "embed"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// The confusables table maps characters to the prototype they are visually
+// identical to, in the format of Unicode's confusables.txt
+//
+//go:generate go run gen_confusables.go -version 17.0.0
+//go:embed data/confusables.txt
+var confusablesData string
+
+var (
+	confusables     map[rune]string
+	confusablesOnce sync.Once
+)
+
+// allowedScriptMixes are the script combinations that are normal within one
+// language (UTS #39 "highly restrictive"), so they don't count as mixed
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// ConfusableChar is a character in a password that looks the same as a
+// different character or sequence
+type ConfusableChar struct {
+	Position  int // rune index in the password
+	Char      rune
+	Script    string
+	LooksLike string
+}
+
+// ConfusableReport lists what in a password could be mistaken for something
+// else when it is read off a screen or typed from paper
+type ConfusableReport struct {
+	Scripts     []string // writing systems of the password's letters, in order of appearance
+	MixedScript bool
+	Characters  []ConfusableChar
+}
+
+// HasFindings reports whether the password mixes scripts or contains lookalikes
+func (cr ConfusableReport) HasFindings() bool {
+	return cr.MixedScript || len(cr.Characters) > 0
+}
+
+// loadConfusables parses the embedded table once per process
+func loadConfusables() map[rune]string {
+	confusablesOnce.Do(func() {
+		confusables = parseConfusables(confusablesData)
+	})
+	return confusables
+}
+
+// parseConfusables reads "source ; prototype ; type # comment" lines of
+// hexadecimal code points, skipping comments and malformed lines
+func parseConfusables(data string) map[rune]string {
+	table := make(map[rune]string)
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 2 {
+			continue
+		}
+		source, ok := parseCodePoints(fields[0])
+		if !ok || len(source) != 1 {
+			continue
+		}
+		prototype, ok := parseCodePoints(fields[1])
+		if !ok || len(prototype) == 0 {
+			continue
+		}
+		table[source[0]] = string(prototype)
+	}
+	return table
+}
+
+// parseCodePoints reads space-separated hexadecimal code points
+func parseCodePoints(s string) ([]rune, bool) {
+	var runes []rune
+	for _, field := range strings.Fields(s) {
+		code, err := strconv.ParseUint(field, 16, 32)
+		if err != nil {
+			return nil, false
+		}
+		runes = append(runes, rune(code))
+	}
+	return runes, true
+}
+
+// Skeleton maps every character of s to its prototype, so two strings that
+// look the same have the same skeleton
+func Skeleton(s string) string {
+	table := loadConfusables()
+	var b strings.Builder
+	for _, r := range s {
+		if prototype, ok := table[r]; ok {
+			b.WriteString(prototype)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// DetectConfusables reports the scripts a password's letters come from and
+// every non-ASCII letter that looks like a letter of another script the
+// password uses, or of Latin, the script passwords are most often retyped in
+// (UTS #39 mixed-script and whole-script confusables). Accented letters such
+// as ö look like nothing else in a Latin password, and ASCII lookalikes such
+// as 0 and O are left to the similar-character options.
+func DetectConfusables(password string) ConfusableReport {
+	table := loadConfusables()
+	var report ConfusableReport
+	runes := []rune(password)
+	seen := make(map[string]bool)
+
+	for _, r := range runes {
+		if script := ScriptOf(r); script != "" && !seen[script] {
+			seen[script] = true
+			report.Scripts = append(report.Scripts, script)
+		}
+	}
+
+	for i, r := range runes {
+		script := ScriptOf(r)
+		prototype, ok := table[r]
+		if !ok || r <= unicode.MaxASCII || script == "" {
+			continue
+		}
+		target := scriptOfString(prototype)
+		if target == "" || target == script || target != "Latin" && !seen[target] {
+			continue
+		}
+		report.Characters = append(report.Characters, ConfusableChar{Position: i, Char: r, Script: script, LooksLike: prototype})
+	}
+
+	report.MixedScript = len(report.Scripts) > 1 && !allowedScriptMix(report.Scripts)
+	return report
+}
+
+// scriptOfString names the script of the first character of s that has one
+func scriptOfString(s string) string {
+	for _, r := range s {
+		if script := ScriptOf(r); script != "" {
+			return script
+		}
+	}
+	return ""
+}
+
+// ConfusableChars returns the characters of charset that look like another
+// character of charset, or like a sequence of them (m and rn), so they can be
+// left out of generated passwords
+func ConfusableChars(charset string) string {
+	runes := []rune(uniqueRunes(charset))
+	present := make(map[rune]bool, len(runes))
+	bySkeleton := make(map[string][]rune)
+	for _, r := range runes {
+		present[r] = true
+		skeleton := Skeleton(string(r))
+		bySkeleton[skeleton] = append(bySkeleton[skeleton], r)
+	}
+
+	confusable := make(map[rune]bool)
+	for skeleton, group := range bySkeleton {
+		if len(group) > 1 {
+			for _, r := range group {
+				confusable[r] = true
+			}
+			continue
+		}
+		// A character whose prototype is a sequence can be misread as those
+		// characters when they can appear next to each other
+		if len([]rune(skeleton)) > 1 {
+			spelled := true
+			for _, r := range skeleton {
+				if !present[r] && len(bySkeleton[string(r)]) == 0 {
+					spelled = false
+				}
+			}
+			if spelled {
+				confusable[group[0]] = true
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, r := range runes {
+		if confusable[r] {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// ScriptOf names the Unicode script of r, or returns "" for characters shared
+// between scripts such as digits, punctuation and combining marks
+func ScriptOf(r rune) string {
+	if unicode.In(r, unicode.Common, unicode.Inherited) {
+		return ""
+	}
+	if unicode.Is(unicode.Latin, r) {
+		return "Latin"
+	}
+	names := make([]string, 0, len(unicode.Scripts))
+	for name := range unicode.Scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if unicode.Is(unicode.Scripts[name], r) {
+			return name
+		}
+	}
+	return ""
+}
+
+// allowedScriptMix reports whether scripts all belong to one allowed combination
+func allowedScriptMix(scripts []string) bool {
+	for _, mix := range allowedScriptMixes {
+		allowed := true
+		for _, script := range scripts {
+			found := false
+			for _, name := range mix {
+				if script == name {
+					found = true
+					break
+				}
+			}
+			if !found {
+				allowed = false
+				break
+			}
+		}
+		if allowed {
+			return true
+		}
+	}
+	return false
+}
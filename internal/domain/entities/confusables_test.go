@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSkeleton(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"paypal", "pаypаl", true}, // Cyrillic а
+		{"ΗΕΛΛΟ", "HEΛΛO", true},   // Greek capitals
+		{"modern", "rnodern", true},
+		{"C0DE", "CODE", true},
+		{"password", "passwort", false},
+	}
+
+	for _, tt := range tests {
+		if got := Skeleton(tt.a) == Skeleton(tt.b); got != tt.same {
+			t.Errorf("Skeleton(%q) == Skeleton(%q) is %v, want %v", tt.a, tt.b, got, tt.same)
+		}
+	}
+}
+
+func TestDetectConfusables(t *testing.T) {
+	tests := []struct {
+		name        string
+		password    string
+		wantScripts []string
+		wantMixed   bool
+		wantChars   string
+	}{
+		{"ASCII", "Passw0rd!", []string{"Latin"}, false, ""},
+		{"Cyrillic a in Latin word", "pаssword1", []string{"Latin", "Cyrillic"}, true, "а"},
+		{"all Cyrillic", "пароль", []string{"Cyrillic"}, false, "ароь"},
+		{"Japanese mixes scripts normally", "東京たワー", []string{"Han", "Hiragana", "Katakana"}, false, ""},
+		{"Greek and Latin", "Sofiaσοφία", []string{"Latin", "Greek"}, true, "σοφα"},
+		{"digits only", "2024", nil, false, ""},
+		{"German diacritics", "schön2024", []string{"Latin"}, false, ""},
+		{"Swedish diacritics", "Smörgåsbord", []string{"Latin"}, false, ""},
+		{"Turkish diacritics", "Çağrı-Öğün", []string{"Latin"}, false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := DetectConfusables(tt.password)
+			if !reflect.DeepEqual(report.Scripts, tt.wantScripts) {
+				t.Errorf("Scripts = %v, want %v", report.Scripts, tt.wantScripts)
+			}
+			if report.MixedScript != tt.wantMixed {
+				t.Errorf("MixedScript = %v, want %v", report.MixedScript, tt.wantMixed)
+			}
+			var chars strings.Builder
+			for _, char := range report.Characters {
+				chars.WriteRune(char.Char)
+				if []rune(tt.password)[char.Position] != char.Char {
+					t.Errorf("Position %d does not hold %q", char.Position, char.Char)
+				}
+			}
+			if chars.String() != tt.wantChars {
+				t.Errorf("Characters = %q, want %q", chars.String(), tt.wantChars)
+			}
+			if report.HasFindings() != (tt.wantMixed || tt.wantChars != "") {
+				t.Errorf("HasFindings() = %v", report.HasFindings())
+			}
+		})
+	}
+}
+
+func TestConfusableChars(t *testing.T) {
+	tests := []struct {
+		name    string
+		charset string
+		want    string
+	}{
+		{"lowercase alone", Lowercase, "m"},
+		{"letters", Lowercase + Uppercase, "lmI"},
+		{"letters, digits and symbols", Lowercase + Uppercase + Numbers + Symbols, "lmIO01|"},
+		{"m without r", "mn", ""},
+		{"Latin and Greek", "opxyzΑΒΟοπ", "oο"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConfusableChars(tt.charset); got != tt.want {
+				t.Errorf("ConfusableChars(%q) = %q, want %q", tt.charset, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCharacterSet_ExcludeConfusable(t *testing.T) {
+	cs := NewCharacterSet()
+	greek, _ := ParseCharacterClass("greek")
+	config := PasswordConfig{Length: 12, IncludeLower: true, IncludeUpper: true, ExcludeConfusable: true, ExtraClasses: []CharacterClass{greek}, Count: 1}
+
+	charset, err := cs.BuildCharset(config)
+	if err != nil {
+		t.Fatalf("BuildCharset() unexpected error: %v", err)
+	}
+	for _, lookalike := range []string{"o", "ο", "a", "α", "A", "Α", "I", "l", "m"} {
+		if strings.Contains(charset, lookalike) {
+			t.Errorf("charset still contains %q", lookalike)
+		}
+	}
+	for _, kept := range []string{"b", "β", "Ω", "L"} {
+		if !strings.Contains(charset, kept) {
+			t.Errorf("charset lost %q, which has no lookalike", kept)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+//go:build ignore
+
+// gen_confusables writes data/confusables.txt from Unicode's confusables.txt
+// (UTS #39). Passwords are NFKC-normalized before they are checked, so only
+// the entries whose source character NFKC leaves unchanged are kept.
+//
+// Usage:
+//
+//	go run gen_confusables.go [-version 17.0.0] [-in confusables.txt -source description] [-out data/confusables.txt]
+//
+// Without -in, the file for -version is downloaded from unicode.org. A local
+// copy needs -source, describing where it came from for the header.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+	"golang.org/x/text/unicode/runenames"
+)
+
+func main() {
+	version := flag.String("version", "17.0.0", "Unicode version of the confusables data to download")
+	in := flag.String("in", "", "read a local copy of confusables.txt instead of downloading it")
+	origin := flag.String("source", "", "where the -in file came from, for the header")
+	out := flag.String("out", "data/confusables.txt", "file to write")
+	flag.Parse()
+
+	url := "https://www.unicode.org/Public/" + *version + "/security/confusables.txt"
+	if *in == "" {
+		*origin = url
+	} else if *origin == "" {
+		log.Fatal("-in needs -source describing where the file came from")
+	}
+
+	source, err := open(*in, url)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer source.Close()
+
+	var lines []string
+	kept, dropped := 0, 0
+	scanner := bufio.NewScanner(source)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) < 3 {
+			continue
+		}
+		from, err := parseCodePoints(fields[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		to, err := parseCodePoints(fields[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(from) != 1 || norm.NFKC.String(string(from)) != string(from) {
+			dropped++
+			continue
+		}
+		kept++
+		lines = append(lines, fmt.Sprintf("%s ;\t%s ;\t%s\t# ( %s → %s ) %s → %s",
+			hexCodePoints(from), hexCodePoints(to), strings.TrimSpace(fields[2]),
+			string(from), string(to), names(from), names(to)))
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	header := fmt.Sprintf(`# Confusable characters in the format of Unicode's confusables.txt (UTS #39).
+# Source: %s
+#
+# Generated by gen_confusables.go; DO NOT EDIT. Passwords are NFKC-normalized
+# before they are checked, so the %d entries whose source character NFKC
+# changes, such as fullwidth letters, are left out.
+#
+# source ; prototype ; type # ( source → prototype ) names
+
+`, *origin, dropped)
+	data := header + strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(*out, []byte(data), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("wrote %d confusables to %s", kept, *out)
+}
+
+// open returns the local file at path or, when path is empty, the download
+// at url
+func open(path, url string) (io.ReadCloser, error) {
+	if path != "" {
+		return os.Open(path)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// parseCodePoints reads space-separated hexadecimal code points
+func parseCodePoints(s string) ([]rune, error) {
+	var runes []rune
+	for _, field := range strings.Fields(s) {
+		code, err := strconv.ParseUint(field, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code point %q", field)
+		}
+		runes = append(runes, rune(code))
+	}
+	return runes, nil
+}
+
+// hexCodePoints writes runes as space-separated hexadecimal code points
+func hexCodePoints(runes []rune) string {
+	codes := make([]string, len(runes))
+	for i, r := range runes {
+		codes[i] = fmt.Sprintf("%04X", r)
+	}
+	return strings.Join(codes, " ")
+}
+
+// names joins the character names of runes with +, falling back to the code
+// point for characters newer than the name table
+func names(runes []rune) string {
+	parts := make([]string, len(runes))
+	for i, r := range runes {
+		if parts[i] = runenames.Name(r); parts[i] == "" {
+			parts[i] = fmt.Sprintf("U+%04X", r)
+		}
+	}
+	return strings.Join(parts, " + ")
+}
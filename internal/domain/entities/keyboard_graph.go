@@ -0,0 +1,336 @@
+package entities
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// minReportedWalkLength is the shortest keyboard walk DetectKeyboardWalks
+// reports; three-key runs turn up by chance in random passwords
+const minReportedWalkLength = 4
+
+// Keyboard layouts, one row per line. Each key is written as its unshifted
+// character followed by its shifted character (keypads have no shift state).
+var (
+	qwertyRows = []string{
+		"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) -_ =+",
+		"    qQ wW eE rR tT yY uU iI oO pP [{ ]} \\|",
+		"     aA sS dD fF gG hH jJ kK lL ;: '\"",
+		"      zZ xX cC vV bB nN mM ,< .> /?",
+	}
+
+	// German layout; the ISO key left of y is omitted
+	qwertzRows = []string{
+		"^° 1! 2\" 3§ 4$ 5% 6& 7/ 8( 9) 0= ß? ´`",
+		"    qQ wW eE rR tT zZ uU iI oO pP üÜ +*",
+		"     aA sS dD fF gG hH jJ kK lL öÖ äÄ #'",
+		"      yY xX cC vV bB nN mM ,; .: -_",
+	}
+
+	// French layout; digits are shifted and the ² key is omitted
+	azertyRows = []string{
+		"   &1 é2 \"3 '4 (5 -6 è7 _8 ç9 à0 )° =+",
+		"    aA zZ eE rR tT yY uU iI oO pP ^¨ $£",
+		"     qQ sS dD fF gG hH jJ kK lL mM ù% *µ",
+		"      wW xX cC vV bB nN ,? ;. :/ !§",
+	}
+
+	dvorakRows = []string{
+		"`~ 1! 2@ 3# 4$ 5% 6^ 7& 8* 9( 0) [{ ]}",
+		"    '\" ,< .> pP yY fF gG cC rR lL /? =+ \\|",
+		"     aA oO eE uU iI dD hH tT nN sS -_",
+		"      ;: qQ jJ kK xX bB mM wW vV zZ",
+	}
+
+	keypadRows = []string{
+		"  / * -",
+		"7 8 9 +",
+		"4 5 6",
+		"1 2 3",
+		"  0 .",
+	}
+)
+
+// KeyboardGraph describes which keys neighbour each other on a physical layout.
+// Adjacency lists every key's neighbours in a fixed direction order so that a
+// change in direction between consecutive keys can be counted as a turn; an
+// empty string marks a direction with no key.
+type KeyboardGraph struct {
+	Name      string
+	Slanted   bool
+	Adjacency map[rune][]string
+	shifted   map[rune]bool
+}
+
+// KeyboardWalk is a run of physically adjacent keys found in a password
+type KeyboardWalk struct {
+	Graph        string
+	Start        int // rune index of the first key
+	End          int // rune index of the last key (inclusive)
+	Token        string
+	Turns        int
+	ShiftedCount int
+	Guesses      float64 // walks an attacker tries before this one
+}
+
+// Length returns the number of keys in the walk
+func (kw KeyboardWalk) Length() int {
+	return kw.End - kw.Start + 1
+}
+
+// NewKeyboardGraph builds an adjacency graph from a textual layout. Slanted
+// layouts (typewriter keyboards) have six neighbours per key; aligned layouts
+// (keypads) have eight.
+func NewKeyboardGraph(name string, rows []string, slanted bool) *KeyboardGraph {
+	type coord struct{ x, y int }
+
+	tokenSize := len([]rune(strings.Fields(rows[0])[0]))
+	xUnit := tokenSize + 1
+	positions := make(map[coord]string)
+
+	// Columns are counted in runes so layouts may use non-ASCII keys
+	for y, row := range rows {
+		slant := 0
+		if slanted {
+			slant = y
+		}
+		runes := []rune(row)
+		for idx := 0; idx < len(runes); idx++ {
+			if runes[idx] == ' ' {
+				continue
+			}
+			end := idx
+			for end < len(runes) && runes[end] != ' ' {
+				end++
+			}
+			positions[coord{(idx - slant) / xUnit, y}] = string(runes[idx:end])
+			idx = end
+		}
+	}
+
+	neighbours := func(x, y int) []coord {
+		if slanted {
+			return []coord{{x - 1, y}, {x, y - 1}, {x + 1, y - 1}, {x + 1, y}, {x, y + 1}, {x - 1, y + 1}}
+		}
+		return []coord{{x - 1, y}, {x - 1, y - 1}, {x, y - 1}, {x + 1, y - 1}, {x + 1, y}, {x + 1, y + 1}, {x, y + 1}, {x - 1, y + 1}}
+	}
+
+	adjacency := make(map[rune][]string)
+	shifted := make(map[rune]bool)
+	for pos, token := range positions {
+		for i, char := range []rune(token) {
+			if slanted && i == 1 {
+				shifted[char] = true
+			}
+			adjacent := make([]string, 0, 8)
+			for _, n := range neighbours(pos.x, pos.y) {
+				adjacent = append(adjacent, positions[n])
+			}
+			adjacency[char] = adjacent
+		}
+	}
+
+	return &KeyboardGraph{Name: name, Slanted: slanted, Adjacency: adjacency, shifted: shifted}
+}
+
+// QwertyGraph returns the adjacency graph of a US QWERTY keyboard
+func QwertyGraph() *KeyboardGraph {
+	return NewKeyboardGraph("qwerty", qwertyRows, true)
+}
+
+// QwertzGraph returns the adjacency graph of a German QWERTZ keyboard
+func QwertzGraph() *KeyboardGraph {
+	return NewKeyboardGraph("qwertz", qwertzRows, true)
+}
+
+// AzertyGraph returns the adjacency graph of a French AZERTY keyboard
+func AzertyGraph() *KeyboardGraph {
+	return NewKeyboardGraph("azerty", azertyRows, true)
+}
+
+// DvorakGraph returns the adjacency graph of a US Dvorak keyboard
+func DvorakGraph() *KeyboardGraph {
+	return NewKeyboardGraph("dvorak", dvorakRows, true)
+}
+
+// KeyboardGraphs returns the graphs of every supported layout
+func KeyboardGraphs() []*KeyboardGraph {
+	return []*KeyboardGraph{QwertyGraph(), QwertzGraph(), AzertyGraph(), DvorakGraph(), KeypadGraph()}
+}
+
+// KeypadGraph returns the adjacency graph of a numeric keypad
+func KeypadGraph() *KeyboardGraph {
+	return NewKeyboardGraph("keypad", keypadRows, false)
+}
+
+// StartingPositions returns the number of keys a walk can start on
+func (kg *KeyboardGraph) StartingPositions() int {
+	return len(kg.Adjacency)
+}
+
+// AverageDegree returns the mean number of neighbours per key
+func (kg *KeyboardGraph) AverageDegree() float64 {
+	if len(kg.Adjacency) == 0 {
+		return 0
+	}
+
+	total := 0
+	for _, adjacent := range kg.Adjacency {
+		for _, key := range adjacent {
+			if key != "" {
+				total++
+			}
+		}
+	}
+	return float64(total) / float64(len(kg.Adjacency))
+}
+
+// FindWalks returns every run of three or more adjacent keys in password.
+// Walks do not overlap; each one ends where the next adjacency breaks.
+func (kg *KeyboardGraph) FindWalks(password string) []KeyboardWalk {
+	var walks []KeyboardWalk
+	runes := []rune(password)
+
+	i := 0
+	for i < len(runes)-1 {
+		j := i + 1
+		lastDirection := -1
+		turns := 0
+		shifted := 0
+		if kg.shifted[runes[i]] {
+			shifted = 1
+		}
+
+		for {
+			found := false
+			if j < len(runes) {
+				for direction, key := range kg.Adjacency[runes[j-1]] {
+					pos := strings.IndexRune(key, runes[j])
+					if key == "" || pos < 0 {
+						continue
+					}
+					found = true
+					if pos > 0 {
+						shifted++
+					}
+					if direction != lastDirection {
+						turns++
+						lastDirection = direction
+					}
+					break
+				}
+			}
+
+			if found {
+				j++
+				continue
+			}
+
+			if j-i > 2 {
+				walks = append(walks, KeyboardWalk{
+					Graph:        kg.Name,
+					Start:        i,
+					End:          j - 1,
+					Token:        string(runes[i:j]),
+					Turns:        turns,
+					ShiftedCount: shifted,
+				})
+				walks[len(walks)-1].Guesses = kg.Guesses(walks[len(walks)-1])
+			}
+			i = j
+			break
+		}
+	}
+
+	return walks
+}
+
+// Guesses counts the walks of the same length and at most the same number of
+// turns on this keyboard, times the ways of choosing which keys are shifted
+func (kg *KeyboardGraph) Guesses(walk KeyboardWalk) float64 {
+	starts := float64(kg.StartingPositions())
+	degree := kg.AverageDegree()
+	length := walk.Length()
+
+	guesses := 0.0
+	for i := 2; i <= length; i++ {
+		possibleTurns := walk.Turns
+		if possibleTurns > i-1 {
+			possibleTurns = i - 1
+		}
+		for j := 1; j <= possibleTurns; j++ {
+			guesses += Binomial(i-1, j-1) * starts * math.Pow(degree, float64(j))
+		}
+	}
+
+	if walk.ShiftedCount > 0 {
+		shifted := walk.ShiftedCount
+		unshifted := length - shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			variations := 0.0
+			for i := 1; i <= shifted && i <= unshifted; i++ {
+				variations += Binomial(shifted+unshifted, i)
+			}
+			guesses *= variations
+		}
+	}
+	return guesses
+}
+
+// DetectKeyboardWalks finds walks of at least four keys on every layout in
+// graphs. On keypads, where most digits neighbour each other, a walk must
+// keep its direction for at least one step, or it would match a tenth of all
+// numbers. Where walks on different layouts overlap, the longest and then the
+// cheapest to guess is kept.
+func DetectKeyboardWalks(password string, graphs []*KeyboardGraph) []KeyboardWalk {
+	var candidates []KeyboardWalk
+	for _, graph := range graphs {
+		for _, walk := range graph.FindWalks(password) {
+			if !graph.Slanted && walk.Turns >= walk.Length()-1 {
+				continue
+			}
+			if walk.Length() >= minReportedWalkLength {
+				candidates = append(candidates, walk)
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Length() != candidates[j].Length() {
+			return candidates[i].Length() > candidates[j].Length()
+		}
+		return candidates[i].Guesses < candidates[j].Guesses
+	})
+
+	var walks []KeyboardWalk
+	for _, candidate := range candidates {
+		overlaps := false
+		for _, walk := range walks {
+			if candidate.Start <= walk.End && walk.Start <= candidate.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			walks = append(walks, candidate)
+		}
+	}
+	sort.Slice(walks, func(i, j int) bool { return walks[i].Start < walks[j].Start })
+	return walks
+}
+
+// Binomial returns n choose k as a float to avoid overflow
+func Binomial(n, k int) float64 {
+	if k > n || k < 0 {
+		return 0
+	}
+	result := 1.0
+	for d := 1; d <= k; d++ {
+		result *= float64(n)
+		result /= float64(d)
+		n--
+	}
+	return result
+}
@@ -0,0 +1,176 @@
+package entities
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestKeyboardGraph_FindWalks(t *testing.T) {
+	tests := []struct {
+		name        string
+		graph       *KeyboardGraph
+		password    string
+		wantTokens  []string
+		wantTurns   []int
+		wantShifted []int
+	}{
+		{
+			name:        "straight row",
+			graph:       QwertyGraph(),
+			password:    "qwerty",
+			wantTokens:  []string{"qwerty"},
+			wantTurns:   []int{1},
+			wantShifted: []int{0},
+		},
+		{
+			name:        "column with shift",
+			graph:       QwertyGraph(),
+			password:    "zaq1@WSX",
+			wantTokens:  []string{"zaq1@WSX"},
+			wantTurns:   []int{3},
+			wantShifted: []int{4},
+		},
+		{
+			name:        "keypad",
+			graph:       KeypadGraph(),
+			password:    "x7896321",
+			wantTokens:  []string{"7896321"},
+			wantTurns:   []int{3},
+			wantShifted: []int{0},
+		},
+		{
+			name:       "too short",
+			graph:      QwertyGraph(),
+			password:   "qw",
+			wantTokens: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			walks := tt.graph.FindWalks(tt.password)
+			if len(walks) != len(tt.wantTokens) {
+				t.Fatalf("FindWalks(%q) = %d walks, want %d", tt.password, len(walks), len(tt.wantTokens))
+			}
+			for i, walk := range walks {
+				if walk.Token != tt.wantTokens[i] {
+					t.Errorf("Token = %v, want %v", walk.Token, tt.wantTokens[i])
+				}
+				if walk.Turns != tt.wantTurns[i] {
+					t.Errorf("Turns = %v, want %v", walk.Turns, tt.wantTurns[i])
+				}
+				if walk.ShiftedCount != tt.wantShifted[i] {
+					t.Errorf("ShiftedCount = %v, want %v", walk.ShiftedCount, tt.wantShifted[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeyboardGraph_Statistics(t *testing.T) {
+	qwerty := QwertyGraph()
+	if qwerty.StartingPositions() != 94 {
+		t.Errorf("QWERTY starting positions = %v, want 94", qwerty.StartingPositions())
+	}
+	if degree := qwerty.AverageDegree(); degree < 4 || degree > 5 {
+		t.Errorf("QWERTY average degree = %v, want between 4 and 5", degree)
+	}
+
+	keypad := KeypadGraph()
+	if keypad.StartingPositions() != 15 {
+		t.Errorf("Keypad starting positions = %v, want 15", keypad.StartingPositions())
+	}
+}
+
+func TestKeyboardGraph_Layouts(t *testing.T) {
+	tests := []struct {
+		graph    *KeyboardGraph
+		password string
+	}{
+		{QwertzGraph(), "qwertzuiop"},
+		{QwertzGraph(), "löüä"},
+		{AzertyGraph(), "azerty"},
+		{AzertyGraph(), "é&aqw"},
+		{DvorakGraph(), "aoeuidhtns"},
+		{DvorakGraph(), "',.pyf"},
+	}
+
+	for _, tt := range tests {
+		walks := tt.graph.FindWalks(tt.password)
+		if len(walks) != 1 || walks[0].Token != tt.password {
+			t.Errorf("%s FindWalks(%q) = %+v, want a single walk over the whole password", tt.graph.Name, tt.password, walks)
+		}
+	}
+}
+
+func TestDetectKeyboardWalks(t *testing.T) {
+	tests := []struct {
+		password   string
+		wantTokens []string
+		wantGraphs []string
+	}{
+		{"1qaz2wsx", []string{"1qaz", "2wsx"}, []string{"qwerty", "qwerty"}},
+		{"zaq1@WSX", []string{"zaq1@WSX"}, []string{"qwerty"}},
+		{"x7896321", []string{"7896321"}, []string{"keypad"}},
+		{"qwertzuiop", []string{"qwertzuiop"}, []string{"qwertz"}},
+		{"Zq9#vT2!", nil, nil},
+		// Zigzags over the dense keypad are just numbers
+		{"2024", nil, nil},
+		{"+14155552671", nil, nil},
+	}
+
+	for _, tt := range tests {
+		walks := DetectKeyboardWalks(tt.password, KeyboardGraphs())
+		if len(walks) != len(tt.wantTokens) {
+			t.Fatalf("DetectKeyboardWalks(%q) = %+v, want %v", tt.password, walks, tt.wantTokens)
+		}
+		for i, walk := range walks {
+			if walk.Token != tt.wantTokens[i] || walk.Graph != tt.wantGraphs[i] {
+				t.Errorf("DetectKeyboardWalks(%q)[%d] = %s on %s, want %s on %s", tt.password, i, walk.Token, walk.Graph, tt.wantTokens[i], tt.wantGraphs[i])
+			}
+			if walk.Guesses <= 1 {
+				t.Errorf("DetectKeyboardWalks(%q)[%d] guesses = %v, want > 1", tt.password, i, walk.Guesses)
+			}
+		}
+	}
+}
+
+func TestDetectKeyboardWalks_FewNumbersAreKeypadWalks(t *testing.T) {
+	walks, years := 0, 0
+	for n := 0; n < 10000; n++ {
+		if len(DetectKeyboardWalks(fmt.Sprintf("%04d", n), KeyboardGraphs())) > 0 {
+			walks++
+			if n >= 1950 && n <= 2030 {
+				years++
+			}
+		}
+	}
+	if walks > 300 || years > 2 {
+		t.Errorf("%d of 10000 four-digit numbers and %d years since 1950 are keypad walks, want at most 300 and 2", walks, years)
+	}
+}
+
+func TestPasswordPatternDetector_PersonalDataHidesWalks(t *testing.T) {
+	detector := NewPasswordPatternDetector()
+	tests := []struct {
+		password, want string
+	}{
+		{"Jan2024!", "year_pattern"},
+		{"x29/02/2024x", "date_pattern"},
+		{"+14155552671", "phone_pattern"},
+		{"SW1A1AAsecret", "postcode_pattern"},
+	}
+
+	for _, tt := range tests {
+		found := false
+		for _, pattern := range detector.DetectPatterns(tt.password) {
+			if pattern.Type == "keyboard_pattern" {
+				t.Errorf("DetectPatterns(%q) reports keyboard walk %q", tt.password, pattern.Description)
+			}
+			found = found || pattern.Type == tt.want
+		}
+		if !found {
+			t.Errorf("DetectPatterns(%q) lacks %s", tt.password, tt.want)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package entities
 
 import (
-	"regexp"
+	"errors"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 // PasswordStrength represents the strength level of a password
@@ -37,17 +41,31 @@ func (ps PasswordStrength) String() string {
 	}
 }
 
+// ParsePasswordStrength reads a strength by name, such as "Medium" or
+// "very strong", ignoring case, spaces, hyphens and underscores
+func ParsePasswordStrength(name string) (PasswordStrength, error) {
+	normalized := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(name))
+	for strength := VeryWeak; strength <= ExtremelyStrong; strength++ {
+		if strings.ReplaceAll(strings.ToLower(strength.String()), " ", "") == normalized {
+			return strength, nil
+		}
+	}
+	return VeryWeak, NewPasswordError("unknown strength: " + name)
+}
+
 // PasswordConfig represents configuration for password generation
 type PasswordConfig struct {
-	Length         int
-	IncludeLower   bool
-	IncludeUpper   bool
-	IncludeNumbers bool
-	IncludeSymbols bool
-	ExcludeSimilar bool
-	ExcludeChars   string
-	Count          int
-	NoRepeat       bool
+	Length            int
+	IncludeLower      bool
+	IncludeUpper      bool
+	IncludeNumbers    bool
+	IncludeSymbols    bool
+	ExcludeSimilar    bool
+	ExcludeConfusable bool // leave out characters that look like another available one
+	ExcludeChars      string
+	Count             int
+	NoRepeat          bool
+	ExtraClasses      []CharacterClass // drawn from alongside the enabled standard types
 }
 
 // Validate ensures the password configuration is valid
@@ -56,7 +74,7 @@ func (pc PasswordConfig) Validate() error {
 		return NewPasswordError("password length must be positive")
 	}
 
-	if !pc.IncludeLower && !pc.IncludeUpper && !pc.IncludeNumbers && !pc.IncludeSymbols {
+	if !pc.IncludeLower && !pc.IncludeUpper && !pc.IncludeNumbers && !pc.IncludeSymbols && len(pc.ExtraClasses) == 0 {
 		return NewPasswordError("at least one character type must be selected")
 	}
 
@@ -67,42 +85,51 @@ func (pc PasswordConfig) Validate() error {
 	return nil
 }
 
-// Password represents a generated password with its properties
+// Password represents a generated password with its properties. Value is
+// NFKC-normalized, as NIST SP 800-63B asks verifiers to do, so that visually
+// identical input compares and counts the same however it was typed.
 type Password struct {
-	Value  string
-	Length int
+	Value     string
+	Length    int // in Unicode code points
+	Graphemes int // in user-perceived characters, e.g. an emoji with a skin tone counts once
 }
 
 // NewPassword creates a new Password instance
 func NewPassword(value string) Password {
+	value = norm.NFKC.String(value)
 	return Password{
-		Value:  value,
-		Length: len(value),
+		Value:     value,
+		Length:    utf8.RuneCountInString(value),
+		Graphemes: len(Graphemes(value)),
 	}
 }
 
-// HasLowercase checks if password contains lowercase letters
+// HasLowercase checks if password contains lowercase letters in any script
 func (p Password) HasLowercase() bool {
-	matched, _ := regexp.MatchString(`[a-z]`, p.Value)
-	return matched
+	return strings.IndexFunc(p.Value, unicode.IsLower) >= 0
 }
 
-// HasUppercase checks if password contains uppercase letters
+// HasUppercase checks if password contains uppercase or titlecase letters in any script
 func (p Password) HasUppercase() bool {
-	matched, _ := regexp.MatchString(`[A-Z]`, p.Value)
-	return matched
+	return strings.IndexFunc(p.Value, func(r rune) bool { return unicode.IsUpper(r) || unicode.IsTitle(r) }) >= 0
+}
+
+// HasOtherLetters checks if password contains letters from scripts without
+// case, such as CJK ideographs or Arabic
+func (p Password) HasOtherLetters() bool {
+	return strings.IndexFunc(p.Value, func(r rune) bool {
+		return unicode.IsLetter(r) && !unicode.IsLower(r) && !unicode.IsUpper(r) && !unicode.IsTitle(r)
+	}) >= 0
 }
 
-// HasNumbers checks if password contains numbers
+// HasNumbers checks if password contains digits or other numerals in any script
 func (p Password) HasNumbers() bool {
-	matched, _ := regexp.MatchString(`[0-9]`, p.Value)
-	return matched
+	return strings.IndexFunc(p.Value, unicode.IsNumber) >= 0
 }
 
-// HasSymbols checks if password contains symbols
+// HasSymbols checks if password contains punctuation or symbols, emoji included
 func (p Password) HasSymbols() bool {
-	matched, _ := regexp.MatchString(`[!@#$%^&*()_+\-=\[\]{}|;:,.<>?]`, p.Value)
-	return matched
+	return strings.IndexFunc(p.Value, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) }) >= 0
 }
 
 // GetCharacterTypes returns the types of characters present in the password
@@ -115,6 +142,9 @@ func (p Password) GetCharacterTypes() []string {
 	if p.HasUppercase() {
 		types = append(types, "Uppercase")
 	}
+	if p.HasOtherLetters() {
+		types = append(types, "Other letters")
+	}
 	if p.HasNumbers() {
 		types = append(types, "Numbers")
 	}
@@ -125,22 +155,62 @@ func (p Password) GetCharacterTypes() []string {
 	return types
 }
 
+// ObservedConfig returns the generation settings a password's characters
+// imply, for analyzing passwords that passgen didn't generate
+func (p Password) ObservedConfig() PasswordConfig {
+	return PasswordConfig{
+		Length:         p.Length,
+		IncludeLower:   p.HasLowercase(),
+		IncludeUpper:   p.HasUppercase(),
+		IncludeNumbers: p.HasNumbers(),
+		IncludeSymbols: p.HasSymbols(),
+		Count:          1,
+	}
+}
+
 // IsEmpty checks if password is empty
 func (p Password) IsEmpty() bool {
 	return strings.TrimSpace(p.Value) == ""
 }
 
+// ErrorKind classifies a PasswordError so callers can tell failures apart
+type ErrorKind int
+
+const (
+	KindInvalidInput    ErrorKind = iota // a malformed request, option or file
+	KindPolicyViolation                  // settings or a password break a policy, profile or banned-word list
+	KindWeakPassword                     // a password is rated below the required strength
+	KindBreached                         // a password appears in a breach corpus
+	KindInternal                         // a failure unrelated to the input, such as the random source
+)
+
 // PasswordError represents password-related errors
 type PasswordError struct {
+	Kind    ErrorKind
 	Message string
 }
 
-// NewPasswordError creates a new password error
+// NewPasswordError creates a new password error about invalid input
 func NewPasswordError(message string) *PasswordError {
-	return &PasswordError{Message: message}
+	return &PasswordError{Kind: KindInvalidInput, Message: message}
+}
+
+// NewPasswordErrorOfKind creates a new password error of the given kind
+func NewPasswordErrorOfKind(kind ErrorKind, message string) *PasswordError {
+	return &PasswordError{Kind: kind, Message: message}
 }
 
 // Error implements the error interface
 func (pe *PasswordError) Error() string {
 	return pe.Message
 }
+
+// ErrorKindOf returns the kind of the first PasswordError in err's chain, or
+// KindInternal when there is none
+func ErrorKindOf(err error) ErrorKind {
+	var pe *PasswordError
+	if errors.As(err, &pe) {
+		return pe.Kind
+	}
+	return KindInternal
+}
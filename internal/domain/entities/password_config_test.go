@@ -1,6 +1,8 @@
 package entities
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -81,3 +83,18 @@ func TestPasswordConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorKindOf(t *testing.T) {
+	if got := ErrorKindOf(PasswordConfig{Length: 0, IncludeLower: true, Count: 1}.Validate()); got != KindInvalidInput {
+		t.Errorf("ErrorKindOf(invalid config) = %v, want KindInvalidInput", got)
+	}
+
+	wrapped := fmt.Errorf("generating password: %w", NewPasswordErrorOfKind(KindBreached, "breached"))
+	if got := ErrorKindOf(wrapped); got != KindBreached {
+		t.Errorf("ErrorKindOf(wrapped) = %v, want KindBreached", got)
+	}
+
+	if got := ErrorKindOf(errors.New("disk full")); got != KindInternal {
+		t.Errorf("ErrorKindOf(plain error) = %v, want KindInternal", got)
+	}
+}
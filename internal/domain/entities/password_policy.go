@@ -0,0 +1,56 @@
+package entities
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PasswordPolicy describes the rules a site or organization enforces on
+// user-chosen passwords, so they can be audited against a standard
+type PasswordPolicy struct {
+	MinLength      int  `json:"min_length"`
+	MaxLength      int  `json:"max_length"`      // 0 means unlimited
+	RequireLetters bool `json:"require_letters"` // at least one letter of either case
+	RequireLower   bool `json:"require_lower"`
+	RequireUpper   bool `json:"require_upper"`
+	RequireNumbers bool `json:"require_numbers"`
+	RequireSymbols bool `json:"require_symbols"`
+	MinCharClasses int  `json:"min_char_classes"` // e.g. "3 of 4 character types"
+	AllowSpaces    bool `json:"allow_spaces"`
+	AllowUnicode   bool `json:"allow_unicode"`
+	ExpiryDays     int  `json:"expiry_days"` // forced rotation interval; 0 means never
+	History        int  `json:"history"`     // previous passwords that may not be reused
+	Blocklist      bool `json:"blocklist"`   // candidates are checked against breached and common passwords
+	AllowHints     bool `json:"allow_hints"`
+	SecurityQuests bool `json:"security_questions"` // knowledge-based prompts such as "first pet"
+}
+
+// ParsePasswordPolicy reads a policy from JSON, rejecting unknown fields so a
+// misspelled rule is not silently ignored
+func ParsePasswordPolicy(r io.Reader) (PasswordPolicy, error) {
+	var policy PasswordPolicy
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&policy); err != nil {
+		return PasswordPolicy{}, NewPasswordError(fmt.Sprintf("invalid password policy: %v", err))
+	}
+	if err := policy.Validate(); err != nil {
+		return PasswordPolicy{}, err
+	}
+	return policy, nil
+}
+
+// Validate ensures the policy's values are consistent
+func (pp PasswordPolicy) Validate() error {
+	if pp.MinLength < 0 || pp.MaxLength < 0 || pp.ExpiryDays < 0 || pp.MinCharClasses < 0 || pp.History < 0 {
+		return NewPasswordError("invalid password policy: values must not be negative")
+	}
+	if pp.MinCharClasses > 4 {
+		return NewPasswordError("invalid password policy: min_char_classes cannot exceed 4")
+	}
+	if pp.MaxLength > 0 && pp.MaxLength < pp.MinLength {
+		return NewPasswordError("invalid password policy: max_length is below min_length")
+	}
+	return nil
+}
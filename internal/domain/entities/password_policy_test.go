@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePasswordPolicy(t *testing.T) {
+	policy, err := ParsePasswordPolicy(strings.NewReader(`{"min_length": 12, "max_length": 128, "require_symbols": true, "expiry_days": 90}`))
+	if err != nil {
+		t.Fatalf("ParsePasswordPolicy() error = %v", err)
+	}
+	want := PasswordPolicy{MinLength: 12, MaxLength: 128, RequireSymbols: true, ExpiryDays: 90}
+	if policy != want {
+		t.Errorf("ParsePasswordPolicy() = %+v, want %+v", policy, want)
+	}
+
+	for _, input := range []string{
+		`{"min_lenght": 12}`,
+		`{"min_length": -1}`,
+		`{"min_length": 20, "max_length": 10}`,
+		`not json`,
+	} {
+		if _, err := ParsePasswordPolicy(strings.NewReader(input)); err == nil {
+			t.Errorf("ParsePasswordPolicy(%s) succeeded, want an error", input)
+		}
+	}
+}
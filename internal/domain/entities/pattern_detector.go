@@ -2,9 +2,10 @@ package entities
 
 import (
 	"fmt"
+	"math"
 	"regexp"
-	"strings"
-	"unicode"
+	"time"
+	"unicode/utf8"
 )
 
 // PasswordPattern represents a detected pattern in a password
@@ -13,14 +14,46 @@ type PasswordPattern struct {
 	Description string
 	Severity    string // "high", "medium", "low"
 	Suggestion  string
+	Start       int     // rune index of the first character of the finding
+	End         int     // rune index of the last character (inclusive)
+	Guesses     float64 // candidates an attacker tries for this segment; 0 when not estimated
+}
+
+// severityForGuesses rates a finding by how quickly its segment is guessed
+func severityForGuesses(guesses float64) string {
+	switch {
+	case guesses < 1e4:
+		return "high"
+	case guesses < 1e7:
+		return "medium"
+	default:
+		return "low"
+	}
 }
 
 // PasswordPatternDetector detects common patterns in passwords
-type PasswordPatternDetector struct{}
+type PasswordPatternDetector struct {
+	commonWords *BannedWordMatcher
+	bannedWords *BannedWordMatcher
+	keyboards   []*KeyboardGraph
+	// referenceYear anchors which years are plausible and how far from now
+	// an attacker has to search
+	referenceYear int
+}
 
 // NewPasswordPatternDetector creates a new password pattern detector
 func NewPasswordPatternDetector() *PasswordPatternDetector {
-	return &PasswordPatternDetector{}
+	return &PasswordPatternDetector{
+		commonWords:   NewBannedWordMatcher(0, DefaultBannedWordList()),
+		keyboards:     KeyboardGraphs(),
+		referenceYear: time.Now().Year(),
+	}
+}
+
+// SetBannedWords adds context-specific banned words to the checks
+func (ppd *PasswordPatternDetector) SetBannedWords(matcher *BannedWordMatcher) *PasswordPatternDetector {
+	ppd.bannedWords = matcher
+	return ppd
 }
 
 // DetectPatterns analyzes a password for common patterns
@@ -30,172 +63,158 @@ func (ppd *PasswordPatternDetector) DetectPatterns(password string) []PasswordPa
 	// Check for common patterns
 	patterns = append(patterns, ppd.checkSequential(password)...)
 	patterns = append(patterns, ppd.checkRepeating(password)...)
+	patterns = append(patterns, ppd.checkMirrored(password)...)
 	patterns = append(patterns, ppd.checkCommonWords(password)...)
 	patterns = append(patterns, ppd.checkKeyboardPatterns(password)...)
-	patterns = append(patterns, ppd.checkDatePatterns(password)...)
+	patterns = append(patterns, ppd.checkPersonalData(password)...)
 	patterns = append(patterns, ppd.checkNumberPatterns(password)...)
 
 	return patterns
 }
 
-// checkSequential detects sequential characters (abc, 123, etc.)
+// checkSequential detects ascending and descending runs of letters or
+// numbers with a constant step (abc, 7531, zyx), wrapping at the end of the
+// alphabet (xyzab, 8901)
 func (ppd *PasswordPatternDetector) checkSequential(password string) []PasswordPattern {
-	var patterns []PasswordPattern
-
-	// Check for sequential letters
-	if matched, _ := regexp.MatchString(`(?i)[a-z]{3,}`, password); matched {
-		// Check if they are actually sequential
-		lower := strings.ToLower(password)
-		for i := 0; i < len(lower)-2; i++ {
-			if lower[i+1] == lower[i]+1 && lower[i+2] == lower[i]+2 {
-				patterns = append(patterns, PasswordPattern{
-					Type:        "sequential_letters",
-					Description: "Contains sequential letters (e.g., abc, def)",
-					Severity:    "medium",
-					Suggestion:  "Avoid using sequential letters in passwords",
-				})
-				break
-			}
-		}
-	}
+	return findSequences([]rune(password))
+}
 
-	// Check for sequential numbers
-	if matched, _ := regexp.MatchString(`\d{3,}`, password); matched {
-		for i := 0; i < len(password)-2; i++ {
-			if unicode.IsDigit(rune(password[i])) && unicode.IsDigit(rune(password[i+1])) && unicode.IsDigit(rune(password[i+2])) {
-				if password[i+1] == password[i]+1 && password[i+2] == password[i]+2 {
-					patterns = append(patterns, PasswordPattern{
-						Type:        "sequential_numbers",
-						Description: "Contains sequential numbers (e.g., 123, 456)",
-						Severity:    "medium",
-						Suggestion:  "Avoid using sequential numbers in passwords",
-					})
-					break
-				}
-			}
-		}
-	}
+// checkRepeating detects repeated characters (aaa) and repeated blocks
+// (abcabc, 123123)
+func (ppd *PasswordPatternDetector) checkRepeating(password string) []PasswordPattern {
+	return findRepeats([]rune(password))
+}
 
-	return patterns
+// checkMirrored detects segments that read the same backwards (1221, abccba)
+func (ppd *PasswordPatternDetector) checkMirrored(password string) []PasswordPattern {
+	return findMirrors([]rune(password))
 }
 
-// checkRepeating detects repeating characters
-func (ppd *PasswordPatternDetector) checkRepeating(password string) []PasswordPattern {
+// checkCommonWords detects common words and any configured banned words,
+// including l33t-spelled and slightly misspelled forms
+func (ppd *PasswordPatternDetector) checkCommonWords(password string) []PasswordPattern {
 	var patterns []PasswordPattern
 
-	// Check for 3+ repeating characters
-	if matched, _ := regexp.MatchString(`(.)\\1{2,}`, password); matched {
+	for _, match := range ppd.commonWords.Match(password) {
+		patterns = append(patterns, PasswordPattern{
+			Type:        "common_word",
+			Description: fmt.Sprintf("Contains common word: '%s'", match.Word),
+			Severity:    "high",
+			Suggestion:  "Avoid using common words in passwords",
+			Start:       match.Start,
+			End:         match.End,
+		})
+	}
+
+	for _, match := range ppd.bannedWords.Match(password) {
 		patterns = append(patterns, PasswordPattern{
-			Type:        "repeating_chars",
-			Description: "Contains repeating characters (e.g., aaa, 111)",
+			Type:        "banned_word",
+			Description: fmt.Sprintf("Contains banned word from the %s list: '%s'", match.List, match.Word),
 			Severity:    "high",
-			Suggestion:  "Avoid repeating the same character multiple times",
+			Suggestion:  "Avoid names and words tied to your organization or its surroundings",
+			Start:       match.Start,
+			End:         match.End,
 		})
 	}
 
 	return patterns
 }
 
-// checkCommonWords detects common words and patterns
-func (ppd *PasswordPatternDetector) checkCommonWords(password string) []PasswordPattern {
+// checkKeyboardPatterns detects walks across adjacent keys on any supported
+// layout, in any direction and with any mix of shifted keys. Walks inside a
+// date, year, phone number or postcode are left to checkPersonalData, which
+// explains them better.
+func (ppd *PasswordPatternDetector) checkKeyboardPatterns(password string) []PasswordPattern {
 	var patterns []PasswordPattern
-	lower := strings.ToLower(password)
-
-	commonWords := []string{
-		"password", "admin", "user", "login", "welcome", "qwerty",
-		"letmein", "monkey", "dragon", "master", "shadow", "123456",
-		"password123", "admin123", "root", "toor", "guest",
-	}
+	personalData := FindPersonalData(password, ppd.referenceYear)
 
-	for _, word := range commonWords {
-		if strings.Contains(lower, word) {
-			patterns = append(patterns, PasswordPattern{
-				Type:        "common_word",
-				Description: fmt.Sprintf("Contains common word: '%s'", word),
-				Severity:    "high",
-				Suggestion:  "Avoid using common words in passwords",
-			})
+	for _, walk := range DetectKeyboardWalks(password, ppd.keyboards) {
+		if coveredByPersonalData(walk, personalData) {
+			continue
 		}
+		patterns = append(patterns, PasswordPattern{
+			Type: "keyboard_pattern",
+			Description: fmt.Sprintf("Contains keyboard pattern: '%s' (%s walk, %d keys, %d turns, ~10^%.1f guesses)",
+				walk.Token, walk.Graph, walk.Length(), walk.Turns, math.Log10(walk.Guesses)),
+			Severity:   severityForGuesses(walk.Guesses),
+			Suggestion: "Avoid using keyboard patterns in passwords",
+			Start:      walk.Start,
+			End:        walk.End,
+			Guesses:    walk.Guesses,
+		})
 	}
 
 	return patterns
 }
 
-// checkKeyboardPatterns detects keyboard patterns
-func (ppd *PasswordPatternDetector) checkKeyboardPatterns(password string) []PasswordPattern {
-	var patterns []PasswordPattern
-	lower := strings.ToLower(password)
-
-	keyboardPatterns := []string{
-		"qwerty", "asdf", "zxcv", "1234", "qwertyuiop",
-		"asdfghjkl", "zxcvbnm", "!@#$", "!@#$%^&*",
-	}
-
-	for _, pattern := range keyboardPatterns {
-		if strings.Contains(lower, pattern) {
-			patterns = append(patterns, PasswordPattern{
-				Type:        "keyboard_pattern",
-				Description: fmt.Sprintf("Contains keyboard pattern: '%s'", pattern),
-				Severity:    "medium",
-				Suggestion:  "Avoid using keyboard patterns in passwords",
-			})
+// coveredByPersonalData reports whether walk lies entirely within one of
+// matches
+func coveredByPersonalData(walk KeyboardWalk, matches []PersonalDataMatch) bool {
+	for _, match := range matches {
+		if match.Start <= walk.Start && walk.End <= match.End {
+			return true
 		}
 	}
+	return false
+}
 
-	return patterns
+// personalDataPatterns describes each kind of personal data finding
+var personalDataPatterns = map[string]struct {
+	patternType, description, suggestion string
+}{
+	PersonalDataDate:     {"date_pattern", "Contains date", "Avoid using dates in passwords"},
+	PersonalDataYear:     {"year_pattern", "Contains year", "Avoid using years in passwords"},
+	PersonalDataPhone:    {"phone_pattern", "Contains phone number", "Avoid phone numbers, especially your own"},
+	PersonalDataPostcode: {"postcode_pattern", "Contains postcode", "Avoid postcodes, especially your own"},
 }
 
-// checkDatePatterns detects date-like patterns
-func (ppd *PasswordPatternDetector) checkDatePatterns(password string) []PasswordPattern {
+// checkPersonalData detects calendar dates, years, phone numbers and
+// postcodes, rating each by how few guesses its shape leaves an attacker
+func (ppd *PasswordPatternDetector) checkPersonalData(password string) []PasswordPattern {
 	var patterns []PasswordPattern
 
-	datePatterns := []string{
-		`\d{2}/\d{2}/\d{4}`, // MM/DD/YYYY
-		`\d{2}-\d{2}-\d{4}`, // MM-DD-YYYY
-		`\d{4}/\d{2}/\d{2}`, // YYYY/MM/DD
-		`\d{4}-\d{2}-\d{2}`, // YYYY-MM-DD
-		`\d{2}\d{2}\d{4}`,   // MMDDYYYY
-		`\d{4}\d{2}\d{2}`,   // YYYYMMDD
-	}
-
-	for _, pattern := range datePatterns {
-		if matched, _ := regexp.MatchString(pattern, password); matched {
-			patterns = append(patterns, PasswordPattern{
-				Type:        "date_pattern",
-				Description: "Contains date-like pattern",
-				Severity:    "medium",
-				Suggestion:  "Avoid using dates in passwords",
-			})
-			break
+	for _, match := range FindPersonalData(password, ppd.referenceYear) {
+		kind := personalDataPatterns[match.Kind]
+		description := fmt.Sprintf("%s: '%s'", kind.description, match.Token)
+		if match.Format != "" {
+			description += fmt.Sprintf(" (%s)", match.Format)
 		}
+		patterns = append(patterns, PasswordPattern{
+			Type:        kind.patternType,
+			Description: description,
+			Severity:    severityForGuesses(match.Guesses),
+			Suggestion:  kind.suggestion,
+			Start:       match.Start,
+			End:         match.End,
+			Guesses:     match.Guesses,
+		})
 	}
 
 	return patterns
 }
 
-// checkNumberPatterns detects simple number patterns
+// checkNumberPatterns detects numbers tacked onto the end of a password
 func (ppd *PasswordPatternDetector) checkNumberPatterns(password string) []PasswordPattern {
 	var patterns []PasswordPattern
 
 	// Check for all numbers at the end (common pattern)
-	if matched, _ := regexp.MatchString(`[a-zA-Z]+\d+$`, password); matched {
+	if loc := regexp.MustCompile(`[a-zA-Z]+(\d+)$`).FindStringSubmatchIndex(password); loc != nil {
+		start, end := runeSpan(password, loc[2], loc[3])
 		patterns = append(patterns, PasswordPattern{
 			Type:        "numbers_at_end",
 			Description: "Numbers only at the end of password",
 			Severity:    "low",
 			Suggestion:  "Consider mixing numbers throughout the password",
-		})
-	}
-
-	// Check for simple year patterns
-	if matched, _ := regexp.MatchString(`(19|20)\d{2}`, password); matched {
-		patterns = append(patterns, PasswordPattern{
-			Type:        "year_pattern",
-			Description: "Contains year-like pattern",
-			Severity:    "medium",
-			Suggestion:  "Avoid using years in passwords",
+			Start:       start,
+			End:         end,
 		})
 	}
 
 	return patterns
 }
+
+// runeSpan converts a regexp match's byte offsets into the rune indexes of
+// its first and last characters
+func runeSpan(password string, from, to int) (start, end int) {
+	return utf8.RuneCountInString(password[:from]), utf8.RuneCountInString(password[:to]) - 1
+}
@@ -0,0 +1,325 @@
+package entities
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of personal data FindPersonalData recognizes
+const (
+	PersonalDataDate     = "date"
+	PersonalDataYear     = "year"
+	PersonalDataPhone    = "phone"
+	PersonalDataPostcode = "postcode"
+)
+
+// Limits for personal data detection
+const (
+	minPlausibleYear = 1900 // birth years and anniversaries rarely predate this
+	futureYearMargin = 10   // years this far ahead still read as a year
+	minYearGuesses   = 20   // attackers try at least this many years around now
+)
+
+// Postcode shapes, with roughly how many codes are in use
+var postcodeShapes = []struct {
+	name    string
+	pattern *regexp.Regexp
+	codes   float64
+}{
+	{"US ZIP+4", regexp.MustCompile(`\d{5}-\d{4}`), 42000 * 10000},
+	{"US ZIP", regexp.MustCompile(`\d{5}`), 42000},
+	{"UK", regexp.MustCompile(`[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}`), 1.8e6},
+	{"Canadian", regexp.MustCompile(`[A-Z]\d[A-Z] ?\d[A-Z]\d`), 9e5},
+}
+
+// phonePatterns match digit groups laid out like phone numbers: an optional
+// country code, then area code, exchange and line number, or an
+// international number written with a leading +
+var phonePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?\(?\d{3}\)?[ .-]?\d{3}[ .-]?\d{4}`),
+	regexp.MustCompile(`\+\d{8,14}`),
+}
+
+// PersonalDataMatch is a date, year, phone number or postcode found in a
+// password
+type PersonalDataMatch struct {
+	Kind      string
+	Start     int // rune index of the first character
+	End       int // rune index of the last character (inclusive)
+	Token     string
+	Format    string // date order such as "DMY", or postcode region such as "UK"
+	Year      int
+	Month     int
+	Day       int
+	Separator string  // date separator; empty when the date has none
+	Guesses   float64 // how many candidates of this shape an attacker tries
+}
+
+// IsCalendarDate reports whether the day exists in that month and year
+func IsCalendarDate(year, month, day int) bool {
+	if month < 1 || month > 12 || day < 1 {
+		return false
+	}
+	// Day zero of the following month is the last day of this one
+	return day <= time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// FindPersonalData finds calendar dates in DMY, MDY or YMD order with 2- or
+// 4-digit years, standalone years, phone numbers and postcodes. Years count
+// as plausible from 1900 to a decade after referenceYear. Where findings
+// overlap, the longest and then the cheapest to guess is kept.
+func FindPersonalData(password string, referenceYear int) []PersonalDataMatch {
+	runes := []rune(password)
+
+	var candidates []PersonalDataMatch
+	candidates = append(candidates, findDates(runes, referenceYear)...)
+	candidates = append(candidates, findYears(runes, referenceYear)...)
+	candidates = append(candidates, findPhoneNumbers(password)...)
+	candidates = append(candidates, findPostcodes(password)...)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		li, lj := candidates[i].End-candidates[i].Start, candidates[j].End-candidates[j].Start
+		if li != lj {
+			return li > lj
+		}
+		return candidates[i].Guesses < candidates[j].Guesses
+	})
+
+	var matches []PersonalDataMatch
+	for _, candidate := range candidates {
+		overlaps := false
+		for _, m := range matches {
+			if candidate.Start <= m.End && m.Start <= candidate.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			matches = append(matches, candidate)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	return matches
+}
+
+// findDates tries every digit-bounded substring as a date. Without
+// separators day and month must be zero-padded, so only six- and eight-digit
+// runs qualify; with a separator they may be one digit.
+func findDates(runes []rune, referenceYear int) []PersonalDataMatch {
+	var matches []PersonalDataMatch
+	for i := range runes {
+		if !isASCIIDigit(runes[i]) || (i > 0 && isASCIIDigit(runes[i-1])) {
+			continue
+		}
+		for j := i + 5; j < len(runes) && j <= i+9; j++ {
+			if !isASCIIDigit(runes[j]) || (j+1 < len(runes) && isASCIIDigit(runes[j+1])) {
+				continue
+			}
+			token := string(runes[i : j+1])
+			year, month, day, format, separator, ok := parseDate(token, referenceYear)
+			if !ok {
+				continue
+			}
+			guesses := yearGuesses(year, referenceYear) * 365
+			if separator != "" {
+				guesses *= 4
+			}
+			matches = append(matches, PersonalDataMatch{
+				Kind: PersonalDataDate, Start: i, End: j, Token: token, Format: format,
+				Year: year, Month: month, Day: day, Separator: separator, Guesses: guesses,
+			})
+		}
+	}
+	return matches
+}
+
+// parseDate reads token as a date in YMD, DMY or MDY order, trying them in
+// that order
+func parseDate(token string, referenceYear int) (year, month, day int, format, separator string, ok bool) {
+	var parts []string
+	for _, r := range token {
+		if isASCIIDigit(r) {
+			continue
+		}
+		if !strings.ContainsRune("/-._ ", r) || (separator != "" && string(r) != separator) {
+			return 0, 0, 0, "", "", false
+		}
+		separator = string(r)
+	}
+
+	if separator != "" {
+		parts = strings.Split(token, separator)
+		if len(parts) != 3 {
+			return 0, 0, 0, "", "", false
+		}
+	} else {
+		switch len(token) {
+		case 6:
+			parts = []string{token[:2], token[2:4], token[4:]}
+		case 8:
+			// Split twice so both YYYYMMDD and DDMMYYYY can be tried
+			if y, m, d, f, ok := parseDateParts([]string{token[:4], token[4:6], token[6:]}, referenceYear); ok {
+				return y, m, d, f, "", true
+			}
+			parts = []string{token[:2], token[2:4], token[4:]}
+		default:
+			return 0, 0, 0, "", "", false
+		}
+	}
+
+	year, month, day, format, ok = parseDateParts(parts, referenceYear)
+	return year, month, day, format, separator, ok
+}
+
+// parseDateParts interprets three digit groups as a calendar date
+func parseDateParts(parts []string, referenceYear int) (year, month, day int, format string, ok bool) {
+	for _, p := range parts {
+		if p == "" || len(p) > 4 {
+			return 0, 0, 0, "", false
+		}
+	}
+
+	orders := []struct {
+		format           string
+		year, month, day int
+	}{
+		{"YMD", 0, 1, 2},
+		{"DMY", 2, 1, 0},
+		{"MDY", 2, 0, 1},
+	}
+	for _, order := range orders {
+		y, m, d := parts[order.year], parts[order.month], parts[order.day]
+		if (len(y) != 2 && len(y) != 4) || len(m) > 2 || len(d) > 2 {
+			continue
+		}
+		year = expandYear(atoiDigits(y), len(y), referenceYear)
+		month, day = atoiDigits(m), atoiDigits(d)
+		if plausibleYear(year, referenceYear) && IsCalendarDate(year, month, day) {
+			return year, month, day, order.format, true
+		}
+	}
+	return 0, 0, 0, "", false
+}
+
+// findYears finds four-digit years that stand apart from other digits
+func findYears(runes []rune, referenceYear int) []PersonalDataMatch {
+	var matches []PersonalDataMatch
+	for i := 0; i+4 <= len(runes); i++ {
+		token := string(runes[i : i+4])
+		if !isDigitString(token) || (i > 0 && isASCIIDigit(runes[i-1])) || (i+4 < len(runes) && isASCIIDigit(runes[i+4])) {
+			continue
+		}
+		year := atoiDigits(token)
+		if !plausibleYear(year, referenceYear) {
+			continue
+		}
+		matches = append(matches, PersonalDataMatch{
+			Kind: PersonalDataYear, Start: i, End: i + 3, Token: token,
+			Year: year, Guesses: yearGuesses(year, referenceYear),
+		})
+	}
+	return matches
+}
+
+// findPhoneNumbers finds digit groups shaped like phone numbers. Dialing
+// plans leave the leading digits little freedom, so a number of n digits is
+// taken as one of about 10^(n-1).
+func findPhoneNumbers(password string) []PersonalDataMatch {
+	var matches []PersonalDataMatch
+	for _, pattern := range phonePatterns {
+		for _, loc := range pattern.FindAllStringIndex(password, -1) {
+			if !digitBounded(password, loc[0], loc[1]) {
+				continue
+			}
+			token := password[loc[0]:loc[1]]
+			digits := 0
+			for _, r := range token {
+				if isASCIIDigit(r) {
+					digits++
+				}
+			}
+			start, end := runeSpan(password, loc[0], loc[1])
+			matches = append(matches, PersonalDataMatch{
+				Kind: PersonalDataPhone, Start: start, End: end, Token: token,
+				Guesses: math.Pow(10, float64(digits-1)),
+			})
+		}
+	}
+	return matches
+}
+
+// findPostcodes finds US ZIP codes and UK and Canadian postcodes
+func findPostcodes(password string) []PersonalDataMatch {
+	var matches []PersonalDataMatch
+	for _, shape := range postcodeShapes {
+		for _, loc := range shape.pattern.FindAllStringIndex(password, -1) {
+			if !digitBounded(password, loc[0], loc[1]) {
+				continue
+			}
+			start, end := runeSpan(password, loc[0], loc[1])
+			matches = append(matches, PersonalDataMatch{
+				Kind: PersonalDataPostcode, Start: start, End: end, Token: password[loc[0]:loc[1]],
+				Format: shape.name, Guesses: shape.codes,
+			})
+		}
+	}
+	return matches
+}
+
+// plausibleYear reports whether year is one a person would put in a password
+func plausibleYear(year, referenceYear int) bool {
+	return year >= minPlausibleYear && year <= referenceYear+futureYearMargin
+}
+
+// expandYear turns a two-digit year into the plausible century
+func expandYear(year, digits, referenceYear int) int {
+	if digits != 2 {
+		return year
+	}
+	if 2000+year <= referenceYear+futureYearMargin {
+		return 2000 + year
+	}
+	return 1900 + year
+}
+
+// yearGuesses counts the years an attacker tries before reaching year,
+// working outwards from referenceYear
+func yearGuesses(year, referenceYear int) float64 {
+	return math.Max(math.Abs(float64(year-referenceYear)), minYearGuesses)
+}
+
+// digitBounded reports whether the byte range is not part of a longer run of
+// digits
+func digitBounded(s string, from, to int) bool {
+	return (from == 0 || !isASCIIDigit(rune(s[from-1]))) && (to == len(s) || !isASCIIDigit(rune(s[to])))
+}
+
+// isASCIIDigit reports whether r is 0-9; other scripts' digits don't form dates
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// isDigitString reports whether s is a non-empty run of ASCII digits
+func isDigitString(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !isASCIIDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// atoiDigits converts a run of ASCII digits to an int
+func atoiDigits(s string) int {
+	n := 0
+	for _, r := range s {
+		n = n*10 + int(r-'0')
+	}
+	return n
+}
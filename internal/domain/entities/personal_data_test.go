@@ -0,0 +1,83 @@
+package entities
+
+import (
+	"testing"
+)
+
+func TestIsCalendarDate(t *testing.T) {
+	tests := []struct {
+		year, month, day int
+		want             bool
+	}{
+		{2024, 2, 29, true},
+		{2023, 2, 29, false},
+		{1900, 2, 29, false},
+		{2000, 2, 29, true},
+		{1987, 4, 31, false},
+		{1987, 12, 31, true},
+		{1987, 13, 1, false},
+		{1987, 1, 0, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCalendarDate(tt.year, tt.month, tt.day); got != tt.want {
+			t.Errorf("IsCalendarDate(%d, %d, %d) = %v, want %v", tt.year, tt.month, tt.day, got, tt.want)
+		}
+	}
+}
+
+func TestFindPersonalData(t *testing.T) {
+	const referenceYear = 2025
+
+	tests := []struct {
+		name       string
+		password   string
+		wantKinds  []string
+		wantTokens []string
+	}{
+		{"YMD without separators", "anna19870819", []string{PersonalDataDate}, []string{"19870819"}},
+		{"DMY with separators", "x19/08/1987", []string{PersonalDataDate}, []string{"19/08/1987"}},
+		{"MDY with two-digit year", "12-25-99!", []string{PersonalDataDate}, []string{"12-25-99"}},
+		{"invalid calendar date", "30021990", nil, nil},
+		{"eight random digits", "48213977", nil, nil},
+		{"standalone year", "Summer2024!", []string{PersonalDataYear}, []string{"2024"}},
+		{"year inside longer number", "120199834", nil, nil},
+		{"implausible year", "pass2199", nil, nil},
+		{"phone number", "call555-867-5309", []string{PersonalDataPhone}, []string{"555-867-5309"}},
+		{"international phone", "+447911123456", []string{PersonalDataPhone}, []string{"+447911123456"}},
+		{"US ZIP", "home90210", []string{PersonalDataPostcode}, []string{"90210"}},
+		{"UK postcode", "SW1A1AAlondon", []string{PersonalDataPostcode}, []string{"SW1A1AA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := FindPersonalData(tt.password, referenceYear)
+			if len(matches) != len(tt.wantKinds) {
+				t.Fatalf("FindPersonalData(%q) = %+v, want %v", tt.password, matches, tt.wantTokens)
+			}
+			for i, match := range matches {
+				if match.Kind != tt.wantKinds[i] || match.Token != tt.wantTokens[i] {
+					t.Errorf("match %d = %s %q, want %s %q", i, match.Kind, match.Token, tt.wantKinds[i], tt.wantTokens[i])
+				}
+				if match.Guesses <= 0 {
+					t.Errorf("match %d guesses = %v, want > 0", i, match.Guesses)
+				}
+			}
+		})
+	}
+}
+
+func TestFindPersonalData_DateFields(t *testing.T) {
+	matches := FindPersonalData("19/08/1987", 2025)
+	if len(matches) != 1 {
+		t.Fatalf("FindPersonalData() = %+v, want one date", matches)
+	}
+	m := matches[0]
+	if m.Year != 1987 || m.Month != 8 || m.Day != 19 || m.Format != "DMY" || m.Separator != "/" {
+		t.Errorf("date = %d-%d-%d %s sep %q, want 1987-8-19 DMY sep /", m.Year, m.Month, m.Day, m.Format, m.Separator)
+	}
+	// 38 years from the reference year, any day, four separators
+	if want := 38.0 * 365 * 4; m.Guesses != want {
+		t.Errorf("Guesses = %v, want %v", m.Guesses, want)
+	}
+}
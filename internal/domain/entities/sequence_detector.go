@@ -0,0 +1,211 @@
+package entities
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Sequence, repeat and mirror detection limits
+const (
+	minSequenceLength     = 3 // for step ±1; larger steps need one character more
+	maxSequenceStep       = 3 // steps beyond this read as unrelated characters
+	minRepeatedRunLength  = 3 // aaa
+	minRepeatedBlockCount = 2 // abcabc
+	minMirrorLength       = 4 // 1221, abccba
+)
+
+// sequenceAlphabets are the orderings a sequence can follow; each wraps
+// around, so xyzab and 7890 are sequences too
+var sequenceAlphabets = []struct {
+	name    string
+	letters string
+}{
+	{"letters", "abcdefghijklmnopqrstuvwxyz"},
+	{"numbers", "0123456789"},
+}
+
+// sequencePosition places a character in one of sequenceAlphabets, ignoring
+// case; ok is false for characters outside all of them
+func sequencePosition(r rune) (alphabet, index int, ok bool) {
+	r = unicode.ToLower(r)
+	for i, a := range sequenceAlphabets {
+		if idx := strings.IndexRune(a.letters, r); idx >= 0 {
+			return i, idx, true
+		}
+	}
+	return 0, 0, false
+}
+
+// findSequences returns the maximal runs whose characters advance through one
+// alphabet by the same step, ascending or descending
+func findSequences(runes []rune) []PasswordPattern {
+	var patterns []PasswordPattern
+
+	for i := 0; i < len(runes)-1; {
+		alphabet, first, ok := sequencePosition(runes[i])
+		if !ok {
+			i++
+			continue
+		}
+		size := len(sequenceAlphabets[alphabet].letters)
+
+		step, j, previous := 0, i+1, first
+		for ; j < len(runes); j++ {
+			a, index, ok := sequencePosition(runes[j])
+			if !ok || a != alphabet {
+				break
+			}
+			// Normalize the step into (-size/2, size/2] so wrapping counts
+			delta := ((index-previous)%size + size) % size
+			if delta > size/2 {
+				delta -= size
+			}
+			if j == i+1 {
+				step = delta
+			}
+			if delta != step || step == 0 || step > maxSequenceStep || step < -maxSequenceStep {
+				break
+			}
+			previous = index
+		}
+
+		minLength := minSequenceLength
+		if step != 1 && step != -1 {
+			minLength++
+		}
+		if j-i < minLength {
+			i++
+			continue
+		}
+
+		direction := "ascending"
+		if step < 0 {
+			direction = "descending"
+		}
+		kind := sequenceAlphabets[alphabet].name
+		patterns = append(patterns, PasswordPattern{
+			Type:        "sequential_" + kind,
+			Description: fmt.Sprintf("Contains %s sequence of %s: '%s' (step %d)", direction, kind, string(runes[i:j]), step),
+			Severity:    "medium",
+			Suggestion:  fmt.Sprintf("Avoid using sequential %s in passwords", kind),
+			Start:       i,
+			End:         j - 1,
+		})
+		// The last character may start the next run
+		i = j - 1
+	}
+
+	return patterns
+}
+
+// findRepeats returns runs of one repeated character and blocks repeated back
+// to back. At each position the longest repetition wins, using the shortest
+// block that produces it.
+func findRepeats(runes []rune) []PasswordPattern {
+	var patterns []PasswordPattern
+
+	for i := 0; i < len(runes)-1; {
+		bestBase, bestCount := 0, 0
+		for base := 1; base <= (len(runes)-i)/2; base++ {
+			count := 1
+			for i+(count+1)*base <= len(runes) && string(runes[i:i+base]) == string(runes[i+count*base:i+(count+1)*base]) {
+				count++
+			}
+			if count > 1 && count*base > bestCount*bestBase {
+				bestBase, bestCount = base, count
+			}
+		}
+
+		if bestBase == 1 && bestCount >= minRepeatedRunLength {
+			patterns = append(patterns, PasswordPattern{
+				Type:        "repeating_chars",
+				Description: fmt.Sprintf("Contains repeating characters: '%s' x%d", string(runes[i]), bestCount),
+				Severity:    "high",
+				Suggestion:  "Avoid repeating the same character multiple times",
+				Start:       i,
+				End:         i + bestCount - 1,
+			})
+			i += bestCount
+			continue
+		}
+		if bestBase > 1 && bestCount >= minRepeatedBlockCount {
+			patterns = append(patterns, PasswordPattern{
+				Type:        "repeated_block",
+				Description: fmt.Sprintf("Contains repeated block: '%s' x%d", string(runes[i:i+bestBase]), bestCount),
+				Severity:    "high",
+				Suggestion:  "Avoid repeating the same group of characters",
+				Start:       i,
+				End:         i + bestBase*bestCount - 1,
+			})
+			i += bestBase * bestCount
+			continue
+		}
+		i++
+	}
+
+	return patterns
+}
+
+// findMirrors returns the longest non-overlapping palindromic segments, such
+// as 1221 or abccba. Segments of a single repeated character are left to
+// findRepeats.
+func findMirrors(runes []rune) []PasswordPattern {
+	type segment struct{ start, end int }
+	var candidates []segment
+
+	// Expand around every centre, odd and even length
+	for centre := 0; centre < 2*len(runes)-1; centre++ {
+		left, right := centre/2, centre/2+centre%2
+		for left >= 0 && right < len(runes) && unicode.ToLower(runes[left]) == unicode.ToLower(runes[right]) {
+			left--
+			right++
+		}
+		start, end := left+1, right-1
+		if end-start+1 >= minMirrorLength && !singleCharacter(runes[start:end+1]) {
+			candidates = append(candidates, segment{start, end})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].end-candidates[i].start > candidates[j].end-candidates[j].start
+	})
+
+	var chosen []segment
+	for _, candidate := range candidates {
+		overlaps := false
+		for _, s := range chosen {
+			if candidate.start <= s.end && s.start <= candidate.end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			chosen = append(chosen, candidate)
+		}
+	}
+	sort.Slice(chosen, func(i, j int) bool { return chosen[i].start < chosen[j].start })
+
+	var patterns []PasswordPattern
+	for _, s := range chosen {
+		patterns = append(patterns, PasswordPattern{
+			Type:        "mirrored",
+			Description: fmt.Sprintf("Contains mirrored segment: '%s'", string(runes[s.start:s.end+1])),
+			Severity:    "medium",
+			Suggestion:  "Avoid segments that read the same backwards",
+			Start:       s.start,
+			End:         s.end,
+		})
+	}
+	return patterns
+}
+
+// singleCharacter reports whether every rune of token is the same, ignoring case
+func singleCharacter(token []rune) bool {
+	for _, r := range token {
+		if unicode.ToLower(r) != unicode.ToLower(token[0]) {
+			return false
+		}
+	}
+	return true
+}
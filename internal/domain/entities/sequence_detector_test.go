@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"testing"
+)
+
+// span is a pattern type with the characters it covers
+type span struct {
+	Type       string
+	Start, End int
+}
+
+func spansOf(patterns []PasswordPattern) []span {
+	var spans []span
+	for _, p := range patterns {
+		spans = append(spans, span{p.Type, p.Start, p.End})
+	}
+	return spans
+}
+
+func equalSpans(a, b []span) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestFindSequences(t *testing.T) {
+	tests := []struct {
+		password string
+		want     []span
+	}{
+		{"abc", []span{{"sequential_letters", 0, 2}}},
+		{"x987!", []span{{"sequential_numbers", 1, 3}}},
+		{"Zyx", []span{{"sequential_letters", 0, 2}}},
+		{"ace", nil}, // step 2 needs four characters
+		{"1357", []span{{"sequential_numbers", 0, 3}}},
+		{"xyzab", []span{{"sequential_letters", 0, 4}}},
+		{"8901", []span{{"sequential_numbers", 0, 3}}},
+		{"a1b2c3", nil},
+		{"abcba", []span{{"sequential_letters", 0, 2}, {"sequential_letters", 2, 4}}},
+		{"aeim", nil}, // step 4 is too wide
+	}
+
+	for _, tt := range tests {
+		if got := spansOf(findSequences([]rune(tt.password))); !equalSpans(got, tt.want) {
+			t.Errorf("findSequences(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestFindRepeats(t *testing.T) {
+	tests := []struct {
+		password string
+		want     []span
+	}{
+		{"aaa", []span{{"repeating_chars", 0, 2}}},
+		{"xx111y", []span{{"repeating_chars", 2, 4}}},
+		{"abcabc", []span{{"repeated_block", 0, 5}}},
+		{"Pass123123", []span{{"repeated_block", 4, 9}}},
+		{"abab!!!!", []span{{"repeated_block", 0, 3}, {"repeating_chars", 4, 7}}},
+		{"aa", nil},
+		{"abcd", nil},
+	}
+
+	for _, tt := range tests {
+		if got := spansOf(findRepeats([]rune(tt.password))); !equalSpans(got, tt.want) {
+			t.Errorf("findRepeats(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestFindMirrors(t *testing.T) {
+	tests := []struct {
+		password string
+		want     []span
+	}{
+		{"1221", []span{{"mirrored", 0, 3}}},
+		{"xracecar!", []span{{"mirrored", 1, 7}}},
+		{"abccba12344321", []span{{"mirrored", 0, 5}, {"mirrored", 6, 13}}},
+		{"aaaa", nil},
+		{"aba", nil},
+		{"Abba", []span{{"mirrored", 0, 3}}},
+	}
+
+	for _, tt := range tests {
+		if got := spansOf(findMirrors([]rune(tt.password))); !equalSpans(got, tt.want) {
+			t.Errorf("findMirrors(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestPasswordPatternDetector_Spans(t *testing.T) {
+	detector := NewPasswordPatternDetector()
+
+	// Spans are rune indexes, so a multi-byte prefix shifts them by one
+	patterns := detector.DetectPatterns("é000qwerty")
+	want := map[string]span{
+		"repeating_chars":  {"repeating_chars", 1, 3},
+		"keyboard_pattern": {"keyboard_pattern", 4, 9},
+	}
+	for _, p := range patterns {
+		if w, ok := want[p.Type]; ok && (span{p.Type, p.Start, p.End}) != w {
+			t.Errorf("%s span = %d-%d, want %d-%d", p.Type, p.Start, p.End, w.Start, w.End)
+		}
+		delete(want, p.Type)
+	}
+	for missing := range want {
+		t.Errorf("DetectPatterns() missing %s", missing)
+	}
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// Runes that attach to the character before them or join two characters
+const (
+	zeroWidthJoiner     = '\u200D'
+	emojiModifierFirst  = '\U0001F3FB' // skin tone modifiers
+	emojiModifierLast   = '\U0001F3FF'
+	regionalIndicatorA  = '\U0001F1E6' // flags are pairs of regional indicators
+	regionalIndicatorZ  = '\U0001F1FF'
+	emojiTagFirst       = '\U000E0020' // tag sequences such as subdivision flags
+	emojiTagCancel      = '\U000E007F'
+	variationSelector16 = '\uFE0F' // requests emoji presentation
+)
+
+// Graphemes splits s into user-perceived characters: a base character with
+// the combining marks, variation selectors and emoji modifiers attached to
+// it, emoji joined by zero-width joiners, and flag pairs. This covers the
+// cases of Unicode's extended grapheme clusters (UAX #29) that occur in
+// passwords; rarer rules such as Hangul jamo sequences are not applied.
+func Graphemes(s string) []string {
+	var clusters []string
+	var current []rune
+	joined, flagOpen := false, false
+
+	for _, r := range s {
+		switch {
+		case len(current) > 0 && (extendsGrapheme(r) || r == zeroWidthJoiner):
+			joined = r == zeroWidthJoiner
+		case len(current) > 0 && joined:
+			joined = false
+		case len(current) > 0 && flagOpen && isRegionalIndicator(r):
+			flagOpen = false
+		default:
+			if len(current) > 0 {
+				clusters = append(clusters, string(current))
+			}
+			current = current[:0:0]
+			flagOpen = isRegionalIndicator(r)
+		}
+		current = append(current, r)
+	}
+	if len(current) > 0 {
+		clusters = append(clusters, string(current))
+	}
+	return clusters
+}
+
+// DisplayWidth returns the number of terminal columns s occupies: two for
+// wide and fullwidth characters such as CJK ideographs and most emoji, none
+// for marks and joiners, one for everything else
+func DisplayWidth(s string) int {
+	total := 0
+	for _, cluster := range Graphemes(s) {
+		runes := []rune(cluster)
+		switch {
+		case isRegionalIndicator(runes[0]), len(runes) > 1 && runes[1] == variationSelector16:
+			total += 2
+		case unicode.IsControl(runes[0]) || extendsGrapheme(runes[0]):
+			// A stray mark or control character has no column of its own
+		default:
+			switch width.LookupRune(runes[0]).Kind() {
+			case width.EastAsianWide, width.EastAsianFullwidth:
+				total += 2
+			default:
+				total++
+			}
+		}
+	}
+	return total
+}
+
+// extendsGrapheme reports whether r attaches to the character before it
+func extendsGrapheme(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		(r >= emojiModifierFirst && r <= emojiModifierLast) ||
+		(r >= emojiTagFirst && r <= emojiTagCancel)
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicatorA && r <= regionalIndicatorZ
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPassword_Unicode(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		wantValue     string
+		wantLength    int
+		wantGraphemes int
+		wantTypes     []string
+	}{
+		{"ASCII", "Passw0rd!", "Passw0rd!", 9, 9, []string{"Lowercase", "Uppercase", "Numbers", "Symbols"}},
+		{"umlauts", "Pässwörd", "Pässwörd", 8, 8, []string{"Lowercase", "Uppercase"}},
+		{"combining accent is composed", "cafe\u0301", "café", 4, 4, []string{"Lowercase"}},
+		{"fullwidth is folded", "ｐａｓｓ１２３", "pass123", 7, 7, []string{"Lowercase", "Numbers"}},
+		{"uncased script", "密码2024", "密码2024", 6, 6, []string{"Other letters", "Numbers"}},
+		{"emoji with skin tone", "ok👍🏽", "ok👍🏽", 4, 3, []string{"Lowercase", "Symbols"}},
+		{"Greek and Cyrillic", "ΣοφίαПривет", "ΣοφίαПривет", 11, 11, []string{"Lowercase", "Uppercase"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			password := NewPassword(tt.value)
+			if password.Value != tt.wantValue {
+				t.Errorf("Value = %q, want %q", password.Value, tt.wantValue)
+			}
+			if password.Length != tt.wantLength {
+				t.Errorf("Length = %d, want %d", password.Length, tt.wantLength)
+			}
+			if password.Graphemes != tt.wantGraphemes {
+				t.Errorf("Graphemes = %d, want %d", password.Graphemes, tt.wantGraphemes)
+			}
+			if types := password.GetCharacterTypes(); !reflect.DeepEqual(types, tt.wantTypes) {
+				t.Errorf("GetCharacterTypes() = %v, want %v", types, tt.wantTypes)
+			}
+		})
+	}
+}
+
+func TestGraphemes(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"abc", []string{"a", "b", "c"}},
+		{"e\u0301x", []string{"e\u0301", "x"}},
+		{"👨\u200d👩\u200d👧!", []string{"👨\u200d👩\u200d👧", "!"}},
+		{"🇩🇪🇫🇷", []string{"🇩🇪", "🇫🇷"}},
+		{"❤️", []string{"❤️"}},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		if got := Graphemes(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Graphemes(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"password", 8},
+		{"Pässwörd", 8},
+		{"e\u0301", 1},
+		{"密码", 4},
+		{"ｐａｓｓ", 8},
+		{"👍🏽", 2},
+		{"👨\u200d👩\u200d👧", 2},
+		{"🇩🇪", 2},
+		{"❤️", 2},
+	}
+
+	for _, tt := range tests {
+		if got := DisplayWidth(tt.value); got != tt.want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
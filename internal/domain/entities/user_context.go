@@ -0,0 +1,212 @@
+package entities
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// userContextDistance is the edit distance tolerated when comparing a
+// password with the account holder's details
+const userContextDistance = 1
+
+// userContextFields lists the context fields, most personal first
+var userContextFields = []string{"username", "email", "name", "organization"}
+
+// UserContext holds details about the account a password is meant for.
+// Passwords built from them are the first thing a targeted attacker tries.
+type UserContext struct {
+	Username     string
+	Email        string
+	Name         string
+	Organization string
+}
+
+// UserContextFinding is a resemblance between a password and a context value
+type UserContextFinding struct {
+	Field    string // "username", "email", "name" or "organization"
+	Value    string // the context value, as derived (e.g. an email's local part)
+	Token    string // the part of the password that resembles it
+	Kind     string // "contains", "leet", "similar", "reversed" or "initials"
+	Severity string // "high", "medium", "low"
+}
+
+// Description explains the finding for display
+func (f UserContextFinding) Description() string {
+	switch f.Kind {
+	case "leet":
+		return fmt.Sprintf("Contains your %s '%s' in l33t spelling ('%s')", f.Field, f.Value, f.Token)
+	case "similar":
+		return fmt.Sprintf("Closely resembles your %s '%s' ('%s')", f.Field, f.Value, f.Token)
+	case "reversed":
+		return fmt.Sprintf("Contains your %s '%s' reversed ('%s')", f.Field, f.Value, f.Token)
+	case "initials":
+		return fmt.Sprintf("Contains your initials followed by a year ('%s')", f.Token)
+	default:
+		return fmt.Sprintf("Contains your %s '%s'", f.Field, f.Value)
+	}
+}
+
+// IsEmpty reports whether no context was given
+func (uc UserContext) IsEmpty() bool {
+	return uc.Username == "" && uc.Email == "" && uc.Name == "" && uc.Organization == ""
+}
+
+// Tokens returns the words derived from the context, keyed by field: the
+// username and its parts, the email's local part, parts and domain label,
+// the full name with and without spaces and each name part, and the
+// organization and its parts
+func (uc UserContext) Tokens() map[string][]string {
+	tokens := make(map[string][]string)
+	add := func(field, value string) {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len([]rune(value)) >= minBannedWordLength {
+			tokens[field] = append(tokens[field], value)
+		}
+	}
+	addWithParts := func(field, value string) {
+		add(field, value)
+		parts := splitContextValue(value)
+		if len(parts) > 1 {
+			add(field, strings.Join(parts, ""))
+			for _, part := range parts {
+				add(field, part)
+			}
+		}
+	}
+
+	addWithParts("username", uc.Username)
+
+	if uc.Email != "" {
+		local, domain, _ := strings.Cut(uc.Email, "@")
+		addWithParts("email", local)
+		if label, _, ok := strings.Cut(domain, "."); ok {
+			add("email", label)
+		}
+	}
+
+	addWithParts("name", uc.Name)
+	addWithParts("organization", uc.Organization)
+
+	for field, values := range tokens {
+		tokens[field] = NewBannedWordList(field, values).Words
+	}
+	return tokens
+}
+
+// Initials returns the first letter of each name part, when there are at
+// least two parts
+func (uc UserContext) Initials() string {
+	parts := splitContextValue(uc.Name)
+	if len(parts) < 2 {
+		return ""
+	}
+	var initials []rune
+	for _, part := range parts {
+		initials = append(initials, []rune(part)[0])
+	}
+	return string(initials)
+}
+
+// Match returns the ways password resembles the context: containing a value
+// verbatim, in l33t spelling, misspelled or reversed, or the name's initials
+// followed by a year. Each value is reported once.
+func (uc UserContext) Match(password string) []UserContextFinding {
+	if uc.IsEmpty() || password == "" {
+		return nil
+	}
+
+	// Fields in order of how personal they are; a value shared by several
+	// fields is reported once, under the most personal
+	tokens := uc.Tokens()
+	var lists []BannedWordList
+	for _, field := range userContextFields {
+		lists = append(lists, BannedWordList{Name: field, Words: tokens[field]})
+	}
+	matcher := NewBannedWordMatcher(userContextDistance, lists...)
+
+	var findings []UserContextFinding
+	seen := make(map[string]bool)
+	record := func(match BannedWordMatch, kind string) {
+		if seen[match.Word] {
+			return
+		}
+		seen[match.Word] = true
+		findings = append(findings, UserContextFinding{
+			Field:    match.List,
+			Value:    match.Word,
+			Token:    match.Token,
+			Kind:     kind,
+			Severity: contextSeverity(match.List, kind),
+		})
+	}
+
+	for _, match := range matcher.Match(password) {
+		switch {
+		case match.Distance > 0:
+			record(match, "similar")
+		case match.Leet:
+			record(match, "leet")
+		default:
+			record(match, "contains")
+		}
+	}
+
+	for _, match := range matcher.Match(reverseRunes(password)) {
+		if match.Distance == 0 {
+			match.Token = reverseRunes(match.Token)
+			record(match, "reversed")
+		}
+	}
+
+	if initials := uc.Initials(); initials != "" {
+		pattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(initials) + `[-_.]?((19|20)\d{2}|\d{2})`)
+		if token := pattern.FindString(password); token != "" {
+			findings = append(findings, UserContextFinding{
+				Field:    "name",
+				Value:    initials,
+				Token:    token,
+				Kind:     "initials",
+				Severity: contextSeverity("name", "initials"),
+			})
+		}
+	}
+
+	return findings
+}
+
+// contextSeverity rates a finding: personal identifiers used verbatim are
+// the most guessable, the organization and approximate matches less so
+func contextSeverity(field, kind string) string {
+	personal := field != "organization"
+	switch {
+	case kind == "initials":
+		return "medium"
+	case kind == "similar" && personal:
+		return "medium"
+	case kind == "similar":
+		return "low"
+	case personal:
+		return "high"
+	default:
+		return "medium"
+	}
+}
+
+// splitContextValue splits a name, username or email local part into its
+// alphanumeric parts ("alice.smith" and "Alice Smith" both give alice, smith)
+func splitContextValue(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// reverseRunes reverses a string by runes
+func reverseRunes(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
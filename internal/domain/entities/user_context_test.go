@@ -0,0 +1,57 @@
+package entities
+
+import "testing"
+
+func TestUserContext_Match(t *testing.T) {
+	context := UserContext{
+		Username:     "asmith",
+		Email:        "alice.smith@example.com",
+		Name:         "Alice Smith",
+		Organization: "Globex",
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		field    string
+		kind     string
+		severity string
+	}{
+		{"username", "asmith2024!", "username", "contains", "high"},
+		{"email local part", "x-alice.smith-x", "email", "contains", "high"},
+		{"name part leet", "$m1th-rules", "email", "leet", "high"},
+		{"reversed", "ecila#Q9zz", "email", "reversed", "high"},
+		{"misspelled organization", "Glbex-Qz9!", "organization", "similar", "low"},
+		{"organization", "GLOBEX#hq9", "organization", "contains", "medium"},
+		{"initials and year", "as1987!Qz", "name", "initials", "medium"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := context.Match(tt.password)
+			for _, finding := range findings {
+				if finding.Field == tt.field && finding.Kind == tt.kind && finding.Severity == tt.severity {
+					return
+				}
+			}
+			t.Errorf("Match(%q) = %+v, want a %s finding on %s with severity %s",
+				tt.password, findings, tt.kind, tt.field, tt.severity)
+		})
+	}
+
+	if findings := context.Match("kD8#mQ2$vL9!xR4@"); len(findings) != 0 {
+		t.Errorf("Unrelated password produced findings: %+v", findings)
+	}
+	if findings := (UserContext{}).Match("alice"); findings != nil {
+		t.Errorf("Empty context produced findings: %+v", findings)
+	}
+}
+
+func TestUserContext_SharedValueReportedOnce(t *testing.T) {
+	context := UserContext{Username: "alice", Email: "alice@example.com", Name: "Alice"}
+
+	findings := context.Match("alice2024")
+	if len(findings) != 1 || findings[0].Field != "username" {
+		t.Errorf("Match() = %+v, want a single username finding", findings)
+	}
+}
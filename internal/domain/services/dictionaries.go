@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bufio"
+	"embed"
+	"strings"
+	"sync"
+)
+
+// Frequency-ranked word lists used by the guess estimator. The lists are
+// derived from the zxcvbn project's data (MIT licensed, see
+// THIRD_PARTY_NOTICES) and are embedded so estimation works offline. Each file holds one lowercase word per line,
+// most common first; a word's rank is its line number.
+//
+//go:embed data/*.txt
+var dictionaryFiles embed.FS
+
+// RankedDictionary maps a lowercase word to its frequency rank (1 = most common)
+type RankedDictionary map[string]int
+
+var (
+	defaultDictionaries     map[string]RankedDictionary
+	defaultDictionariesOnce sync.Once
+)
+
+// dictionaryNames lists the embedded dictionaries in the order they are matched
+var dictionaryNames = []string{"passwords", "english", "female_names", "male_names", "surnames"}
+
+// loadDefaultDictionaries parses the embedded word lists once per process
+func loadDefaultDictionaries() map[string]RankedDictionary {
+	defaultDictionariesOnce.Do(func() {
+		defaultDictionaries = make(map[string]RankedDictionary, len(dictionaryNames))
+		for _, name := range dictionaryNames {
+			data, err := dictionaryFiles.ReadFile("data/" + name + ".txt")
+			if err != nil {
+				continue
+			}
+			defaultDictionaries[name] = parseRankedDictionary(string(data))
+		}
+	})
+	return defaultDictionaries
+}
+
+// parseRankedDictionary turns a newline-separated word list into a RankedDictionary
+func parseRankedDictionary(data string) RankedDictionary {
+	dict := make(RankedDictionary)
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	rank := 0
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" {
+			continue
+		}
+		rank++
+		if _, exists := dict[word]; !exists {
+			dict[word] = rank
+		}
+	}
+	return dict
+}
+
+// NewRankedDictionary builds a dictionary from words in priority order
+func NewRankedDictionary(words []string) RankedDictionary {
+	dict := make(RankedDictionary, len(words))
+	rank := 0
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		rank++
+		if _, exists := dict[word]; !exists {
+			dict[word] = rank
+		}
+	}
+	return dict
+}
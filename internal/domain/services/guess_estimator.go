@@ -0,0 +1,464 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// Match patterns produced by the guess estimator
+const (
+	PatternDictionary = "dictionary"
+	PatternSpatial    = "spatial"
+	PatternRepeat     = "repeat"
+	PatternSequence   = "sequence"
+	PatternRegex      = "regex"
+	PatternDate       = "date"
+	PatternBruteforce = "bruteforce"
+)
+
+// Regex match names
+const (
+	RegexRecentYear  = "recent_year"
+	RegexPhoneNumber = "phone_number"
+	RegexPostcode    = "postcode"
+)
+
+// Tuning constants for the guess model (values follow zxcvbn)
+const (
+	bruteforceCardinality           = 10
+	minGuessesBeforeGrowingSequence = 10000
+	minSubmatchGuessesSingleChar    = 10
+	minSubmatchGuessesMultiChar     = 50
+	minYearSpace                    = 20
+	maxEstimatedLength              = 100
+)
+
+// GuessMatch is one segment of a password decomposition. Only the fields
+// relevant to the match's Pattern are populated.
+type GuessMatch struct {
+	Pattern string
+	Start   int // rune index of the first character
+	End     int // rune index of the last character (inclusive)
+	Token   string
+
+	// dictionary
+	Dictionary    string
+	MatchedWord   string
+	Rank          int
+	Reversed      bool
+	L33t          bool
+	Substitutions map[rune]rune // substituted character -> original letter
+
+	// spatial
+	Graph        string
+	Turns        int
+	ShiftedCount int
+
+	// repeat
+	BaseToken   string
+	BaseGuesses float64
+	RepeatCount int
+
+	// sequence
+	SequenceName  string
+	SequenceSpace int
+	Ascending     bool
+
+	// regex
+	RegexName  string
+	RegexSpace float64 // candidates of the matched shape, for phone numbers and postcodes
+
+	// date
+	Year      int
+	Month     int
+	Day       int
+	Separator string
+
+	Guesses      float64
+	GuessesLog10 float64
+}
+
+// GuessEstimate is the result of estimating how many guesses an attacker
+// needs to find a password
+type GuessEstimate struct {
+	Password     string
+	Guesses      float64
+	GuessesLog10 float64
+	Score        int // 0 (too guessable) to 4 (very unguessable)
+	Sequence     []GuessMatch
+}
+
+// GuessEstimator estimates guess numbers by decomposing a password into the
+// cheapest sequence of recognisable patterns (zxcvbn's approach). Unlike the
+// checklist in PasswordStrengthChecker it reflects how human-chosen passwords
+// are actually cracked.
+type GuessEstimator struct {
+	dictionaries  map[string]RankedDictionary
+	graphs        []*entities.KeyboardGraph
+	referenceYear int
+}
+
+// NewGuessEstimator creates a GuessEstimator backed by the embedded dictionaries
+func NewGuessEstimator() *GuessEstimator {
+	return &GuessEstimator{
+		dictionaries:  loadDefaultDictionaries(),
+		graphs:        entities.KeyboardGraphs(),
+		referenceYear: time.Now().Year(),
+	}
+}
+
+// Estimate returns the guess estimate for password. userInputs are extra
+// words (user name, site name, ...) that an attacker would try first.
+func (ge *GuessEstimator) Estimate(password string, userInputs ...string) GuessEstimate {
+	runes := []rune(password)
+	analysed := password
+	if len(runes) > maxEstimatedLength {
+		analysed = string(runes[:maxEstimatedLength])
+	}
+
+	dictionaries := ge.dictionaries
+	if len(userInputs) > 0 {
+		dictionaries = make(map[string]RankedDictionary, len(ge.dictionaries)+1)
+		for name, dict := range ge.dictionaries {
+			dictionaries[name] = dict
+		}
+		dictionaries["user_inputs"] = NewRankedDictionary(userInputs)
+	}
+
+	matches := ge.omnimatch(analysed, dictionaries)
+	estimate := ge.mostGuessableMatchSequence(analysed, matches)
+
+	// Characters beyond the analysed prefix are counted as brute force
+	if extra := len(runes) - len([]rune(analysed)); extra > 0 {
+		estimate.Guesses *= math.Pow(bruteforceCardinality, float64(extra))
+		if math.IsInf(estimate.Guesses, 1) {
+			estimate.Guesses = math.MaxFloat64
+		}
+	}
+
+	estimate.Password = password
+	estimate.GuessesLog10 = math.Log10(estimate.Guesses)
+	estimate.Score = guessesToScore(estimate.Guesses)
+	return estimate
+}
+
+// guessesToScore buckets a guess number into zxcvbn's 0-4 score
+func guessesToScore(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// mostGuessableMatchSequence finds the decomposition of password into
+// non-overlapping matches (gaps filled by brute force) that minimises the
+// total guesses, using dynamic programming over prefix length and the number
+// of matches in the sequence.
+func (ge *GuessEstimator) mostGuessableMatchSequence(password string, matches []GuessMatch) GuessEstimate {
+	runes := []rune(password)
+	n := len(runes)
+	if n == 0 {
+		return GuessEstimate{Guesses: 1}
+	}
+
+	matchesByEnd := make([][]GuessMatch, n)
+	for i := range matches {
+		ge.estimateGuesses(&matches[i], n)
+	}
+	for _, m := range matches {
+		matchesByEnd[m.End] = append(matchesByEnd[m.End], m)
+	}
+	for _, list := range matchesByEnd {
+		sort.Slice(list, func(a, b int) bool { return list[a].Start < list[b].Start })
+	}
+
+	// best[k][l] is the optimal sequence of l matches covering runes[0..k]
+	type step struct {
+		match GuessMatch
+		pi    float64 // product of match guesses
+		g     float64 // overall guesses including the sequence-length penalty
+	}
+	best := make([]map[int]step, n)
+	for k := range best {
+		best[k] = make(map[int]step)
+	}
+
+	update := func(m GuessMatch, l int) {
+		k := m.End
+		pi := ge.estimateGuesses(&m, n)
+		if l > 1 {
+			pi *= best[m.Start-1][l-1].pi
+		}
+		g := factorial(l) * pi
+		g += math.Pow(minGuessesBeforeGrowingSequence, float64(l-1))
+
+		for competingL, competing := range best[k] {
+			if competingL > l {
+				continue
+			}
+			if competing.g <= g {
+				return
+			}
+		}
+		best[k][l] = step{match: m, pi: pi, g: g}
+	}
+
+	bruteforceUpdate := func(k int) {
+		update(makeBruteforceMatch(runes, 0, k), 1)
+		for i := 1; i <= k; i++ {
+			m := makeBruteforceMatch(runes, i, k)
+			for l, last := range best[i-1] {
+				if last.match.Pattern == PatternBruteforce {
+					continue
+				}
+				update(m, l+1)
+			}
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, m := range matchesByEnd[k] {
+			if m.Start > 0 {
+				for l := range best[m.Start-1] {
+					update(m, l+1)
+				}
+			} else {
+				update(m, 1)
+			}
+		}
+		bruteforceUpdate(k)
+	}
+
+	// Unwind the optimal sequence from the end of the password
+	optimalL := 0
+	guesses := math.Inf(1)
+	for l, s := range best[n-1] {
+		if s.g < guesses || (s.g == guesses && l < optimalL) {
+			optimalL = l
+			guesses = s.g
+		}
+	}
+
+	var sequence []GuessMatch
+	for k, l := n-1, optimalL; k >= 0 && l > 0; l-- {
+		m := best[k][l].match
+		sequence = append([]GuessMatch{m}, sequence...)
+		k = m.Start - 1
+	}
+
+	return GuessEstimate{Guesses: guesses, Sequence: sequence}
+}
+
+// makeBruteforceMatch covers runes[i..j] with a brute-force segment
+func makeBruteforceMatch(runes []rune, i, j int) GuessMatch {
+	return GuessMatch{Pattern: PatternBruteforce, Start: i, End: j, Token: string(runes[i : j+1])}
+}
+
+// estimateGuesses computes and caches the guesses needed for a single match
+func (ge *GuessEstimator) estimateGuesses(m *GuessMatch, passwordLength int) float64 {
+	if m.Guesses > 0 {
+		return m.Guesses
+	}
+
+	tokenLength := len([]rune(m.Token))
+	minGuesses := 1.0
+	if tokenLength < passwordLength {
+		if tokenLength == 1 {
+			minGuesses = minSubmatchGuessesSingleChar
+		} else {
+			minGuesses = minSubmatchGuessesMultiChar
+		}
+	}
+
+	var guesses float64
+	switch m.Pattern {
+	case PatternBruteforce:
+		guesses = bruteforceGuesses(tokenLength)
+	case PatternDictionary:
+		guesses = dictionaryGuesses(*m)
+	case PatternSpatial:
+		guesses = ge.spatialGuesses(*m)
+	case PatternRepeat:
+		guesses = m.BaseGuesses * float64(m.RepeatCount)
+	case PatternSequence:
+		guesses = sequenceGuesses(*m)
+	case PatternRegex:
+		guesses = m.RegexSpace
+		if m.RegexName == RegexRecentYear {
+			guesses = math.Max(math.Abs(float64(m.Year-ge.referenceYear)), minYearSpace)
+		}
+	case PatternDate:
+		guesses = ge.dateGuesses(*m)
+	}
+
+	m.Guesses = math.Max(guesses, minGuesses)
+	m.GuessesLog10 = math.Log10(m.Guesses)
+	return m.Guesses
+}
+
+// bruteforceGuesses assumes every position could be any of a small alphabet
+func bruteforceGuesses(length int) float64 {
+	guesses := math.Pow(bruteforceCardinality, float64(length))
+	if math.IsInf(guesses, 1) {
+		guesses = math.MaxFloat64
+	}
+	minGuesses := float64(minSubmatchGuessesMultiChar + 1)
+	if length == 1 {
+		minGuesses = minSubmatchGuessesSingleChar + 1
+	}
+	return math.Max(guesses, minGuesses)
+}
+
+// dictionaryGuesses is the word's rank scaled by its capitalisation,
+// substitution and reversal variants
+func dictionaryGuesses(m GuessMatch) float64 {
+	guesses := float64(m.Rank) * uppercaseVariations(m.Token) * l33tVariations(m)
+	if m.Reversed {
+		guesses *= 2
+	}
+	return guesses
+}
+
+// uppercaseVariations counts capitalisation variants an attacker would try
+func uppercaseVariations(token string) float64 {
+	upper, lower := 0, 0
+	runes := []rune(token)
+	for _, r := range runes {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			upper++
+		case r >= 'a' && r <= 'z':
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+
+	// First letter, last letter or all capitalised are tried early
+	startUpper := isASCIIUpper(runes[0]) && upper == 1
+	endUpper := isASCIIUpper(runes[len(runes)-1]) && upper == 1
+	if startUpper || endUpper || lower == 0 {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= minInt(upper, lower); i++ {
+		variations += entities.Binomial(upper+lower, i)
+	}
+	return variations
+}
+
+// l33tVariations counts the substitution variants an attacker would try
+func l33tVariations(m GuessMatch) float64 {
+	if !m.L33t {
+		return 1
+	}
+
+	variations := 1.0
+	lowered := []rune(toLowerASCII(m.Token))
+	for subbed, unsubbed := range m.Substitutions {
+		s, u := 0, 0
+		for _, r := range lowered {
+			if r == subbed {
+				s++
+			}
+			if r == unsubbed {
+				u++
+			}
+		}
+		if s == 0 || u == 0 {
+			variations *= 2
+			continue
+		}
+		possibilities := 0.0
+		for i := 1; i <= minInt(s, u); i++ {
+			possibilities += entities.Binomial(u+s, i)
+		}
+		variations *= possibilities
+	}
+	return variations
+}
+
+// spatialGuesses counts walks like the match's on its keyboard
+func (ge *GuessEstimator) spatialGuesses(m GuessMatch) float64 {
+	for _, graph := range ge.graphs {
+		if graph.Name == m.Graph {
+			return graph.Guesses(entities.KeyboardWalk{
+				Graph: m.Graph, Start: m.Start, End: m.End, Token: m.Token, Turns: m.Turns, ShiftedCount: m.ShiftedCount,
+			})
+		}
+	}
+	return bruteforceGuesses(len([]rune(m.Token)))
+}
+
+// sequenceGuesses favours sequences that start at an obvious character
+func sequenceGuesses(m GuessMatch) float64 {
+	first := []rune(m.Token)[0]
+	var base float64
+	switch {
+	case first == 'a' || first == 'A' || first == 'z' || first == 'Z' || first == '0' || first == '1' || first == '9':
+		base = 4
+	case first >= '0' && first <= '9':
+		base = 10
+	default:
+		base = 26
+	}
+	if !m.Ascending {
+		base *= 2
+	}
+	return base * float64(len([]rune(m.Token)))
+}
+
+// dateGuesses counts days in the plausible year range around the match
+func (ge *GuessEstimator) dateGuesses(m GuessMatch) float64 {
+	yearSpace := math.Max(math.Abs(float64(m.Year-ge.referenceYear)), minYearSpace)
+	guesses := yearSpace * 365
+	if m.Separator != "" {
+		guesses *= 4
+	}
+	return guesses
+}
+
+// factorial returns n! as a float
+func factorial(n int) float64 {
+	result := 1.0
+	for i := 2; i <= n; i++ {
+		result *= float64(i)
+	}
+	return result
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func isASCIIUpper(r rune) bool {
+	return r >= 'A' && r <= 'Z'
+}
+
+// toLowerASCII lowercases ASCII letters only, leaving rune positions intact
+func toLowerASCII(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if isASCIIUpper(r) {
+			runes[i] = r + ('a' - 'A')
+		}
+	}
+	return string(runes)
+}
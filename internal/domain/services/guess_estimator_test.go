@@ -0,0 +1,111 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestGuessEstimator_Estimate(t *testing.T) {
+	estimator := NewGuessEstimator()
+
+	tests := []struct {
+		name        string
+		password    string
+		maxLog10    float64
+		wantPattern string
+	}{
+		{name: "top password", password: "password", maxLog10: 1, wantPattern: PatternDictionary},
+		{name: "reversed word", password: "drowssap", maxLog10: 1, wantPattern: PatternDictionary},
+		{name: "l33t word", password: "P@ssw0rd", maxLog10: 3, wantPattern: PatternDictionary},
+		{name: "keyboard walk", password: "zaq1@WSX", maxLog10: 9, wantPattern: PatternSpatial},
+		{name: "repeated block", password: "abcabcabc", maxLog10: 3, wantPattern: PatternRepeat},
+		{name: "sequence", password: "hijklmn", maxLog10: 3, wantPattern: PatternSequence},
+		{name: "date", password: "19/08/1987", maxLog10: 6, wantPattern: PatternDate},
+		{name: "phone number", password: "555-867-5309", maxLog10: 10, wantPattern: PatternRegex},
+		{name: "postcode", password: "SW1A1AA", maxLog10: 6.5, wantPattern: PatternRegex},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := estimator.Estimate(tt.password)
+
+			if estimate.GuessesLog10 > tt.maxLog10 {
+				t.Errorf("Estimate(%q) log10 = %.2f, want <= %.2f", tt.password, estimate.GuessesLog10, tt.maxLog10)
+			}
+			if len(estimate.Sequence) == 0 {
+				t.Fatalf("Estimate(%q) returned an empty decomposition", tt.password)
+			}
+			if got := estimate.Sequence[0].Pattern; got != tt.wantPattern {
+				t.Errorf("Estimate(%q) first pattern = %v, want %v", tt.password, got, tt.wantPattern)
+			}
+		})
+	}
+}
+
+func TestGuessEstimator_RejectsImpossibleDates(t *testing.T) {
+	estimator := NewGuessEstimator()
+
+	// Shorter valid dates inside them, such as 1/04/1990, may still match
+	for _, password := range []string{"31/04/1990", "29/02/1991"} {
+		for _, match := range estimator.Estimate(password).Sequence {
+			if match.Pattern == PatternDate && match.Token == password {
+				t.Errorf("Estimate(%q) matched date %d-%d-%d", password, match.Year, match.Month, match.Day)
+			}
+		}
+	}
+}
+
+func TestGuessEstimator_RandomPasswordIsStrong(t *testing.T) {
+	estimator := NewGuessEstimator()
+
+	estimate := estimator.Estimate("kD8#mQ2$vL9!xR4@")
+	if estimate.Score != 4 {
+		t.Errorf("Score = %v, want 4", estimate.Score)
+	}
+	if estimate.GuessesLog10 < 14 {
+		t.Errorf("GuessesLog10 = %.2f, want >= 14", estimate.GuessesLog10)
+	}
+}
+
+func TestGuessEstimator_DecompositionCoversPassword(t *testing.T) {
+	estimator := NewGuessEstimator()
+
+	password := "Summer2024!qwerty"
+	estimate := estimator.Estimate(password)
+
+	next := 0
+	for _, match := range estimate.Sequence {
+		if match.Start != next {
+			t.Fatalf("Match %q starts at %d, want %d", match.Token, match.Start, next)
+		}
+		next = match.End + 1
+	}
+	if next != len([]rune(password)) {
+		t.Errorf("Decomposition covers %d characters, want %d", next, len([]rune(password)))
+	}
+}
+
+func TestGuessEstimator_UserInputs(t *testing.T) {
+	estimator := NewGuessEstimator()
+
+	without := estimator.Estimate("kumarasakti")
+	with := estimator.Estimate("kumarasakti", "kumarasakti")
+
+	if with.Guesses >= without.Guesses {
+		t.Errorf("User input should lower guesses: with = %.3g, without = %.3g", with.Guesses, without.Guesses)
+	}
+	if with.Sequence[0].Dictionary != "user_inputs" {
+		t.Errorf("Dictionary = %v, want user_inputs", with.Sequence[0].Dictionary)
+	}
+}
+
+func TestGuessEstimator_EmptyPassword(t *testing.T) {
+	estimator := NewGuessEstimator()
+
+	estimate := estimator.Estimate("")
+	if estimate.Guesses != 1 {
+		t.Errorf("Guesses = %v, want 1", estimate.Guesses)
+	}
+	if estimate.Score != 0 {
+		t.Errorf("Score = %v, want 0", estimate.Score)
+	}
+}
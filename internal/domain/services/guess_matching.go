@@ -0,0 +1,602 @@
+package services
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// l33tTable lists the substitutions attackers try for each letter
+var l33tTable = map[rune][]rune{
+	'a': {'4', '@'},
+	'b': {'8', '6'},
+	'c': {'(', '{', '[', '<'},
+	'e': {'3'},
+	'g': {'6', '9'},
+	'i': {'1', '!', '|'},
+	'l': {'1', '|', '7'},
+	'o': {'0'},
+	's': {'$', '5'},
+	't': {'+', '7'},
+	'x': {'%'},
+	'z': {'2'},
+}
+
+// Limits for sequence and date matching
+const (
+	maxSequenceDelta = 5
+	dateMinYear      = 1000
+	dateMaxYear      = 2050
+)
+
+// dateSplits lists where to cut a separator-free digit run into day, month and year
+var dateSplits = map[int][][2]int{
+	4: {{1, 2}, {2, 3}},
+	5: {{1, 3}, {2, 3}},
+	6: {{1, 2}, {2, 4}, {4, 5}},
+	7: {{1, 3}, {2, 3}, {4, 5}, {4, 6}},
+	8: {{2, 4}, {4, 6}},
+}
+
+// omnimatch runs every matcher over password and returns all candidate matches
+func (ge *GuessEstimator) omnimatch(password string, dictionaries map[string]RankedDictionary) []GuessMatch {
+	var matches []GuessMatch
+	matches = append(matches, dictionaryMatch(password, dictionaries)...)
+	matches = append(matches, reverseDictionaryMatch(password, dictionaries)...)
+	matches = append(matches, l33tMatch(password, dictionaries)...)
+	matches = append(matches, ge.spatialMatch(password)...)
+	matches = append(matches, ge.repeatMatch(password, dictionaries)...)
+	matches = append(matches, sequenceMatch(password)...)
+	matches = append(matches, ge.regexMatch(password)...)
+	matches = append(matches, ge.dateMatch(password)...)
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		if matches[a].Start != matches[b].Start {
+			return matches[a].Start < matches[b].Start
+		}
+		return matches[a].End < matches[b].End
+	})
+	return matches
+}
+
+// dictionaryMatch finds every substring that is a dictionary word
+func dictionaryMatch(password string, dictionaries map[string]RankedDictionary) []GuessMatch {
+	var matches []GuessMatch
+	runes := []rune(password)
+	lower := []rune(toLowerASCII(password))
+
+	for _, name := range sortedDictionaryNames(dictionaries) {
+		dict := dictionaries[name]
+		for i := range lower {
+			for j := i; j < len(lower); j++ {
+				word := string(lower[i : j+1])
+				rank, ok := dict[word]
+				if !ok {
+					continue
+				}
+				matches = append(matches, GuessMatch{
+					Pattern:     PatternDictionary,
+					Start:       i,
+					End:         j,
+					Token:       string(runes[i : j+1]),
+					Dictionary:  name,
+					MatchedWord: word,
+					Rank:        rank,
+				})
+			}
+		}
+	}
+	return matches
+}
+
+// reverseDictionaryMatch finds dictionary words typed backwards
+func reverseDictionaryMatch(password string, dictionaries map[string]RankedDictionary) []GuessMatch {
+	runes := []rune(password)
+	n := len(runes)
+
+	var matches []GuessMatch
+	for _, m := range dictionaryMatch(reverseString(password), dictionaries) {
+		m.Reversed = true
+		m.Start, m.End = n-1-m.End, n-1-m.Start
+		m.Token = string(runes[m.Start : m.End+1])
+		if m.Token == reverseString(m.Token) {
+			continue // palindromes are already found by the forward pass
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// l33tMatch finds dictionary words disguised with character substitutions
+func l33tMatch(password string, dictionaries map[string]RankedDictionary) []GuessMatch {
+	runes := []rune(password)
+	var matches []GuessMatch
+	seen := make(map[string]bool)
+
+	for _, sub := range enumerateL33tSubs(relevantL33tTable(password)) {
+		if len(sub) == 0 {
+			continue
+		}
+		translated := make([]rune, len(runes))
+		for i, r := range runes {
+			if letter, ok := sub[r]; ok {
+				translated[i] = letter
+			} else {
+				translated[i] = r
+			}
+		}
+
+		for _, m := range dictionaryMatch(string(translated), dictionaries) {
+			token := runes[m.Start : m.End+1]
+			if len(token) <= 1 || strings.EqualFold(string(token), m.MatchedWord) {
+				continue
+			}
+
+			// Keep only the substitutions that actually occur in this token
+			used := make(map[rune]rune)
+			for _, r := range token {
+				if letter, ok := sub[r]; ok {
+					used[r] = letter
+				}
+			}
+
+			key := m.Dictionary + "|" + m.MatchedWord + "|" + strconv.Itoa(m.Start) + "|" + strconv.Itoa(m.End)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			m.Token = string(token)
+			m.L33t = true
+			m.Substitutions = used
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// relevantL33tTable restricts l33tTable to substitutions present in password
+func relevantL33tTable(password string) map[rune][]rune {
+	present := make(map[rune]bool)
+	for _, r := range password {
+		present[r] = true
+	}
+
+	relevant := make(map[rune][]rune)
+	for letter, subs := range l33tTable {
+		for _, sub := range subs {
+			if present[sub] {
+				relevant[letter] = append(relevant[letter], sub)
+			}
+		}
+	}
+	return relevant
+}
+
+// enumerateL33tSubs returns every consistent assignment of substituted
+// characters to letters. A character such as '1' may stand for 'i' or 'l',
+// so each ambiguity doubles the number of assignments.
+func enumerateL33tSubs(table map[rune][]rune) []map[rune]rune {
+	candidates := make(map[rune][]rune) // substituted char -> possible letters
+	for letter, subs := range table {
+		for _, sub := range subs {
+			candidates[sub] = append(candidates[sub], letter)
+		}
+	}
+
+	chars := make([]rune, 0, len(candidates))
+	for char := range candidates {
+		chars = append(chars, char)
+		sort.Slice(candidates[char], func(a, b int) bool { return candidates[char][a] < candidates[char][b] })
+	}
+	sort.Slice(chars, func(a, b int) bool { return chars[a] < chars[b] })
+
+	subs := []map[rune]rune{{}}
+	for _, char := range chars {
+		var next []map[rune]rune
+		for _, sub := range subs {
+			for _, letter := range candidates[char] {
+				extended := make(map[rune]rune, len(sub)+1)
+				for k, v := range sub {
+					extended[k] = v
+				}
+				extended[char] = letter
+				next = append(next, extended)
+			}
+		}
+		subs = next
+	}
+	return subs
+}
+
+// spatialMatch finds keyboard walks on every known layout
+func (ge *GuessEstimator) spatialMatch(password string) []GuessMatch {
+	var matches []GuessMatch
+	for _, graph := range ge.graphs {
+		for _, walk := range graph.FindWalks(password) {
+			matches = append(matches, GuessMatch{
+				Pattern:      PatternSpatial,
+				Start:        walk.Start,
+				End:          walk.End,
+				Token:        walk.Token,
+				Graph:        walk.Graph,
+				Turns:        walk.Turns,
+				ShiftedCount: walk.ShiftedCount,
+			})
+		}
+	}
+	return matches
+}
+
+// repeatMatch finds a block repeated back to back (aaa, abcabc). At each
+// position the longest repetition wins, using the shortest base block.
+func (ge *GuessEstimator) repeatMatch(password string, dictionaries map[string]RankedDictionary) []GuessMatch {
+	runes := []rune(password)
+	n := len(runes)
+	var matches []GuessMatch
+
+	for i := 0; i < n-1; {
+		bestBase, bestCount := 0, 0
+		for base := 1; base <= (n-i)/2; base++ {
+			count := 1
+			for i+(count+1)*base <= n && equalRunes(runes[i:i+base], runes[i+count*base:i+(count+1)*base]) {
+				count++
+			}
+			if count >= 2 && base*count > bestBase*bestCount {
+				bestBase, bestCount = base, count
+			}
+		}
+
+		if bestCount < 2 {
+			i++
+			continue
+		}
+
+		base := string(runes[i : i+bestBase])
+		baseEstimate := ge.mostGuessableMatchSequence(base, ge.omnimatch(base, dictionaries))
+		end := i + bestBase*bestCount - 1
+		matches = append(matches, GuessMatch{
+			Pattern:     PatternRepeat,
+			Start:       i,
+			End:         end,
+			Token:       string(runes[i : end+1]),
+			BaseToken:   base,
+			BaseGuesses: baseEstimate.Guesses,
+			RepeatCount: bestCount,
+		})
+		i = end + 1
+	}
+	return matches
+}
+
+// sequenceMatch finds runs with a constant step between characters (abc, 7531)
+func sequenceMatch(password string) []GuessMatch {
+	runes := []rune(password)
+	if len(runes) <= 1 {
+		return nil
+	}
+
+	var matches []GuessMatch
+	emit := func(i, j, delta int) {
+		if j-i > 1 || abs(delta) == 1 {
+			if delta == 0 || abs(delta) > maxSequenceDelta {
+				return
+			}
+			token := string(runes[i : j+1])
+			name, space := sequenceSpace(token)
+			matches = append(matches, GuessMatch{
+				Pattern:       PatternSequence,
+				Start:         i,
+				End:           j,
+				Token:         token,
+				SequenceName:  name,
+				SequenceSpace: space,
+				Ascending:     delta > 0,
+			})
+		}
+	}
+
+	i := 0
+	lastDelta := 0
+	for k := 1; k < len(runes); k++ {
+		delta := int(runes[k]) - int(runes[k-1])
+		if k == 1 {
+			lastDelta = delta
+		}
+		if delta == lastDelta {
+			continue
+		}
+		j := k - 1
+		emit(i, j, lastDelta)
+		i = j
+		lastDelta = delta
+	}
+	emit(i, len(runes)-1, lastDelta)
+	return matches
+}
+
+// sequenceSpace names the alphabet a sequence token is drawn from
+func sequenceSpace(token string) (string, int) {
+	allIn := func(lo, hi rune) bool {
+		for _, r := range token {
+			if r < lo || r > hi {
+				return false
+			}
+		}
+		return true
+	}
+	switch {
+	case allIn('a', 'z'):
+		return "lower", 26
+	case allIn('A', 'Z'):
+		return "upper", 26
+	case allIn('0', '9'):
+		return "digits", 10
+	default:
+		return "unicode", 26
+	}
+}
+
+// regexMatch finds recent years, which people often append to passwords,
+// and phone numbers and postcodes
+func (ge *GuessEstimator) regexMatch(password string) []GuessMatch {
+	runes := []rune(password)
+	var matches []GuessMatch
+	for i := 0; i+4 <= len(runes); i++ {
+		token := string(runes[i : i+4])
+		if !isDigits(token) || !(strings.HasPrefix(token, "19") || strings.HasPrefix(token, "20")) {
+			continue
+		}
+		year, _ := strconv.Atoi(token)
+		matches = append(matches, GuessMatch{
+			Pattern:   PatternRegex,
+			Start:     i,
+			End:       i + 3,
+			Token:     token,
+			RegexName: RegexRecentYear,
+			Year:      year,
+		})
+	}
+
+	regexNames := map[string]string{
+		entities.PersonalDataPhone:    RegexPhoneNumber,
+		entities.PersonalDataPostcode: RegexPostcode,
+	}
+	for _, found := range entities.FindPersonalData(password, ge.referenceYear) {
+		if name, ok := regexNames[found.Kind]; ok {
+			matches = append(matches, GuessMatch{
+				Pattern:    PatternRegex,
+				Start:      found.Start,
+				End:        found.End,
+				Token:      found.Token,
+				RegexName:  name,
+				RegexSpace: found.Guesses,
+			})
+		}
+	}
+	return matches
+}
+
+// dateMatch finds calendar dates with or without separators
+func (ge *GuessEstimator) dateMatch(password string) []GuessMatch {
+	runes := []rune(password)
+	n := len(runes)
+	var matches []GuessMatch
+
+	// Dates without separators: 4 to 8 digits
+	for i := 0; i+4 <= n; i++ {
+		for j := i + 3; j <= i+7 && j < n; j++ {
+			token := string(runes[i : j+1])
+			if !isDigits(token) {
+				break
+			}
+
+			var best *GuessMatch
+			bestDistance := 0
+			for _, split := range dateSplits[len(token)] {
+				ints := [3]int{atoi(token[:split[0]]), atoi(token[split[0]:split[1]]), atoi(token[split[1]:])}
+				year, month, day, ok := mapIntsToDMY(ints)
+				if !ok {
+					continue
+				}
+				distance := abs(year - ge.referenceYear)
+				if best == nil || distance < bestDistance {
+					best = &GuessMatch{Pattern: PatternDate, Start: i, End: j, Token: token, Year: year, Month: month, Day: day}
+					bestDistance = distance
+				}
+			}
+			if best != nil {
+				matches = append(matches, *best)
+			}
+		}
+	}
+
+	// Dates with a repeated separator: 1-4 digits, sep, 1-2 digits, sep, 1-4 digits
+	for i := 0; i+6 <= n; i++ {
+		for j := i + 5; j <= i+9 && j < n; j++ {
+			token := string(runes[i : j+1])
+			ints, separator, ok := splitSeparatedDate(token)
+			if !ok {
+				continue
+			}
+			year, month, day, ok := mapIntsToDMY(ints)
+			if !ok {
+				continue
+			}
+			matches = append(matches, GuessMatch{
+				Pattern: PatternDate, Start: i, End: j, Token: token,
+				Year: year, Month: month, Day: day, Separator: separator,
+			})
+		}
+	}
+
+	// Drop dates fully contained in another date match
+	var filtered []GuessMatch
+	for a, m := range matches {
+		contained := false
+		for b, other := range matches {
+			if a != b && other.Start <= m.Start && other.End >= m.End && (other.Start != m.Start || other.End != m.End) {
+				contained = true
+				break
+			}
+		}
+		if !contained {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// splitSeparatedDate parses tokens like 1/2/1990 or 2024-03-15
+func splitSeparatedDate(token string) ([3]int, string, bool) {
+	const separators = " /\\_.-"
+
+	var parts []string
+	separator := ""
+	current := strings.Builder{}
+	for _, r := range token {
+		if r >= '0' && r <= '9' {
+			current.WriteRune(r)
+			continue
+		}
+		if !strings.ContainsRune(separators, r) || (separator != "" && string(r) != separator) {
+			return [3]int{}, "", false
+		}
+		separator = string(r)
+		parts = append(parts, current.String())
+		current.Reset()
+	}
+	parts = append(parts, current.String())
+
+	if len(parts) != 3 || separator == "" {
+		return [3]int{}, "", false
+	}
+	if len(parts[0]) < 1 || len(parts[0]) > 4 || len(parts[1]) < 1 || len(parts[1]) > 2 || len(parts[2]) < 1 || len(parts[2]) > 4 {
+		return [3]int{}, "", false
+	}
+	return [3]int{atoi(parts[0]), atoi(parts[1]), atoi(parts[2])}, separator, true
+}
+
+// mapIntsToDMY interprets three integers as a day, month and year in any
+// common order, rejecting combinations that cannot be a date
+func mapIntsToDMY(ints [3]int) (year, month, day int, ok bool) {
+	if ints[1] > 31 || ints[1] <= 0 {
+		return 0, 0, 0, false
+	}
+
+	over12, over31, under1 := 0, 0, 0
+	for _, v := range ints {
+		if (v > 99 && v < dateMinYear) || v > dateMaxYear {
+			return 0, 0, 0, false
+		}
+		if v > 31 {
+			over31++
+		}
+		if v > 12 {
+			over12++
+		}
+		if v <= 0 {
+			under1++
+		}
+	}
+	if over31 >= 2 || over12 == 3 || under1 >= 2 {
+		return 0, 0, 0, false
+	}
+
+	splits := [][3]int{{ints[2], ints[0], ints[1]}, {ints[0], ints[1], ints[2]}}
+	for _, s := range splits {
+		if s[0] >= dateMinYear && s[0] <= dateMaxYear {
+			if month, day, ok := mapIntsToDM(s[0], s[1], s[2]); ok {
+				return s[0], month, day, true
+			}
+			return 0, 0, 0, false
+		}
+	}
+	for _, s := range splits {
+		year := twoToFourDigitYear(s[0])
+		if month, day, ok := mapIntsToDM(year, s[1], s[2]); ok {
+			return year, month, day, true
+		}
+	}
+	return 0, 0, 0, false
+}
+
+// mapIntsToDM accepts a day and month in either order, as long as the day
+// exists in that month of year
+func mapIntsToDM(year, a, b int) (month, day int, ok bool) {
+	for _, pair := range [][2]int{{a, b}, {b, a}} {
+		d, m := pair[0], pair[1]
+		if entities.IsCalendarDate(year, m, d) {
+			return m, d, true
+		}
+	}
+	return 0, 0, false
+}
+
+// twoToFourDigitYear expands 87 to 1987 and 15 to 2015
+func twoToFourDigitYear(year int) int {
+	switch {
+	case year > 99:
+		return year
+	case year > 50:
+		return year + 1900
+	default:
+		return year + 2000
+	}
+}
+
+// sortedDictionaryNames returns dictionary names in a stable order
+func sortedDictionaryNames(dictionaries map[string]RankedDictionary) []string {
+	names := make([]string, 0, len(dictionaries))
+	for name := range dictionaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func equalRunes(a, b []rune) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func atoi(s string) int {
+	v, _ := strconv.Atoi(s)
+	return v
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,324 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"golang.org/x/text/unicode/norm"
+)
+
+// StandardNIST80063B selects evaluation per NIST SP 800-63B (rev. 4)
+const StandardNIST80063B = "nist-800-63b"
+
+// Length requirements of SP 800-63B section 3.1.1.2, counted in Unicode code
+// points after normalization
+const (
+	NISTMinLength         = 15 // passwords used as a single authentication factor
+	NISTMinLengthMFA      = 8  // passwords used as part of multi-factor authentication
+	NISTMaxAcceptedLength = 64 // verifiers must accept passwords at least this long
+)
+
+// Compliance check outcomes
+const (
+	ComplianceStatusPass = "pass"
+	ComplianceStatusWarn = "warn"
+	ComplianceStatusFail = "fail"
+)
+
+// ComplianceCheck is the outcome of one requirement of a standard
+type ComplianceCheck struct {
+	Requirement string
+	Status      string
+	Detail      string
+	Reference   string // where the requirement is defined, when known
+}
+
+// ComplianceResult is the evaluation of a password against a standard
+type ComplianceResult struct {
+	Standard   string
+	Normalized string // the password after NFKC normalization
+	Length     int    // code points in the normalized password
+	MinLength  int
+	Checks     []ComplianceCheck
+	Compliant  bool // no check failed
+}
+
+// NISTComplianceChecker evaluates passwords and policies against SP 800-63B.
+// Unlike PasswordStrengthChecker it imposes no composition rules: the
+// guideline asks only for length and a blocklist of known-bad passwords.
+type NISTComplianceChecker struct {
+	guessEstimator *GuessEstimator
+}
+
+// NewNISTComplianceChecker creates a checker that uses guessEstimator to
+// compare passwords against its dictionaries of common passwords and words
+func NewNISTComplianceChecker(guessEstimator *GuessEstimator) *NISTComplianceChecker {
+	return &NISTComplianceChecker{guessEstimator: guessEstimator}
+}
+
+// MinLength returns the minimum password length for the authentication setting
+func (nc *NISTComplianceChecker) MinLength(mfa bool) int {
+	if mfa {
+		return NISTMinLengthMFA
+	}
+	return NISTMinLength
+}
+
+// Normalize returns the NFKC form of a password, which the standard has
+// verifiers measure, hash and compare against blocklists
+func (nc *NISTComplianceChecker) Normalize(password string) string {
+	return norm.NFKC.String(password)
+}
+
+// Check evaluates a password. The blocklist evidence — breach lookups of the
+// normalized password, banned words and user-context findings — is taken
+// from result, and userInputs are context-specific words to compare the
+// whole password with.
+func (nc *NISTComplianceChecker) Check(password string, mfa bool, result StrengthCheckResult, userInputs ...string) ComplianceResult {
+	normalized := nc.Normalize(password)
+	compliance := ComplianceResult{
+		Standard:   StandardNIST80063B,
+		Normalized: normalized,
+		Length:     utf8.RuneCountInString(normalized),
+		MinLength:  nc.MinLength(mfa),
+	}
+
+	factor := "single-factor"
+	if mfa {
+		factor = "multi-factor"
+	}
+	if compliance.Length >= compliance.MinLength {
+		compliance.add("Minimum length", ComplianceStatusPass,
+			fmt.Sprintf("%d characters (at least %d for %s use)", compliance.Length, compliance.MinLength, factor))
+	} else {
+		compliance.add("Minimum length", ComplianceStatusFail,
+			fmt.Sprintf("%d characters; at least %d are required for %s use", compliance.Length, compliance.MinLength, factor))
+	}
+
+	if compliance.Length <= NISTMaxAcceptedLength {
+		compliance.add("Maximum length", ComplianceStatusPass,
+			fmt.Sprintf("within the %d characters every verifier must accept", NISTMaxAcceptedLength))
+	} else {
+		compliance.add("Maximum length", ComplianceStatusWarn,
+			fmt.Sprintf("longer than the %d characters every verifier must accept; some services may refuse it", NISTMaxAcceptedLength))
+	}
+
+	compliance.Checks = append(compliance.Checks,
+		nc.checkCharacters(password, normalized),
+		breachCheck(result),
+		commonPasswordCheck(nc.guessEstimator, normalized),
+		contextCheck(normalized, result, userInputs))
+
+	compliance.add("Composition rules", ComplianceStatusPass,
+		"not applied; the standard forbids requiring mixtures of character types")
+
+	compliance.Compliant = true
+	for _, check := range compliance.Checks {
+		if check.Status == ComplianceStatusFail {
+			compliance.Compliant = false
+		}
+	}
+	return compliance
+}
+
+// add appends a check to the result
+func (cr *ComplianceResult) add(requirement, status, detail string) {
+	cr.Checks = append(cr.Checks, ComplianceCheck{Requirement: requirement, Status: status, Detail: detail})
+}
+
+// checkCharacters confirms the password uses only characters a verifier must
+// accept: printing characters and spaces in any script
+func (nc *NISTComplianceChecker) checkCharacters(password, normalized string) ComplianceCheck {
+	check := ComplianceCheck{Requirement: "Unicode characters", Status: ComplianceStatusPass}
+	for _, r := range normalized {
+		if r == utf8.RuneError || (!unicode.IsPrint(r) && r != ' ') {
+			check.Status = ComplianceStatusWarn
+			check.Detail = fmt.Sprintf("contains the non-printing character %U, which services may reject", r)
+			return check
+		}
+	}
+
+	if normalized != password {
+		check.Detail = "accepted; NFKC normalization changes the password, so type it consistently"
+	} else {
+		check.Detail = "accepted as typed (already NFKC-normalized)"
+	}
+	return check
+}
+
+// breachCheck reports the breach corpus lookups
+func breachCheck(result StrengthCheckResult) ComplianceCheck {
+	check := ComplianceCheck{Requirement: "Breached passwords"}
+	if len(result.Breaches) == 0 {
+		check.Status, check.Detail = ComplianceStatusWarn, "not checked; pass --breach-db or --breach-api"
+		return check
+	}
+
+	var total int64
+	for _, breach := range result.Breaches {
+		total += breach.Count
+	}
+	if result.Breached() {
+		check.Status, check.Detail = ComplianceStatusFail, fmt.Sprintf("seen %d times in breach corpora", total)
+	} else {
+		check.Status, check.Detail = ComplianceStatusPass, fmt.Sprintf("not found in %d breach corpus lookup(s)", len(result.Breaches))
+	}
+	return check
+}
+
+// commonPasswordCheck rejects passwords that are mostly a common password or
+// dictionary word (including l33t and reversed spellings), such as
+// password1!, or nothing more than repeated, sequential or keyboard-adjacent
+// characters
+func commonPasswordCheck(guessEstimator *GuessEstimator, normalized string) ComplianceCheck {
+	check := ComplianceCheck{Requirement: "Common passwords and words", Status: ComplianceStatusFail}
+
+	estimate := guessEstimator.Estimate(normalized)
+	length := utf8.RuneCountInString(normalized)
+	for _, match := range estimate.Sequence {
+		if match.Pattern == PatternDictionary && 2*(match.End-match.Start+1) > length {
+			check.Detail = fmt.Sprintf("'%s' from the %s dictionary makes up most of it", match.MatchedWord, match.Dictionary)
+			return check
+		}
+	}
+	if len(estimate.Sequence) == 1 {
+		match := estimate.Sequence[0]
+		switch match.Pattern {
+		case PatternRepeat:
+			check.Detail = "consists of repeated characters"
+		case PatternSequence:
+			check.Detail = "is a sequence of consecutive characters"
+		case PatternSpatial:
+			check.Detail = "is a keyboard pattern"
+		case PatternDate:
+			check.Detail = "is a date"
+		}
+	}
+
+	if check.Detail == "" {
+		check.Status, check.Detail = ComplianceStatusPass, "not a dictionary word, common password or simple pattern"
+	}
+	return check
+}
+
+// contextCheck rejects passwords derived from context-specific words: banned
+// words, the account holder's details, or the userInputs themselves. Without
+// userInputs nothing context-specific was supplied to compare against.
+func contextCheck(normalized string, result StrengthCheckResult, userInputs []string) ComplianceCheck {
+	check := ComplianceCheck{Requirement: "Context-specific words"}
+
+	var reasons []string
+	for _, match := range result.BannedWords {
+		reasons = append(reasons, fmt.Sprintf("contains the banned %s word '%s'", match.List, match.Word))
+	}
+	for _, finding := range result.ContextFindings {
+		reasons = append(reasons, finding.Description())
+	}
+	lower := strings.ToLower(normalized)
+	for _, input := range userInputs {
+		if strings.EqualFold(input, lower) {
+			reasons = append(reasons, fmt.Sprintf("is the context-specific word '%s'", input))
+		}
+	}
+
+	switch {
+	case len(reasons) > 0:
+		check.Status, check.Detail = ComplianceStatusFail, strings.Join(reasons, "; ")
+	case len(userInputs) == 0:
+		check.Status, check.Detail = ComplianceStatusWarn, "not checked; pass --banned-words or the account details (--user, --email, ...)"
+	default:
+		check.Status, check.Detail = ComplianceStatusPass, "no resemblance to the service or account holder"
+	}
+	return check
+}
+
+// AuditPolicy lists where a password policy conflicts with the standard
+func (nc *NISTComplianceChecker) AuditPolicy(policy entities.PasswordPolicy, mfa bool) []ComplianceCheck {
+	var checks []ComplianceCheck
+	add := func(requirement, status, detail string) {
+		checks = append(checks, ComplianceCheck{Requirement: requirement, Status: status, Detail: detail})
+	}
+
+	if minLength := nc.MinLength(mfa); policy.MinLength < minLength {
+		add("Minimum length", ComplianceStatusFail, fmt.Sprintf("min_length %d is below the required %d", policy.MinLength, minLength))
+	} else {
+		add("Minimum length", ComplianceStatusPass, fmt.Sprintf("min_length %d", policy.MinLength))
+	}
+
+	if policy.MaxLength > 0 && policy.MaxLength < NISTMaxAcceptedLength {
+		add("Maximum length", ComplianceStatusFail,
+			fmt.Sprintf("max_length %d rejects passwords of up to %d characters, which verifiers must accept", policy.MaxLength, NISTMaxAcceptedLength))
+	} else {
+		add("Maximum length", ComplianceStatusPass, fmt.Sprintf("accepts at least %d characters", NISTMaxAcceptedLength))
+	}
+
+	var rules []string
+	for _, rule := range []struct {
+		set  bool
+		name string
+	}{
+		{policy.RequireLetters, "require_letters"},
+		{policy.RequireLower, "require_lower"},
+		{policy.RequireUpper, "require_upper"},
+		{policy.RequireNumbers, "require_numbers"},
+		{policy.RequireSymbols, "require_symbols"},
+		{policy.MinCharClasses > 1, "min_char_classes"},
+	} {
+		if rule.set {
+			rules = append(rules, rule.name)
+		}
+	}
+	if len(rules) > 0 {
+		add("Composition rules", ComplianceStatusFail,
+			fmt.Sprintf("requiring character-type mixtures is forbidden (%s)", strings.Join(rules, ", ")))
+	} else {
+		add("Composition rules", ComplianceStatusPass, "none imposed")
+	}
+
+	if policy.ExpiryDays > 0 {
+		add("Periodic changes", ComplianceStatusFail,
+			fmt.Sprintf("expiry_days %d forces rotation; change passwords only on evidence of compromise", policy.ExpiryDays))
+	} else {
+		add("Periodic changes", ComplianceStatusPass, "no forced rotation")
+	}
+
+	if policy.Blocklist {
+		add("Blocklist", ComplianceStatusPass, "candidates are checked against breached and common passwords")
+	} else {
+		add("Blocklist", ComplianceStatusFail, "candidates must be compared against breached, common and context-specific passwords")
+	}
+
+	if !policy.AllowSpaces {
+		add("Spaces", ComplianceStatusWarn, "spaces should be accepted so passphrases can be used")
+	}
+	if !policy.AllowUnicode {
+		add("Unicode characters", ComplianceStatusWarn, "Unicode characters should be accepted, normalized before hashing")
+	}
+	if policy.AllowHints {
+		add("Password hints", ComplianceStatusFail, "hints readable by unauthenticated users are not allowed")
+	}
+	if policy.SecurityQuests {
+		add("Knowledge-based prompts", ComplianceStatusFail, "security questions must not be used to choose or recover passwords")
+	}
+
+	return checks
+}
+
+// AuditConfig lists where passwords generated with config would fall short of
+// the standard. Character-type choices are not audited: a generated password
+// may mix types freely, the standard only forbids requiring it of users.
+func (nc *NISTComplianceChecker) AuditConfig(config entities.PasswordConfig, mfa bool) []ComplianceCheck {
+	var checks []ComplianceCheck
+	if minLength := nc.MinLength(mfa); config.Length < minLength {
+		checks = append(checks, ComplianceCheck{Requirement: "Minimum length", Status: ComplianceStatusFail,
+			Detail: fmt.Sprintf("length %d is below the required %d; use -l %d or longer", config.Length, minLength, minLength)})
+	}
+	if config.Length > NISTMaxAcceptedLength {
+		checks = append(checks, ComplianceCheck{Requirement: "Maximum length", Status: ComplianceStatusWarn,
+			Detail: fmt.Sprintf("length %d exceeds the %d characters every verifier must accept; some services may refuse it", config.Length, NISTMaxAcceptedLength)})
+	}
+	return checks
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// complianceStatuses maps each requirement to its outcome
+func complianceStatuses(checks []ComplianceCheck) map[string]string {
+	statuses := make(map[string]string, len(checks))
+	for _, check := range checks {
+		statuses[check.Requirement] = check.Status
+	}
+	return statuses
+}
+
+func TestNISTComplianceChecker_Check(t *testing.T) {
+	checker := NewNISTComplianceChecker(NewGuessEstimator())
+	strength := NewPasswordStrengthChecker()
+
+	tests := []struct {
+		name          string
+		password      string
+		mfa           bool
+		wantCompliant bool
+		wantFailed    string
+	}{
+		{"lowercase passphrase", "correct horse battery staple", false, true, ""},
+		{"too short for single factor", "kD8#mQ2$vL9!", false, false, "Minimum length"},
+		{"long enough with MFA", "kD8#mQ2$vL9!", true, true, ""},
+		{"common password", "password", true, false, "Common passwords and words"},
+		{"decorated common password", "password1!", true, false, "Common passwords and words"},
+		{"keyboard pattern", "qwertyuiop", true, false, "Common passwords and words"},
+		{"repeated characters", "aaaaaaaaaaaaaaaa", false, false, "Common passwords and words"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := strength.CheckPasswordStrength(entities.NewPassword(tt.password))
+			compliance := checker.Check(tt.password, tt.mfa, result)
+			if compliance.Compliant != tt.wantCompliant {
+				t.Errorf("Compliant = %v, want %v (checks %+v)", compliance.Compliant, tt.wantCompliant, compliance.Checks)
+			}
+			if tt.wantFailed != "" && complianceStatuses(compliance.Checks)[tt.wantFailed] != ComplianceStatusFail {
+				t.Errorf("%s did not fail: %+v", tt.wantFailed, compliance.Checks)
+			}
+			if status := complianceStatuses(compliance.Checks)["Composition rules"]; status != ComplianceStatusPass {
+				t.Errorf("Composition rules = %s, want pass", status)
+			}
+		})
+	}
+}
+
+func TestNISTComplianceChecker_CheckNormalizesUnicode(t *testing.T) {
+	checker := NewNISTComplianceChecker(NewGuessEstimator())
+
+	// The fullwidth letters normalize to ASCII and the combining accent to a
+	// single code point, so each counts as one character
+	password := "ｃａｆｅ́ au lait, s'il vous plaît"
+	result := NewPasswordStrengthChecker().CheckPasswordStrength(entities.NewPassword(password))
+	compliance := checker.Check(password, false, result)
+
+	if want := "café au lait, s'il vous plaît"; compliance.Normalized != want {
+		t.Errorf("Normalized = %q, want %q", compliance.Normalized, want)
+	}
+	if compliance.Length != 29 {
+		t.Errorf("Length = %d, want 29 code points", compliance.Length)
+	}
+	if !compliance.Compliant {
+		t.Errorf("Check = %+v, want compliant", compliance.Checks)
+	}
+}
+
+func TestNISTComplianceChecker_CheckContext(t *testing.T) {
+	checker := NewNISTComplianceChecker(NewGuessEstimator())
+	strength := NewPasswordStrengthChecker()
+
+	password := "examplecorporation"
+	result := strength.CheckPasswordStrength(entities.NewPassword(password))
+	if status := complianceStatuses(checker.Check(password, false, result).Checks)["Context-specific words"]; status != ComplianceStatusWarn {
+		t.Errorf("without inputs, context check = %s, want warn", status)
+	}
+
+	compliance := checker.Check(password, false, result, "ExampleCorporation")
+	if status := complianceStatuses(compliance.Checks)["Context-specific words"]; status != ComplianceStatusFail {
+		t.Errorf("context check = %s, want fail", status)
+	}
+
+	breached := strength.ApplyBreachResults(result, []entities.BreachResult{{Source: "test", Found: true, Count: 3}})
+	if status := complianceStatuses(checker.Check(password, false, breached).Checks)["Breached passwords"]; status != ComplianceStatusFail {
+		t.Errorf("breach check = %s, want fail", status)
+	}
+}
+
+func TestNISTComplianceChecker_AuditPolicy(t *testing.T) {
+	checker := NewNISTComplianceChecker(NewGuessEstimator())
+
+	legacy := entities.PasswordPolicy{MinLength: 8, MaxLength: 16, RequireUpper: true, RequireSymbols: true, ExpiryDays: 90, AllowHints: true}
+	statuses := complianceStatuses(checker.AuditPolicy(legacy, false))
+	for _, requirement := range []string{"Minimum length", "Maximum length", "Composition rules", "Periodic changes", "Blocklist", "Password hints"} {
+		if statuses[requirement] != ComplianceStatusFail {
+			t.Errorf("%s = %q, want fail", requirement, statuses[requirement])
+		}
+	}
+
+	modern := entities.PasswordPolicy{MinLength: 15, Blocklist: true, AllowSpaces: true, AllowUnicode: true}
+	for _, check := range checker.AuditPolicy(modern, false) {
+		if check.Status != ComplianceStatusPass {
+			t.Errorf("%s = %s (%s), want pass", check.Requirement, check.Status, check.Detail)
+		}
+	}
+
+	if statuses := complianceStatuses(checker.AuditPolicy(entities.PasswordPolicy{MinLength: 8}, true)); statuses["Minimum length"] != ComplianceStatusPass {
+		t.Errorf("MFA minimum length = %s, want pass", statuses["Minimum length"])
+	}
+}
+
+func TestNISTComplianceChecker_AuditConfig(t *testing.T) {
+	checker := NewNISTComplianceChecker(NewGuessEstimator())
+
+	if checks := checker.AuditConfig(entities.PasswordConfig{Length: 12}, false); len(checks) != 1 || checks[0].Status != ComplianceStatusFail {
+		t.Errorf("AuditConfig(12) = %+v, want one length failure", checks)
+	}
+	if checks := checker.AuditConfig(entities.PasswordConfig{Length: 12}, true); len(checks) != 0 {
+		t.Errorf("AuditConfig(12, mfa) = %+v, want none", checks)
+	}
+	if checks := checker.AuditConfig(entities.PasswordConfig{Length: 80}, false); len(checks) != 1 || checks[0].Status != ComplianceStatusWarn {
+		t.Errorf("AuditConfig(80) = %+v, want one length warning", checks)
+	}
+}
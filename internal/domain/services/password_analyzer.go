@@ -3,146 +3,185 @@ package services
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/kumarasakti/passgen/internal/domain/entities"
 )
 
+// CrackTimeEstimate is the expected time to crack a password under one attack scenario
+type CrackTimeEstimate struct {
+	Scenario entities.AttackScenario
+	Guesses  float64
+	Duration entities.CrackDuration
+}
+
 // PasswordAnalysis represents the result of password analysis
 type PasswordAnalysis struct {
 	Password       entities.Password
 	CharsetSize    int
 	CharacterTypes []string
 	Entropy        float64
+	Guesses        float64 // expected guesses for a brute-force search
 	Strength       entities.PasswordStrength
-	StrengthEmoji  string
-	TimeToCrack    string
-	SecurityLevel  string
-	Tips           []string
-	Celebration    string
+	CrackTimes     []CrackTimeEstimate
+	// Dictionary words found after undoing l33t substitutions and case changes;
+	// each counts at its dictionary-rank estimate rather than by charset
+	RecognizedWords []GuessMatch
+	// Mixed scripts and lookalike characters that make the password hard to
+	// read back or type correctly
+	Confusables entities.ConfusableReport
+	Factors     []ScoringFactor // add up to Entropy
 	// Word-based password specific fields
 	WordBased             bool
 	OriginalWord          string
 	TransformationQuality string
 }
 
+// minRecognizedWordLength is the shortest dictionary word the analyzer treats
+// as recognized; shorter words turn up by chance in random passwords
+const minRecognizedWordLength = 4
+
 // PasswordAnalyzer handles password security analysis
 type PasswordAnalyzer struct {
 	charsetManager *entities.CharacterSet
+	guessEstimator *GuessEstimator
 }
 
 // NewPasswordAnalyzer creates a new PasswordAnalyzer instance
 func NewPasswordAnalyzer() *PasswordAnalyzer {
 	return &PasswordAnalyzer{
 		charsetManager: entities.NewCharacterSet(),
+		guessEstimator: NewGuessEstimator(),
 	}
 }
 
 // AnalyzePassword performs comprehensive analysis of a password
 func (pa *PasswordAnalyzer) AnalyzePassword(password entities.Password, config entities.PasswordConfig) PasswordAnalysis {
+	return pa.analyze(password, config, true)
+}
+
+// AnalyzeGeneratedPassword analyzes a password drawn at random from config.
+// Words that turn up by chance were not chosen by a person, so they give an
+// attacker nothing and the charset estimate stands.
+func (pa *PasswordAnalyzer) AnalyzeGeneratedPassword(password entities.Password, config entities.PasswordConfig) PasswordAnalysis {
+	return pa.analyze(password, config, false)
+}
+
+func (pa *PasswordAnalyzer) analyze(password entities.Password, config entities.PasswordConfig, recognizeWords bool) PasswordAnalysis {
 	charsetSize := pa.charsetManager.CalculateCharsetSize(config)
 	characterTypes := password.GetCharacterTypes()
 
-	// Calculate entropy: log2(charset^length)
-	entropy := float64(password.Length) * math.Log2(float64(charsetSize))
+	// Calculate entropy: log2(charset^length), less what recognized words give away
+	bitsPerChar := math.Log2(float64(charsetSize))
+	entropy := float64(password.Length) * bitsPerChar
+	start, end := wholePassword(password.Length)
+	factors := []ScoringFactor{{FactorCharset, entropy, start, end,
+		fmt.Sprintf("%d characters from a set of %d, %.1f bits each", password.Length, charsetSize, bitsPerChar)}}
 
-	// Determine strength and related properties
-	strength, strengthEmoji, securityLevel, celebration, tips := pa.determineStrength(entropy, password.Length, len(characterTypes))
+	var recognized []GuessMatch
+	recognizedEntropy := entropy
+	if recognizeWords {
+		recognized, recognizedEntropy = pa.recognizeWords(password.Value, charsetSize)
+	}
+	if len(recognized) > 0 && recognizedEntropy < entropy {
+		entropy = recognizedEntropy
+		for _, word := range recognized {
+			random := float64(word.End-word.Start+1) * bitsPerChar
+			factors = append(factors, ScoringFactor{FactorDictionary, math.Log2(word.Guesses) - random, word.Start, word.End,
+				fmt.Sprintf("'%s' is the dictionary word '%s' (%s list, rank %d): %.1f bits rather than %.1f",
+					word.Token, word.MatchedWord, word.Dictionary, word.Rank, math.Log2(word.Guesses), random)})
+		}
+	}
 
-	// Calculate time to crack
-	timeToCrack := pa.calculateTimeToCrack(charsetSize, password.Length)
+	// Mixing scripts was asked for when lookalikes are already excluded
+	confusables := entities.DetectConfusables(password.Value)
+	warnings := confusables
+	if config.ExcludeConfusable {
+		warnings.MixedScript = false
+	}
+	factors = append(factors, confusableFactors(warnings, password.Length)...)
+
+	// On average an attacker searches half the space
+	guesses := math.Pow(2, entropy) / 2
+	if math.IsInf(guesses, 1) {
+		guesses = math.MaxFloat64
+	}
+	crackTimes := pa.EstimateCrackTimes(guesses, entities.DefaultAttackScenarios())
 
 	return PasswordAnalysis{
-		Password:       password,
-		CharsetSize:    charsetSize,
-		CharacterTypes: characterTypes,
-		Entropy:        entropy,
-		Strength:       strength,
-		StrengthEmoji:  strengthEmoji,
-		TimeToCrack:    timeToCrack,
-		SecurityLevel:  securityLevel,
-		Tips:           tips,
-		Celebration:    celebration,
+		Password:        password,
+		CharsetSize:     charsetSize,
+		CharacterTypes:  characterTypes,
+		Entropy:         entropy,
+		RecognizedWords: recognized,
+		Confusables:     confusables,
+		Guesses:         guesses,
+		Strength:        StrengthForEntropy(entropy),
+		CrackTimes:      crackTimes,
+		Factors:         factors,
 	}
 }
 
-// determineStrength determines password strength based on entropy and other factors
-func (pa *PasswordAnalyzer) determineStrength(entropy float64, length, charTypeCount int) (entities.PasswordStrength, string, string, string, []string) {
-	var strength entities.PasswordStrength
-	var strengthEmoji, securityLevel, celebration string
-	var tips []string
+// recognizeWords finds dictionary words in a password, including l33t and
+// case-changed spellings, and returns them with the password's entropy when
+// each word counts at its dictionary-rank estimate and every other
+// character at the charset size
+func (pa *PasswordAnalyzer) recognizeWords(password string, charsetSize int) ([]GuessMatch, float64) {
+	estimate := pa.guessEstimator.Estimate(password)
 
-	switch {
-	case entropy >= 100:
-		strength = entities.ExtremelyStrong
-		strengthEmoji = "🔥"
-		securityLevel = "Quantum-resistant for the foreseeable future!"
-		celebration = "Brr, that's ice cold security! Even hackers are shivering! 🥶"
-	case entropy >= 80:
-		strength = entities.VeryStrong
-		strengthEmoji = "💪"
-		securityLevel = "Exceeds security standards for high-value accounts"
-		celebration = "Someone's taking this security thing seriously! 🌟"
-	case entropy >= 60:
-		strength = entities.Strong
-		strengthEmoji = "💯"
-		securityLevel = "Great for securing important accounts"
-		celebration = "Not bad, you actually read the security guidelines! 🎯"
-	case entropy >= 40:
-		strength = entities.Medium
-		strengthEmoji = "⚡"
-		securityLevel = "Adequate for most general purposes"
-		celebration = "Well, it's... adequate. I guess that's something! 👍"
-		if length < 12 {
-			tips = append(tips, "Consider using 12+ characters for better security")
-		}
-		if charTypeCount < 3 {
-			tips = append(tips, "Add more character types (symbols, numbers) for stronger security")
+	var words []GuessMatch
+	entropy, covered := 0.0, 0
+	for _, match := range estimate.Sequence {
+		length := len([]rune(match.Token))
+		if match.Pattern != PatternDictionary || length < minRecognizedWordLength {
+			continue
 		}
-	case entropy >= 25:
-		strength = entities.Weak
-		strengthEmoji = "😰"
-		securityLevel = "Suitable only for low-security uses"
-		celebration = "Oh honey, we need to talk about your password choices... 💪"
-		tips = append(tips, "Use at least 12 characters")
-		tips = append(tips, "Include uppercase, lowercase, numbers, and symbols")
-		tips = append(tips, "Try `passgen --secure` for maximum protection!")
-	default:
-		strength = entities.VeryWeak
-		strengthEmoji = "🚨"
-		securityLevel = "Not recommended for any security purposes"
-		celebration = "Yikes! Even my grandma would crack this in her sleep! 🚀"
-		tips = append(tips, "Use at least 12 characters")
-		tips = append(tips, "Include multiple character types")
-		tips = append(tips, "Try `passgen --secure -l 16` for excellent security!")
+		words = append(words, match)
+		entropy += math.Log2(match.Guesses)
+		covered += length
 	}
 
-	return strength, strengthEmoji, securityLevel, celebration, tips
+	entropy += float64(len([]rune(password))-covered) * math.Log2(float64(charsetSize))
+	return words, entropy
 }
 
-// calculateTimeToCrack calculates time to crack the password
-func (pa *PasswordAnalyzer) calculateTimeToCrack(charsetSize, length int) string {
-	// Assuming 1 trillion guesses per second
-	guessesPerSecond := 1e12
-	possibleCombinations := math.Pow(float64(charsetSize), float64(length))
-	secondsToCrack := possibleCombinations / (2 * guessesPerSecond) // Average case
-
-	if secondsToCrack < 60 {
-		return "Less than a minute"
-	} else if secondsToCrack < 3600 {
-		return fmt.Sprintf("%.1f minutes", secondsToCrack/60)
-	} else if secondsToCrack < 86400 {
-		return fmt.Sprintf("%.1f hours", secondsToCrack/3600)
-	} else if secondsToCrack < 31536000 {
-		return fmt.Sprintf("%.1f days", secondsToCrack/86400)
-	} else if secondsToCrack < 31536000000 {
-		return fmt.Sprintf("%.1f years", secondsToCrack/31536000)
-	} else {
-		// For very large numbers, use scientific notation
-		years := secondsToCrack / 31536000
-		if years > 1e15 {
-			return fmt.Sprintf("%.1e years", years)
-		} else {
-			return fmt.Sprintf("%.0f years", years)
-		}
+// joinWithAnd joins items as "a, b and c"
+func joinWithAnd(items []string) string {
+	if len(items) < 2 {
+		return strings.Join(items, "")
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " and " + items[len(items)-1]
+}
+
+// StrengthForEntropy rates bits of entropy. Estimators measuring guesses
+// convert them to bits so every estimator rates on this one scale.
+func StrengthForEntropy(bits float64) entities.PasswordStrength {
+	switch {
+	case bits >= 100:
+		return entities.ExtremelyStrong
+	case bits >= 80:
+		return entities.VeryStrong
+	case bits >= 60:
+		return entities.Strong
+	case bits >= 40:
+		return entities.Medium
+	case bits >= 25:
+		return entities.Weak
+	default:
+		return entities.VeryWeak
+	}
+}
+
+// EstimateCrackTimes returns the expected time to make the given number of
+// guesses under each attack scenario
+func (pa *PasswordAnalyzer) EstimateCrackTimes(guesses float64, scenarios []entities.AttackScenario) []CrackTimeEstimate {
+	estimates := make([]CrackTimeEstimate, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		estimates = append(estimates, CrackTimeEstimate{
+			Scenario: scenario,
+			Guesses:  guesses,
+			Duration: entities.NewCrackDuration(guesses / scenario.GuessesPerSecond),
+		})
 	}
+	return estimates
 }
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// DefaultAuditTop is the number of worst offenders an audit reports by default
+const DefaultAuditTop = 10
+
+// maxSimilarityEntries bounds the unique passwords grouped by similarity;
+// grouping is quadratic, so it is skipped for very large audits
+const maxSimilarityEntries = 5000
+
+// PasswordDisplay is how reports show passwords and secrets
+type PasswordDisplay int
+
+// Password displays, safest first
+const (
+	DisplayMasked  PasswordDisplay = iota // a fixed-width mask giving away only the length
+	DisplayPartial                        // the first and last characters
+	DisplayFull                           // the password itself
+)
+
+// Show renders a password for a report
+func (d PasswordDisplay) Show(password string) string {
+	switch d {
+	case DisplayFull:
+		return password
+	case DisplayPartial:
+		return PartialMask(password)
+	default:
+		return MaskPassword(password)
+	}
+}
+
+// Audit issues reported per entry
+const (
+	AuditIssueWeak     = "weak"
+	AuditIssueReused   = "reused"
+	AuditIssueSimilar  = "similar"
+	AuditIssueUsername = "contains username"
+)
+
+// auditLengthBuckets are the lower bounds of the length histogram buckets
+var auditLengthBuckets = []int{1, 8, 12, 16, 20}
+
+// AuditEntry is one password read for an audit, with its source line number
+// (or entry number for structured exports) and, for password manager
+// exports, the entry it belongs to
+type AuditEntry struct {
+	Line     int
+	Password string
+	Title    string
+	URL      string
+	Username string
+}
+
+// AuditRef identifies an audited entry without its password
+type AuditRef struct {
+	Line     int    `json:"line"`
+	Title    string `json:"title,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// Label names the entry for display: its title, URL or line number
+func (r AuditRef) Label() string {
+	switch {
+	case r.Title != "":
+		return r.Title
+	case r.URL != "":
+		return r.URL
+	default:
+		return fmt.Sprintf("line %d", r.Line)
+	}
+}
+
+// AuditCount is a labelled tally in an audit report
+type AuditCount struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// AuditOffender is one of the weakest passwords in an audit
+type AuditOffender struct {
+	AuditRef
+	Password     string   `json:"password"` // masked unless the audit reveals passwords
+	Strength     string   `json:"strength"`
+	Score        int      `json:"score"` // guess-based score, 0-4
+	GuessesLog10 float64  `json:"guesses_log10"`
+	Patterns     []string `json:"patterns,omitempty"`
+}
+
+// AuditReuse is a password shared by several entries
+type AuditReuse struct {
+	Password string     `json:"password"` // masked unless the audit reveals passwords
+	Count    int        `json:"count"`
+	Entries  []AuditRef `json:"entries"`
+}
+
+// AuditSimilar is a group of distinct but near-duplicate passwords, such as
+// Summer2024! and Summer2025!
+type AuditSimilar struct {
+	Passwords []string   `json:"passwords"` // masked unless the audit reveals passwords
+	Entries   []AuditRef `json:"entries"`   // every entry using one of the passwords
+}
+
+// AuditFinding lists the issues found with one entry
+type AuditFinding struct {
+	AuditRef
+	Password string   `json:"password"` // masked unless the audit reveals passwords
+	Strength string   `json:"strength"`
+	Issues   []string `json:"issues"`
+}
+
+// AuditReport aggregates the analysis of many passwords
+type AuditReport struct {
+	Total          int             `json:"total"`
+	Unique         int             `json:"unique"`
+	Strengths      []AuditCount    `json:"strengths"`
+	Scores         []AuditCount    `json:"scores"`
+	Patterns       []AuditCount    `json:"patterns"`
+	Lengths        []AuditCount    `json:"lengths"`
+	Reused         []AuditReuse    `json:"reused"`
+	Similar        []AuditSimilar  `json:"similar"`
+	SimilarSkipped bool            `json:"similar_skipped,omitempty"` // too many unique passwords to group by similarity
+	WorstOffenders []AuditOffender `json:"worst_offenders"`
+	Findings       []AuditFinding  `json:"findings"`
+}
+
+// PasswordAuditor analyzes batches of passwords and aggregates the results,
+// so no individual password needs to be reported
+type PasswordAuditor struct {
+	strengthChecker *PasswordStrengthChecker
+	guessEstimator  *GuessEstimator
+	patternDetector *entities.PasswordPatternDetector
+	similarity      *PasswordSimilarityEngine
+}
+
+// NewPasswordAuditor creates a new PasswordAuditor instance
+func NewPasswordAuditor(strengthChecker *PasswordStrengthChecker, guessEstimator *GuessEstimator, similarity *PasswordSimilarityEngine) *PasswordAuditor {
+	return &PasswordAuditor{
+		strengthChecker: strengthChecker,
+		guessEstimator:  guessEstimator,
+		patternDetector: entities.NewPasswordPatternDetector(),
+		similarity:      similarity,
+	}
+}
+
+// SetBannedWords adds context-specific banned words to the pattern checks
+func (pa *PasswordAuditor) SetBannedWords(matcher *entities.BannedWordMatcher) *PasswordAuditor {
+	pa.patternDetector.SetBannedWords(matcher)
+	return pa
+}
+
+// Audit analyzes entries and builds a report listing up to top worst
+// offenders. Verdicts come from the same strength checker and guess
+// estimator as check, with an entry's username applied as user context.
+// Passwords in the report are shown as display says.
+func (pa *PasswordAuditor) Audit(entries []AuditEntry, top int, display PasswordDisplay) AuditReport {
+	if top <= 0 {
+		top = DefaultAuditTop
+	}
+
+	strengthCounts := make(map[entities.PasswordStrength]int)
+	scoreCounts := make([]int, 5)
+	patternCounts := make(map[string]int)
+	lengthCounts := make([]int, len(auditLengthBuckets))
+	indexesByPassword := make(map[string][]int)
+	var order []string
+	offenders := make([]AuditOffender, len(entries))
+	findings := make([]AuditFinding, len(entries))
+
+	for i, entry := range entries {
+		ref := AuditRef{Line: entry.Line, Title: entry.Title, URL: entry.URL, Username: entry.Username}
+		password := entities.NewPassword(entry.Password)
+		result := pa.strengthChecker.CheckPasswordStrength(password)
+		userContext := entities.UserContext{Username: entry.Username}
+		contextFindings := userContext.Match(entry.Password)
+		result = pa.strengthChecker.ApplyUserContext(result, contextFindings)
+		var userInputs []string
+		if entry.Username != "" {
+			userInputs = append(userInputs, entry.Username)
+		}
+		estimate := pa.guessEstimator.Estimate(entry.Password, userInputs...)
+
+		strengthCounts[result.Strength]++
+		scoreCounts[estimate.Score]++
+		lengthCounts[lengthBucket(len([]rune(entry.Password)))]++
+
+		if _, seen := indexesByPassword[entry.Password]; !seen {
+			order = append(order, entry.Password)
+		}
+		indexesByPassword[entry.Password] = append(indexesByPassword[entry.Password], i)
+
+		// Count each pattern type once per password
+		var patterns []string
+		seen := make(map[string]bool)
+		for _, pattern := range pa.patternDetector.DetectPatterns(entry.Password) {
+			if !seen[pattern.Type] {
+				seen[pattern.Type] = true
+				patterns = append(patterns, pattern.Type)
+				patternCounts[pattern.Type]++
+			}
+		}
+
+		offenders[i] = AuditOffender{
+			AuditRef:     ref,
+			Password:     display.Show(entry.Password),
+			Strength:     result.Strength.String(),
+			Score:        estimate.Score,
+			GuessesLog10: estimate.GuessesLog10,
+			Patterns:     patterns,
+		}
+
+		findings[i] = AuditFinding{AuditRef: ref, Password: display.Show(entry.Password), Strength: result.Strength.String()}
+		if result.Strength <= entities.Weak || estimate.Score <= 1 {
+			findings[i].Issues = append(findings[i].Issues, AuditIssueWeak)
+		}
+		if len(contextFindings) > 0 {
+			findings[i].Issues = append(findings[i].Issues, AuditIssueUsername)
+		}
+	}
+
+	report := AuditReport{
+		Total:    len(entries),
+		Unique:   len(indexesByPassword),
+		Patterns: sortedCounts(patternCounts),
+	}
+
+	for strength := entities.VeryWeak; strength <= entities.VeryStrong; strength++ {
+		report.Strengths = append(report.Strengths, AuditCount{Label: strength.String(), Count: strengthCounts[strength]})
+	}
+	for score, count := range scoreCounts {
+		report.Scores = append(report.Scores, AuditCount{Label: fmt.Sprintf("%d", score), Count: count})
+	}
+	for i, count := range lengthCounts {
+		report.Lengths = append(report.Lengths, AuditCount{Label: lengthBucketLabel(i), Count: count})
+	}
+
+	for _, password := range order {
+		indexes := indexesByPassword[password]
+		if len(indexes) < 2 {
+			continue
+		}
+		reuse := AuditReuse{Password: display.Show(password), Count: len(indexes)}
+		for _, i := range indexes {
+			reuse.Entries = append(reuse.Entries, offenders[i].AuditRef)
+			findings[i].Issues = append(findings[i].Issues, AuditIssueReused)
+		}
+		report.Reused = append(report.Reused, reuse)
+	}
+	sort.SliceStable(report.Reused, func(i, j int) bool { return report.Reused[i].Count > report.Reused[j].Count })
+
+	report.SimilarSkipped = len(order) > maxSimilarityEntries
+	if !report.SimilarSkipped {
+		for _, group := range pa.similarity.Group(order) {
+			var similar AuditSimilar
+			for _, member := range group {
+				similar.Passwords = append(similar.Passwords, display.Show(order[member]))
+				for _, i := range indexesByPassword[order[member]] {
+					similar.Entries = append(similar.Entries, offenders[i].AuditRef)
+					findings[i].Issues = append(findings[i].Issues, AuditIssueSimilar)
+				}
+			}
+			report.Similar = append(report.Similar, similar)
+		}
+	}
+
+	for _, finding := range findings {
+		if len(finding.Issues) > 0 {
+			report.Findings = append(report.Findings, finding)
+		}
+	}
+
+	sort.SliceStable(offenders, func(i, j int) bool {
+		return offenders[i].GuessesLog10 < offenders[j].GuessesLog10
+	})
+	if len(offenders) > top {
+		offenders = offenders[:top]
+	}
+	report.WorstOffenders = offenders
+
+	return report
+}
+
+// MaskPassword hides a password behind a fixed-width mask, giving away only
+// its length
+func MaskPassword(password string) string {
+	return fmt.Sprintf("******** (%d chars)", utf8.RuneCountInString(password))
+}
+
+// PartialMask hides all but the first and last characters of a password
+func PartialMask(password string) string {
+	runes := []rune(password)
+	if len(runes) < 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[0]) + strings.Repeat("*", len(runes)-2) + string(runes[len(runes)-1])
+}
+
+// lengthBucket returns the histogram bucket for a password length
+func lengthBucket(length int) int {
+	bucket := 0
+	for i, lower := range auditLengthBuckets {
+		if length >= lower {
+			bucket = i
+		}
+	}
+	return bucket
+}
+
+// lengthBucketLabel names a histogram bucket, e.g. "8-11" or "20+"
+func lengthBucketLabel(bucket int) string {
+	lower := auditLengthBuckets[bucket]
+	if bucket == len(auditLengthBuckets)-1 {
+		return fmt.Sprintf("%d+", lower)
+	}
+	return fmt.Sprintf("%d-%d", lower, auditLengthBuckets[bucket+1]-1)
+}
+
+// sortedCounts orders tallies by descending count, then label
+func sortedCounts(counts map[string]int) []AuditCount {
+	result := make([]AuditCount, 0, len(counts))
+	for label, count := range counts {
+		result = append(result, AuditCount{Label: label, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Label < result[j].Label
+	})
+	return result
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+func TestPasswordAuditor_Audit(t *testing.T) {
+	auditor := NewPasswordAuditor(NewPasswordStrengthChecker(), NewGuessEstimator(), NewPasswordSimilarityEngine()).
+		SetBannedWords(entities.NewBannedWordMatcher(0, entities.NewBannedWordList("company", []string{"acme"})))
+
+	entries := []AuditEntry{
+		{Line: 1, Password: "password"},
+		{Line: 2, Password: "kD8#mQ2$vL9!xR4@"},
+		{Line: 3, Password: "password"},
+		{Line: 5, Password: "acme2024"},
+	}
+	report := auditor.Audit(entries, 2, DisplayMasked)
+
+	if report.Total != 4 || report.Unique != 3 {
+		t.Errorf("Total/Unique = %d/%d, want 4/3", report.Total, report.Unique)
+	}
+
+	if len(report.Reused) != 1 || report.Reused[0].Count != 2 || report.Reused[0].Password != "******** (8 chars)" {
+		t.Fatalf("Reused = %+v, want the masked password used twice", report.Reused)
+	}
+	if entries := report.Reused[0].Entries; entries[0].Line != 1 || entries[1].Line != 3 {
+		t.Errorf("Reused entries = %+v, want lines 1 and 3", entries)
+	}
+
+	if len(report.WorstOffenders) != 2 || report.WorstOffenders[0].Line != 1 {
+		t.Errorf("WorstOffenders = %+v, want the 2 weakest starting at line 1", report.WorstOffenders)
+	}
+
+	counts := make(map[string]int)
+	for _, pattern := range report.Patterns {
+		counts[pattern.Label] = pattern.Count
+	}
+	if counts["common_word"] != 2 || counts["banned_word"] != 1 {
+		t.Errorf("Patterns = %+v, want common_word 2 and banned_word 1", report.Patterns)
+	}
+
+	total := 0
+	for _, length := range report.Lengths {
+		total += length.Count
+	}
+	if total != 4 {
+		t.Errorf("Length histogram covers %d passwords, want 4", total)
+	}
+}
+
+func TestPasswordAuditor_VaultEntries(t *testing.T) {
+	auditor := NewPasswordAuditor(NewPasswordStrengthChecker(), NewGuessEstimator(), NewPasswordSimilarityEngine())
+
+	entries := []AuditEntry{
+		{Line: 1, Title: "GitHub", Username: "octocat", Password: "Octocat!2024xyz"},
+		{Line: 2, Title: "Bank", Username: "jdoe", Password: "Tr0ub4dor&Summer23"},
+		{Line: 3, Title: "Mail", Username: "jdoe", Password: "Tr0ub4dor&Summer24"},
+		{Line: 4, Title: "VPN", Username: "jdoe", Password: "kD8#mQ2$vL9!xR4@"},
+	}
+	report := auditor.Audit(entries, 0, DisplayMasked)
+
+	if report.SimilarSkipped {
+		t.Error("SimilarSkipped = true for a small audit")
+	}
+	if len(report.Similar) != 1 || len(report.Similar[0].Entries) != 2 ||
+		report.Similar[0].Entries[0].Title != "Bank" || report.Similar[0].Entries[1].Title != "Mail" {
+		t.Errorf("Similar = %+v, want Bank and Mail", report.Similar)
+	}
+
+	issues := make(map[string][]string)
+	for _, finding := range report.Findings {
+		issues[finding.Label()] = finding.Issues
+	}
+	if !containsString(issues["GitHub"], AuditIssueUsername) {
+		t.Errorf("GitHub issues = %v, want %q", issues["GitHub"], AuditIssueUsername)
+	}
+	if !containsString(issues["Mail"], AuditIssueSimilar) {
+		t.Errorf("Mail issues = %v, want %q", issues["Mail"], AuditIssueSimilar)
+	}
+	if _, ok := issues["VPN"]; ok {
+		t.Errorf("VPN should have no findings, got %v", issues["VPN"])
+	}
+}
+
+func TestPasswordAuditor_Reveal(t *testing.T) {
+	auditor := NewPasswordAuditor(NewPasswordStrengthChecker(), NewGuessEstimator(), NewPasswordSimilarityEngine())
+
+	tests := map[PasswordDisplay]string{DisplayMasked: "******** (7 chars)", DisplayPartial: "h*****2", DisplayFull: "hunter2"}
+	for display, want := range tests {
+		report := auditor.Audit([]AuditEntry{{Line: 1, Password: "hunter2"}}, 0, display)
+		if got := report.WorstOffenders[0].Password; got != want {
+			t.Errorf("Audit(display %d) password = %q, want %q", display, got, want)
+		}
+	}
+}
+
+func TestMaskPassword(t *testing.T) {
+	// Fixed width whatever the password, giving away only the length
+	tests := map[string]string{"password": "******** (8 chars)", "abc": "******** (3 chars)", "pässwörd": "******** (8 chars)"}
+	for input, want := range tests {
+		if got := MaskPassword(input); got != want {
+			t.Errorf("MaskPassword(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestPartialMask(t *testing.T) {
+	tests := map[string]string{"password": "p******d", "abc": "***", "": "", "pässwörd": "p******d"}
+	for input, want := range tests {
+		if got := PartialMask(input); got != want {
+			t.Errorf("PartialMask(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
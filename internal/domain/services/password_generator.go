@@ -40,32 +40,33 @@ func (pg *PasswordGenerator) GeneratePassword(config entities.PasswordConfig) (e
 		return entities.Password{}, err
 	}
 
+	// Work in runes so characters outside ASCII are drawn whole
 	if config.NoRepeat {
-		return pg.generateNoRepeat(config, charset)
+		return pg.generateNoRepeat(config, []rune(charset))
 	}
-	return pg.generateStandard(config, charset)
+	return pg.generateStandard(config, []rune(charset))
 }
 
 // generateStandard samples each position independently with replacement from charset.
 // This is the default path and maximizes raw entropy.
-func (pg *PasswordGenerator) generateStandard(config entities.PasswordConfig, charset string) (entities.Password, error) {
-	passwordBytes := make([]byte, config.Length)
+func (pg *PasswordGenerator) generateStandard(config entities.PasswordConfig, charset []rune) (entities.Password, error) {
+	passwordRunes := make([]rune, config.Length)
 	charsetMax := big.NewInt(int64(len(charset)))
 
-	for i := range passwordBytes {
+	for i := range passwordRunes {
 		num, err := rand.Int(rand.Reader, charsetMax)
 		if err != nil {
-			return entities.Password{}, entities.NewPasswordError("failed to generate random number: " + err.Error())
+			return entities.Password{}, entities.NewPasswordErrorOfKind(entities.KindInternal, "failed to generate random number: "+err.Error())
 		}
-		passwordBytes[i] = charset[num.Int64()]
+		passwordRunes[i] = charset[num.Int64()]
 	}
 
-	return entities.NewPassword(string(passwordBytes)), nil
+	return entities.NewPassword(string(passwordRunes)), nil
 }
 
 // generateNoRepeat produces a password with guaranteed character-type coverage and no
 // duplicate characters, then securely shuffles the result.
-func (pg *PasswordGenerator) generateNoRepeat(config entities.PasswordConfig, charset string) (entities.Password, error) {
+func (pg *PasswordGenerator) generateNoRepeat(config entities.PasswordConfig, charset []rune) (entities.Password, error) {
 	categories, err := pg.charsetManager.BuildCategories(config)
 	if err != nil {
 		return entities.Password{}, err
@@ -78,13 +79,13 @@ func (pg *PasswordGenerator) generateNoRepeat(config entities.PasswordConfig, ch
 			config.Length, len(charset)))
 	}
 
-	result := make([]byte, 0, config.Length)
-	used := make(map[byte]bool, config.Length)
+	result := make([]rune, 0, config.Length)
+	used := make(map[rune]bool, config.Length)
 
 	// 1. Guarantee: pick one character from each enabled category (if length permits)
 	if config.Length >= len(categories) {
 		for _, category := range categories {
-			char, err := pickUniqueChar(category, used)
+			char, err := pickUniqueChar([]rune(category), used)
 			if err != nil {
 				return entities.Password{}, err
 			}
@@ -108,7 +109,7 @@ func (pg *PasswordGenerator) generateNoRepeat(config entities.PasswordConfig, ch
 	// Without this, guaranteed-category characters would always appear at the start,
 	// making the password structure predictable.
 	if err := secureShuffle(result); err != nil {
-		return entities.Password{}, entities.NewPasswordError("failed to shuffle password: " + err.Error())
+		return entities.Password{}, entities.NewPasswordErrorOfKind(entities.KindInternal, "failed to shuffle password: "+err.Error())
 	}
 
 	return entities.NewPassword(string(result)), nil
@@ -149,11 +150,10 @@ func (pg *PasswordGenerator) GenerateMultiplePasswords(config entities.PasswordC
 
 // pickUniqueChar selects a random character from charset that has not been used yet.
 // Uses crypto/rand for cryptographic security.
-func pickUniqueChar(charset string, used map[byte]bool) (byte, error) {
+func pickUniqueChar(charset []rune, used map[rune]bool) (rune, error) {
 	// Collect available (unused) characters
-	available := make([]byte, 0, len(charset))
-	for i := 0; i < len(charset); i++ {
-		c := charset[i]
+	available := make([]rune, 0, len(charset))
+	for _, c := range charset {
 		if !used[c] {
 			available = append(available, c)
 		}
@@ -165,7 +165,7 @@ func pickUniqueChar(charset string, used map[byte]bool) (byte, error) {
 
 	idx, err := rand.Int(rand.Reader, big.NewInt(int64(len(available))))
 	if err != nil {
-		return 0, entities.NewPasswordError("failed to generate random number: " + err.Error())
+		return 0, entities.NewPasswordErrorOfKind(entities.KindInternal, "failed to generate random number: "+err.Error())
 	}
 
 	return available[idx.Int64()], nil
@@ -174,7 +174,7 @@ func pickUniqueChar(charset string, used map[byte]bool) (byte, error) {
 // secureShuffle performs a Fisher-Yates shuffle using crypto/rand.
 // This ensures that guaranteed-category characters are randomly distributed
 // throughout the password rather than clustered at the beginning.
-func secureShuffle(arr []byte) error {
+func secureShuffle(arr []rune) error {
 	for i := len(arr) - 1; i > 0; i-- {
 		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
 		if err != nil {
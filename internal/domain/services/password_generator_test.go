@@ -1,6 +1,7 @@
 package services
 
 import (
+	"math"
 	"strings"
 	"testing"
 
@@ -373,12 +374,8 @@ func TestPasswordAnalyzer_AnalyzePassword(t *testing.T) {
 			}
 
 			// Basic checks that analysis contains expected data
-			if analysis.TimeToCrack == "" {
-				t.Error("TimeToCrack should not be empty")
-			}
-
-			if analysis.SecurityLevel == "" {
-				t.Error("SecurityLevel should not be empty")
+			if len(analysis.CrackTimes) == 0 {
+				t.Error("CrackTimes should not be empty")
 			}
 
 			// Verify that strength is a valid value
@@ -399,3 +396,134 @@ func TestPasswordAnalyzer_AnalyzePassword(t *testing.T) {
 		})
 	}
 }
+
+func TestPasswordAnalyzer_EstimateCrackTimes(t *testing.T) {
+	analyzer := NewPasswordAnalyzer()
+
+	scenarios, err := entities.ParseAttackScenarios([]string{"online-unthrottled", "custom:1000"})
+	if err != nil {
+		t.Fatalf("ParseAttackScenarios() error = %v", err)
+	}
+
+	estimates := analyzer.EstimateCrackTimes(1e6, scenarios)
+	if len(estimates) != 2 {
+		t.Fatalf("EstimateCrackTimes() returned %d estimates, want 2", len(estimates))
+	}
+	if estimates[0].Duration.Seconds != 1e5 {
+		t.Errorf("online-unthrottled seconds = %v, want 1e5", estimates[0].Duration.Seconds)
+	}
+	if estimates[1].Duration.Seconds != 1e3 {
+		t.Errorf("custom seconds = %v, want 1e3", estimates[1].Duration.Seconds)
+	}
+
+	analysis := analyzer.AnalyzePassword(entities.NewPassword("abcdefgh"), entities.PasswordConfig{
+		Length: 8, IncludeLower: true, Count: 1,
+	})
+	if len(analysis.CrackTimes) != len(entities.DefaultAttackScenarios()) {
+		t.Errorf("AnalyzePassword() crack times = %d, want %d", len(analysis.CrackTimes), len(entities.DefaultAttackScenarios()))
+	}
+}
+
+func TestPasswordAnalyzer_RecognizesLeetWords(t *testing.T) {
+	analyzer := NewPasswordAnalyzer()
+	config := entities.PasswordConfig{
+		Length: 11, IncludeLower: true, IncludeUpper: true, IncludeNumbers: true, IncludeSymbols: true, Count: 1,
+	}
+
+	analysis := analyzer.AnalyzePassword(entities.NewPassword("S3cur1ty!23"), config)
+	if len(analysis.RecognizedWords) != 1 || analysis.RecognizedWords[0].MatchedWord != "security" {
+		t.Fatalf("RecognizedWords = %+v, want security", analysis.RecognizedWords)
+	}
+
+	charsetEntropy := 11 * math.Log2(float64(entities.NewCharacterSet().CalculateCharsetSize(config)))
+	if analysis.Entropy >= charsetEntropy-20 {
+		t.Errorf("Entropy = %.1f, want well below charset estimate %.1f", analysis.Entropy, charsetEntropy)
+	}
+}
+
+func TestPasswordAnalyzer_GeneratedPasswordsSkipWords(t *testing.T) {
+	analyzer := NewPasswordAnalyzer()
+	config := entities.PasswordConfig{
+		Length: 12, IncludeLower: true, IncludeNumbers: true, Count: 1,
+	}
+
+	// A random draw that happens to contain "qwer" and reversed "tibbs"
+	analysis := analyzer.AnalyzeGeneratedPassword(entities.NewPassword("qwer7sbbit4x"), config)
+	if len(analysis.RecognizedWords) != 0 {
+		t.Errorf("RecognizedWords = %+v, want none for a generated password", analysis.RecognizedWords)
+	}
+	charsetEntropy := 12 * math.Log2(float64(entities.NewCharacterSet().CalculateCharsetSize(config)))
+	if math.Abs(analysis.Entropy-charsetEntropy) > 1e-9 {
+		t.Errorf("Entropy = %.1f, want charset estimate %.1f", analysis.Entropy, charsetEntropy)
+	}
+}
+
+func TestPasswordGenerator_CharacterClasses(t *testing.T) {
+	generator := NewPasswordGenerator()
+	greek, err := entities.ParseCharacterClass("greek")
+	if err != nil {
+		t.Fatalf("ParseCharacterClass() unexpected error: %v", err)
+	}
+
+	for _, noRepeat := range []bool{false, true} {
+		config := entities.PasswordConfig{
+			Length:       24,
+			ExtraClasses: []entities.CharacterClass{greek},
+			Count:        1,
+			NoRepeat:     noRepeat,
+		}
+		for iter := 0; iter < 50; iter++ {
+			password, err := generator.GeneratePassword(config)
+			if err != nil {
+				t.Fatalf("NoRepeat=%v: unexpected error: %v", noRepeat, err)
+			}
+			if password.Length != 24 {
+				t.Fatalf("NoRepeat=%v: %q has %d characters, want 24", noRepeat, password.Value, password.Length)
+			}
+			seen := make(map[rune]bool)
+			for _, r := range password.Value {
+				if !strings.ContainsRune(greek.Chars, r) {
+					t.Fatalf("NoRepeat=%v: %q contains non-Greek character %q", noRepeat, password.Value, r)
+				}
+				if noRepeat && seen[r] {
+					t.Fatalf("duplicate character %q in %q", r, password.Value)
+				}
+				seen[r] = true
+			}
+		}
+	}
+
+	// Coverage guarantees extend to each extra class
+	config := entities.PasswordConfig{Length: 4, IncludeLower: true, ExtraClasses: []entities.CharacterClass{greek}, Count: 1, NoRepeat: true}
+	for iter := 0; iter < 50; iter++ {
+		password, _ := generator.GeneratePassword(config)
+		if !strings.ContainsAny(password.Value, greek.Chars) {
+			t.Fatalf("%q has no Greek character", password.Value)
+		}
+	}
+
+	// The no-repeat limit counts characters, not bytes
+	config = entities.PasswordConfig{Length: 50, ExtraClasses: []entities.CharacterClass{greek}, Count: 1, NoRepeat: true}
+	if _, err := generator.GeneratePassword(config); err == nil {
+		t.Error("expected error for length beyond the 49 Greek letters")
+	}
+}
+
+func TestPasswordAnalyzer_FactorsAddUpToEntropy(t *testing.T) {
+	analyzer := NewPasswordAnalyzer()
+
+	for _, value := range []string{"xK9#mQ2$vL7@", "P@ssw0rd-Sunshine-42"} {
+		password := entities.NewPassword(value)
+		analysis := analyzer.AnalyzePassword(password, password.ObservedConfig())
+		total := 0.0
+		for _, factor := range analysis.Factors {
+			total += factor.Contribution
+		}
+		if math.Abs(total-analysis.Entropy) > 1e-9 {
+			t.Errorf("%q: factors add up to %.2f bits, entropy is %.2f", value, total, analysis.Entropy)
+		}
+		if len(analysis.RecognizedWords) > 0 && len(analysis.Factors) < 2 {
+			t.Errorf("%q: recognized words but no dictionary factor: %+v", value, analysis.Factors)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// DefaultSimilarityThreshold is the score at which two passwords count as
+// near-duplicates
+const DefaultSimilarityThreshold = 0.75
+
+// Weights of the similarity components. The normalized stem dominates because
+// rotation schemes keep the stem and change the decoration around it.
+const (
+	stemWeight  = 0.6
+	rawWeight   = 0.2
+	tokenWeight = 0.2
+)
+
+// Token lengths below which letter and digit runs are ignored
+const (
+	minLetterToken = 3
+	minDigitToken  = 2
+)
+
+// SimilarityResult explains how alike two passwords are
+type SimilarityResult struct {
+	StemA, StemB    string   // passwords after normalization
+	StemDistance    int      // edit distance between the stems
+	RawDistance     int      // edit distance between the case-folded passwords
+	SharedTokens    []string // letter and digit runs both passwords contain
+	StemSimilarity  float64  // 0-1, from StemDistance
+	RawSimilarity   float64  // 0-1, from RawDistance
+	TokenSimilarity float64  // Jaccard index of the token sets
+	Score           float64  // weighted combination, 0-1
+	Similar         bool     // Score reaches the engine's threshold
+	Reasons         []string
+}
+
+// PasswordSimilarityEngine compares passwords after normalization to catch
+// reuse that exact matching misses, such as Summer2024! and Summer2025!
+type PasswordSimilarityEngine struct {
+	threshold float64
+}
+
+// NewPasswordSimilarityEngine creates an engine using DefaultSimilarityThreshold
+func NewPasswordSimilarityEngine() *PasswordSimilarityEngine {
+	return &PasswordSimilarityEngine{threshold: DefaultSimilarityThreshold}
+}
+
+// SetThreshold changes the score at which passwords count as similar
+func (pse *PasswordSimilarityEngine) SetThreshold(threshold float64) *PasswordSimilarityEngine {
+	pse.threshold = threshold
+	return pse
+}
+
+// Normalize reduces a password to its stem: case folded, trailing digits and
+// symbols stripped, then l33t substitutions undone. A password that is all
+// digits and symbols is only case folded.
+func (pse *PasswordSimilarityEngine) Normalize(password string) string {
+	lower := strings.ToLower(password)
+	stem := strings.TrimRightFunc(lower, func(r rune) bool { return !unicode.IsLetter(r) })
+	if stem == "" {
+		return lower
+	}
+	return entities.NormalizeLeet(stem)[0]
+}
+
+// Compare scores the similarity of two passwords
+func (pse *PasswordSimilarityEngine) Compare(a, b string) SimilarityResult {
+	result := SimilarityResult{StemA: pse.Normalize(a), StemB: pse.Normalize(b)}
+
+	result.StemDistance = entities.EditDistance(result.StemA, result.StemB)
+	result.StemSimilarity = similarityFromDistance(result.StemDistance, result.StemA, result.StemB)
+
+	lowerA, lowerB := strings.ToLower(a), strings.ToLower(b)
+	result.RawDistance = entities.EditDistance(lowerA, lowerB)
+	result.RawSimilarity = similarityFromDistance(result.RawDistance, lowerA, lowerB)
+
+	tokensA, tokensB := pse.tokens(a), pse.tokens(b)
+	union := make(map[string]bool, len(tokensA)+len(tokensB))
+	for token := range tokensA {
+		union[token] = true
+		if tokensB[token] {
+			result.SharedTokens = append(result.SharedTokens, token)
+		}
+	}
+	for token := range tokensB {
+		union[token] = true
+	}
+	sort.Strings(result.SharedTokens)
+	if len(union) > 0 {
+		result.TokenSimilarity = float64(len(result.SharedTokens)) / float64(len(union))
+	}
+
+	result.Score = stemWeight*result.StemSimilarity + rawWeight*result.RawSimilarity + tokenWeight*result.TokenSimilarity
+	result.Similar = result.Score >= pse.threshold
+
+	switch {
+	case a == b:
+		result.Reasons = append(result.Reasons, "Passwords are identical")
+	case result.StemDistance == 0:
+		result.Reasons = append(result.Reasons, fmt.Sprintf("Same stem '%s' once case, l33t and trailing digits/symbols are ignored", result.StemA))
+	case result.StemSimilarity >= pse.threshold:
+		result.Reasons = append(result.Reasons, fmt.Sprintf("Stems differ by only %d edit(s)", result.StemDistance))
+	}
+	if len(result.SharedTokens) > 0 && a != b {
+		result.Reasons = append(result.Reasons, "Shares "+strings.Join(result.SharedTokens, ", "))
+	}
+
+	return result
+}
+
+// Group clusters passwords into near-duplicate groups, returning the indexes
+// of each group with at least two members. Identical passwords are grouped
+// too, so callers wanting only near-reuse should deduplicate first.
+func (pse *PasswordSimilarityEngine) Group(passwords []string) [][]int {
+	parent := make([]int, len(passwords))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	stems := make([]string, len(passwords))
+	for i, password := range passwords {
+		stems[i] = pse.Normalize(password)
+	}
+
+	for i := range passwords {
+		for j := i + 1; j < len(passwords); j++ {
+			if find(i) == find(j) || !pse.plausiblySimilar(stems[i], stems[j]) {
+				continue
+			}
+			if pse.Compare(passwords[i], passwords[j]).Similar {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	var roots []int
+	for i := range passwords {
+		root := find(i)
+		if _, ok := members[root]; !ok {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], i)
+	}
+
+	var groups [][]int
+	for _, root := range roots {
+		if len(members[root]) > 1 {
+			groups = append(groups, members[root])
+		}
+	}
+	return groups
+}
+
+// plausiblySimilar cheaply rules out pairs whose stems differ too much in
+// length to ever reach the threshold
+func (pse *PasswordSimilarityEngine) plausiblySimilar(stemA, stemB string) bool {
+	lenA, lenB := len([]rune(stemA)), len([]rune(stemB))
+	longer, diff := lenA, lenA-lenB
+	if lenB > longer {
+		longer = lenB
+	}
+	if diff < 0 {
+		diff = -diff
+	}
+	if longer == 0 {
+		return true
+	}
+	// Even with perfect raw and token similarity, the stem must carry the rest
+	best := stemWeight*(1-float64(diff)/float64(longer)) + rawWeight + tokenWeight
+	return best >= pse.threshold
+}
+
+// tokens returns the letter runs of the normalized password and the digit
+// runs of the original, ignoring runs too short to be meaningful
+func (pse *PasswordSimilarityEngine) tokens(password string) map[string]bool {
+	tokens := make(map[string]bool)
+
+	letters := strings.FieldsFunc(pse.Normalize(password), func(r rune) bool { return !unicode.IsLetter(r) })
+	for _, run := range letters {
+		if len([]rune(run)) >= minLetterToken {
+			tokens[run] = true
+		}
+	}
+
+	digits := strings.FieldsFunc(password, func(r rune) bool { return !unicode.IsDigit(r) })
+	for _, run := range digits {
+		if len(run) >= minDigitToken {
+			tokens[run] = true
+		}
+	}
+
+	return tokens
+}
+
+// similarityFromDistance converts an edit distance into a 0-1 similarity
+// relative to the longer string
+func similarityFromDistance(distance int, a, b string) float64 {
+	longer := len([]rune(a))
+	if n := len([]rune(b)); n > longer {
+		longer = n
+	}
+	if longer == 0 {
+		return 1
+	}
+	return 1 - float64(distance)/float64(longer)
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPasswordSimilarityEngine_Normalize(t *testing.T) {
+	engine := NewPasswordSimilarityEngine()
+
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{"Summer2024!", "summer"},
+		{"P@ssw0rd1", "password"},
+		{"Password!", "password"},
+		{"123456", "123456"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := engine.Normalize(tt.password); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestPasswordSimilarityEngine_Compare(t *testing.T) {
+	engine := NewPasswordSimilarityEngine()
+
+	tests := []struct {
+		a, b        string
+		wantSimilar bool
+	}{
+		{"Summer2024!", "Summer2025!", true},
+		{"P@ssw0rd1", "Password!", true},
+		{"Welcome1", "Welcome12", true},
+		{"Summer2024!", "Winter2024!", false},
+		{"kD8#mQ2$vL9!", "correcthorse", false},
+	}
+
+	for _, tt := range tests {
+		result := engine.Compare(tt.a, tt.b)
+		if result.Similar != tt.wantSimilar {
+			t.Errorf("Compare(%q, %q).Similar = %v (score %.2f), want %v", tt.a, tt.b, result.Similar, result.Score, tt.wantSimilar)
+		}
+		if result.Score < 0 || result.Score > 1 {
+			t.Errorf("Compare(%q, %q).Score = %v, want within [0, 1]", tt.a, tt.b, result.Score)
+		}
+	}
+
+	result := engine.Compare("Summer2024!", "Summer2025!")
+	if result.StemDistance != 0 || len(result.Reasons) == 0 {
+		t.Errorf("Compare = %+v, want identical stems with a reason", result)
+	}
+	if !reflect.DeepEqual(result.SharedTokens, []string{"summer"}) {
+		t.Errorf("SharedTokens = %v, want [summer]", result.SharedTokens)
+	}
+
+	if strict := NewPasswordSimilarityEngine().SetThreshold(0.99); strict.Compare("Summer2024!", "Summer2025!").Similar {
+		t.Error("Compare with threshold 0.99 reported rotated passwords as similar")
+	}
+}
+
+func TestPasswordSimilarityEngine_Group(t *testing.T) {
+	engine := NewPasswordSimilarityEngine()
+
+	passwords := []string{"Summer2024!", "kD8#mQ2$vL9!", "Summer2025!", "P@ssw0rd1", "Password!", "summer2026"}
+	want := [][]int{{0, 2, 5}, {3, 4}}
+
+	if got := engine.Group(passwords); !reflect.DeepEqual(got, want) {
+		t.Errorf("Group = %v, want %v", got, want)
+	}
+	if got := engine.Group([]string{"only"}); got != nil {
+		t.Errorf("Group of one = %v, want nil", got)
+	}
+}
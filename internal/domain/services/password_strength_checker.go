@@ -2,24 +2,43 @@ package services
 
 import (
 	"fmt"
+	"strings"
+	"unicode"
 
 	"github.com/kumarasakti/passgen/internal/domain/entities"
 )
 
 // StrengthCheckResult represents the result of password strength checking
 type StrengthCheckResult struct {
-	Password          entities.Password
-	Score             int
-	MaxScore          int
-	Strength          entities.PasswordStrength
-	StrengthEmoji     string
-	Celebration       string
-	SarcasticComments []string
-	Feedback          []string
-	FormattedResult   string
+	Password        entities.Password
+	Score           int
+	MaxScore        int
+	Strength        entities.PasswordStrength
+	Breaches        []entities.BreachResult
+	BannedWords     []entities.BannedWordMatch
+	ContextFindings []entities.UserContextFinding
+	Confusables     entities.ConfusableReport
+	Factors         []ScoringFactor // add up to Score
 }
 
-// PasswordStrengthChecker provides sarcastic password strength checking
+// Breached reports whether any breach corpus contained the password
+func (r StrengthCheckResult) Breached() bool {
+	for _, breach := range r.Breaches {
+		if breach.Found {
+			return true
+		}
+	}
+	return false
+}
+
+// Rejected reports whether the password must not be used at all, because it
+// was breached or contains a banned word
+func (r StrengthCheckResult) Rejected() bool {
+	return r.Breached() || len(r.BannedWords) > 0
+}
+
+// PasswordStrengthChecker scores passwords against a checklist of length and
+// character variety
 type PasswordStrengthChecker struct{}
 
 // NewPasswordStrengthChecker creates a new PasswordStrengthChecker instance
@@ -27,131 +46,207 @@ func NewPasswordStrengthChecker() *PasswordStrengthChecker {
 	return &PasswordStrengthChecker{}
 }
 
-// CheckPasswordStrength analyzes password strength with sarcastic feedback
+// characterClassCheck is one character-variety item of the checklist
+type characterClassCheck struct {
+	name    string
+	points  int
+	matches func(r rune) bool
+	found   string // describes a matching character
+}
+
+// characterClassChecks lists the variety checks in checklist order. Letters
+// from scripts without case stand in for lowercase.
+var characterClassChecks = []characterClassCheck{
+	{FactorLowercase, 1, func(r rune) bool { return unicode.IsLetter(r) && !unicode.IsUpper(r) && !unicode.IsTitle(r) },
+		"a lowercase or uncased letter"},
+	{FactorUppercase, 1, func(r rune) bool { return unicode.IsUpper(r) || unicode.IsTitle(r) },
+		"an uppercase letter"},
+	{FactorNumbers, 1, unicode.IsNumber,
+		"a number"},
+	{FactorSymbols, 2, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) },
+		"a symbol"},
+}
+
+// CheckPasswordStrength scores a password against the checklist, recording
+// each point earned or missed as a factor
 func (psc *PasswordStrengthChecker) CheckPasswordStrength(password entities.Password) StrengthCheckResult {
 	score := 0
 	maxScore := 8
-	feedback := []string{}
-	sarcasticComments := []string{}
+	var factors []ScoringFactor
+	start, end := wholePassword(password.Length)
 
 	// Length check
 	if password.Length >= 12 {
 		score += 2
-		if password.Length >= 16 {
-			sarcasticComments = append(sarcasticComments, "Wow, someone actually read the security guidelines! 👏")
-		}
+		factors = append(factors, ScoringFactor{FactorLength, 2, start, end,
+			fmt.Sprintf("%d characters; 12 or more earn 2 points", password.Length)})
 	} else if password.Length >= 8 {
 		score += 1
-		sarcasticComments = append(sarcasticComments, "8 characters? How... minimalistic of you 🤔")
+		factors = append(factors, ScoringFactor{FactorLength, 1, start, end,
+			fmt.Sprintf("%d characters; 8 to 11 earn 1 point, 12 would earn 2", password.Length)})
 	} else {
-		feedback = append(feedback, "Password should be at least 8 characters long")
-		sarcasticComments = append(sarcasticComments, "Really? That's shorter than most people's names! 😅")
+		factors = append(factors, ScoringFactor{FactorLength, 0, start, end,
+			fmt.Sprintf("Only %d characters; 8 are needed for a point", password.Length)})
 	}
 
-	// Character variety checks
-	if password.HasLowercase() {
-		score += 1
-	} else {
-		feedback = append(feedback, "Add lowercase letters")
-		sarcasticComments = append(sarcasticComments, "No lowercase letters? Are we SHOUTING all the time? 📢")
+	// Character variety checks; the first matching character is the evidence
+	runes := []rune(password.Value)
+	for _, check := range characterClassChecks {
+		position := -1
+		for i, r := range runes {
+			if check.matches(r) {
+				position = i
+				break
+			}
+		}
+		if position < 0 {
+			factors = append(factors, unspannedFactor(check.name, 0, "None present"))
+			continue
+		}
+		score += check.points
+		factors = append(factors, ScoringFactor{check.name, float64(check.points), position, position,
+			fmt.Sprintf("'%c' is %s", runes[position], check.found)})
 	}
 
-	if password.HasUppercase() {
+	// Bonus for length
+	if password.Length >= 16 {
 		score += 1
+		factors = append(factors, ScoringFactor{FactorLengthBonus, 1, start, end, "16 or more characters earn a bonus point"})
 	} else {
-		feedback = append(feedback, "Add uppercase letters")
-		sarcasticComments = append(sarcasticComments, "No capitals? I guess we're going for the e.e. cummings aesthetic 🎭")
+		factors = append(factors, unspannedFactor(FactorLengthBonus, 0, "16 or more characters would earn a bonus point"))
 	}
 
-	if password.HasNumbers() {
-		score += 1
-	} else {
-		feedback = append(feedback, "Add numbers")
-		sarcasticComments = append(sarcasticComments, "Numbers are optional now? Math teachers everywhere are crying 😢")
-	}
+	// Lookalike characters don't weaken a password but do lock people out
+	confusables := entities.DetectConfusables(password.Value)
+	factors = append(factors, confusableFactors(confusables, password.Length)...)
 
-	if password.HasSymbols() {
-		score += 2
-	} else {
-		feedback = append(feedback, "Add special characters")
-		sarcasticComments = append(sarcasticComments, "No symbols? Your password is as plain as unseasoned chicken 🐔")
+	return StrengthCheckResult{
+		Password:    password,
+		Score:       score,
+		MaxScore:    maxScore,
+		Strength:    strengthFromScore(score),
+		Factors:     factors,
+		Confusables: confusables,
 	}
+}
 
-	// Bonus for length
-	if password.Length >= 16 {
-		score += 1
+// ApplyBreachResults records breach lookups on a result. A password found in
+// any breach corpus fails regardless of its character-class score, because
+// attackers try breached passwords first.
+func (psc *PasswordStrengthChecker) ApplyBreachResults(result StrengthCheckResult, breaches []entities.BreachResult) StrengthCheckResult {
+	result.Breaches = append(result.Breaches, breaches...)
+	if !result.Breached() {
+		return result
 	}
 
-	// Determine strength and celebration
-	strength, strengthEmoji, celebration := psc.determineStrengthFromScore(score)
+	var total int64
+	for _, breach := range result.Breaches {
+		total += breach.Count
+	}
 
-	// Format the result
-	formattedResult := psc.formatResult(password, score, maxScore, strength, strengthEmoji, celebration, sarcasticComments, feedback)
+	start, end := wholePassword(result.Password.Length)
+	factor := ScoringFactor{Name: FactorBreached, Start: start, End: end,
+		Rationale: fmt.Sprintf("Appears %d times in known data breaches", total)}
+	return psc.reject(result, factor)
+}
 
-	return StrengthCheckResult{
-		Password:          password,
-		Score:             score,
-		MaxScore:          maxScore,
-		Strength:          strength,
-		StrengthEmoji:     strengthEmoji,
-		Celebration:       celebration,
-		SarcasticComments: sarcasticComments,
-		Feedback:          feedback,
-		FormattedResult:   formattedResult,
+// ApplyBannedWords records banned word matches on a result. Context-specific
+// words are the first thing a targeted attacker tries, so any match fails the
+// password.
+func (psc *PasswordStrengthChecker) ApplyBannedWords(result StrengthCheckResult, matches []entities.BannedWordMatch) StrengthCheckResult {
+	if len(matches) == 0 {
+		return result
 	}
+	result.BannedWords = append(result.BannedWords, matches...)
+
+	var factors []ScoringFactor
+	for _, match := range matches {
+		how := ""
+		switch {
+		case match.Distance > 0:
+			how = " (misspelled)"
+		case match.Leet:
+			how = " (l33t spelling)"
+		}
+		factors = append(factors, ScoringFactor{Name: FactorBannedWord, Start: match.Start, End: match.End,
+			Rationale: fmt.Sprintf("'%s' is the banned %s word '%s'%s", match.Token, match.List, match.Word, how)})
+	}
+	return psc.reject(result, factors...)
 }
 
-// determineStrengthFromScore determines strength based on score
-func (psc *PasswordStrengthChecker) determineStrengthFromScore(score int) (entities.PasswordStrength, string, string) {
-	var strength entities.PasswordStrength
-	var strengthEmoji, celebration string
+// contextPenalties is the score deducted per user-context finding severity
+var contextPenalties = map[string]int{"high": 3, "medium": 2, "low": 1}
 
-	switch {
-	case score >= 7:
-		strength = entities.VeryStrong
-		strengthEmoji = "🔥"
-		celebration = "Impressive! Your password could probably withstand a zombie apocalypse! 🧟‍♂️"
-	case score >= 5:
-		strength = entities.Strong
-		strengthEmoji = "💪"
-		celebration = "Not bad! Your password has some real backbone! 🦴"
-	case score >= 3:
-		strength = entities.Medium
-		strengthEmoji = "😐"
-		celebration = "It's... adequate. Like a participation trophy for password security 🏆"
-	case score >= 1:
-		strength = entities.Weak
-		strengthEmoji = "😰"
-		celebration = "Yikes! This password couldn't protect a diary from a nosy sibling! 📖"
-	default:
-		strength = entities.VeryWeak
-		strengthEmoji = "🚨"
-		celebration = "Oh dear... this password is weaker than my WiFi signal in the basement! 📶"
+// ApplyUserContext records resemblances to the account holder's details on a
+// result, deducting from the score by severity
+func (psc *PasswordStrengthChecker) ApplyUserContext(result StrengthCheckResult, findings []entities.UserContextFinding) StrengthCheckResult {
+	if len(findings) == 0 {
+		return result
 	}
+	result.ContextFindings = append(result.ContextFindings, findings...)
 
-	return strength, strengthEmoji, celebration
-}
+	for _, finding := range findings {
+		// The score bottoms out at zero, so a deduction takes at most what is left
+		deduction := contextPenalties[finding.Severity]
+		if deduction > result.Score {
+			deduction = result.Score
+		}
+		result.Score -= deduction
 
-// formatResult formats the strength check result into a string
-func (psc *PasswordStrengthChecker) formatResult(password entities.Password, score, maxScore int, strength entities.PasswordStrength, strengthEmoji, celebration string, sarcasticComments, feedback []string) string {
-	result := "🔍 Password Analysis Results:\n"
-	result += fmt.Sprintf("Strength: %s %s (Score: %d/%d)\n", strength.String(), strengthEmoji, score, maxScore)
-	result += fmt.Sprintf("\n%s\n", celebration)
+		start, end := runeSpanOf(result.Password.Value, finding.Token)
+		result.Factors = append(result.Factors, ScoringFactor{Name: FactorUserContext, Contribution: -float64(deduction),
+			Start: start, End: end, Rationale: fmt.Sprintf("[%s] %s", finding.Severity, finding.Description())})
+	}
 
-	if len(sarcasticComments) > 0 {
-		result += "\n💭 Honest Feedback:\n"
-		for _, comment := range sarcasticComments {
-			result += fmt.Sprintf("• %s\n", comment)
-		}
+	// Breached or banned passwords stay rejected whatever their score
+	if !result.Rejected() {
+		result.Strength = strengthFromScore(result.Score)
 	}
 
-	if len(feedback) > 0 {
-		result += "\n💡 Actionable Suggestions:\n"
-		for _, suggestion := range feedback {
-			result += fmt.Sprintf("• %s\n", suggestion)
-		}
-		result += "\nPro tip: Try 'passgen --secure -l 16' for a password that actually means business! 🚀"
+	return result
+}
+
+// reject fails a result outright. The first factor takes away whatever score
+// was left.
+func (psc *PasswordStrengthChecker) reject(result StrengthCheckResult, factors ...ScoringFactor) StrengthCheckResult {
+	if len(factors) > 0 {
+		factors[0].Contribution = -float64(result.Score)
 	}
+	result.Factors = append(result.Factors, factors...)
+	result.Score = 0
+	result.Strength = entities.VeryWeak
 
 	return result
 }
+
+// runeSpanOf locates token in value ignoring case, returning rune indices of
+// its first and last characters, or -1, -1 when it doesn't appear
+func runeSpanOf(value, token string) (int, int) {
+	haystack := []rune(strings.ToLower(value))
+	needle := []rune(strings.ToLower(token))
+	if len(needle) == 0 {
+		return -1, -1
+	}
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if string(haystack[i:i+len(needle)]) == string(needle) {
+			return i, i + len(needle) - 1
+		}
+	}
+	return -1, -1
+}
+
+// strengthFromScore rates a checklist score
+func strengthFromScore(score int) entities.PasswordStrength {
+	switch {
+	case score >= 7:
+		return entities.VeryStrong
+	case score >= 5:
+		return entities.Strong
+	case score >= 3:
+		return entities.Medium
+	case score >= 1:
+		return entities.Weak
+	default:
+		return entities.VeryWeak
+	}
+}
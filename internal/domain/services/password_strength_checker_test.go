@@ -0,0 +1,163 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+func TestPasswordStrengthChecker_ApplyBreachResults(t *testing.T) {
+	checker := NewPasswordStrengthChecker()
+	result := checker.CheckPasswordStrength(entities.NewPassword("Correct-Horse-Battery-9"))
+
+	if result.Strength < entities.Strong {
+		t.Fatalf("Precondition: strength = %v, want at least Strong", result.Strength)
+	}
+
+	clean := checker.ApplyBreachResults(result, []entities.BreachResult{{Source: "test", Found: false}})
+	if clean.Breached() || clean.Strength != result.Strength {
+		t.Errorf("Unbreached lookup should not change the verdict, got %v", clean.Strength)
+	}
+
+	breached := checker.ApplyBreachResults(result, []entities.BreachResult{{Source: "test", Found: true, Count: 42}})
+	if !breached.Breached() {
+		t.Error("Breached() = false, want true")
+	}
+	if breached.Strength != entities.VeryWeak || breached.Score != 0 {
+		t.Errorf("Breached password strength = %v (score %d), want Very Weak (score 0)", breached.Strength, breached.Score)
+	}
+	if factor := breached.Factors[len(breached.Factors)-1]; factor.Name != FactorBreached || factor.Rationale != "Appears 42 times in known data breaches" {
+		t.Errorf("last factor = %+v, want the breach", factor)
+	}
+}
+
+func TestPasswordStrengthChecker_ApplyBannedWords(t *testing.T) {
+	checker := NewPasswordStrengthChecker()
+	matcher := entities.NewBannedWordMatcher(1, entities.NewBannedWordList("company", []string{"acme"}))
+
+	result := checker.CheckPasswordStrength(entities.NewPassword("Acm3-Rocks-2024!"))
+	result = checker.ApplyBannedWords(result, matcher.Match(result.Password.Value))
+
+	if !result.Rejected() || result.Breached() {
+		t.Errorf("Rejected() = %v, Breached() = %v, want rejected without breach", result.Rejected(), result.Breached())
+	}
+	if result.Strength != entities.VeryWeak || len(result.BannedWords) != 1 {
+		t.Errorf("Banned password = %v with %d matches, want Very Weak with 1 match", result.Strength, len(result.BannedWords))
+	}
+}
+
+func TestPasswordStrengthChecker_ApplyUserContext(t *testing.T) {
+	checker := NewPasswordStrengthChecker()
+	context := entities.UserContext{Username: "alice", Organization: "Globex"}
+
+	result := checker.CheckPasswordStrength(entities.NewPassword("Alice-Globex-2024!"))
+	before := result.Score
+	result = checker.ApplyUserContext(result, context.Match(result.Password.Value))
+
+	if len(result.ContextFindings) != 2 {
+		t.Fatalf("ContextFindings = %+v, want 2 findings", result.ContextFindings)
+	}
+	// high (3) + medium (2)
+	if want := before - 5; result.Score != want {
+		t.Errorf("Score = %d, want %d", result.Score, want)
+	}
+	if result.Rejected() {
+		t.Error("User-context findings lower the score but should not reject outright")
+	}
+}
+
+func TestPasswordStrengthChecker_WarnsAboutConfusables(t *testing.T) {
+	checker := NewPasswordStrengthChecker()
+
+	result := checker.CheckPasswordStrength(entities.NewPassword("Pаssword-2024!"))
+	if !result.Confusables.MixedScript {
+		t.Error("expected the Cyrillic а to make the password mixed-script")
+	}
+	var mixed, lookalike bool
+	for _, factor := range result.Factors {
+		mixed = mixed || factor.Name == FactorMixedScript && strings.Contains(factor.Rationale, "Latin and Cyrillic")
+		lookalike = lookalike || factor.Name == FactorConfusable && factor.Rationale == "'а' (Cyrillic) looks like 'a'"
+	}
+	if !mixed || !lookalike {
+		t.Errorf("Factors = %+v, want mixed-script and lookalike factors", result.Factors)
+	}
+
+	result = checker.CheckPasswordStrength(entities.NewPassword("Password-2024!"))
+	if result.Confusables.HasFindings() {
+		t.Errorf("ASCII password reported confusables: %+v", result.Confusables)
+	}
+}
+
+// sumContributions adds up the contributions of factors
+func sumContributions(factors []ScoringFactor) float64 {
+	total := 0.0
+	for _, factor := range factors {
+		total += factor.Contribution
+	}
+	return total
+}
+
+func TestPasswordStrengthChecker_FactorsAddUpToScore(t *testing.T) {
+	checker := NewPasswordStrengthChecker()
+	context := entities.UserContext{Username: "alice"}
+	matcher := entities.NewBannedWordMatcher(0, entities.NewBannedWordList("company", []string{"globex"}))
+
+	tests := []struct {
+		name  string
+		apply func(StrengthCheckResult) StrengthCheckResult
+	}{
+		{"checklist only", func(r StrengthCheckResult) StrengthCheckResult { return r }},
+		{"user context", func(r StrengthCheckResult) StrengthCheckResult {
+			return checker.ApplyUserContext(r, context.Match(r.Password.Value))
+		}},
+		{"banned word", func(r StrengthCheckResult) StrengthCheckResult {
+			return checker.ApplyBannedWords(r, matcher.Match(r.Password.Value))
+		}},
+		{"breach", func(r StrengthCheckResult) StrengthCheckResult {
+			return checker.ApplyBreachResults(r, []entities.BreachResult{{Source: "test", Found: true, Count: 3}})
+		}},
+	}
+
+	for _, password := range []string{"alice", "Alice-Globex-2024!", "Globex1"} {
+		for _, tt := range tests {
+			t.Run(password+"/"+tt.name, func(t *testing.T) {
+				result := tt.apply(checker.CheckPasswordStrength(entities.NewPassword(password)))
+				if got := sumContributions(result.Factors); got != float64(result.Score) {
+					t.Errorf("factors add up to %v, score is %d: %+v", got, result.Score, result.Factors)
+				}
+			})
+		}
+	}
+}
+
+func TestPasswordStrengthChecker_FactorEvidence(t *testing.T) {
+	checker := NewPasswordStrengthChecker()
+	result := checker.CheckPasswordStrength(entities.NewPassword("abc-DEF"))
+	result = checker.ApplyUserContext(result, entities.UserContext{Username: "def"}.Match("abc-DEF"))
+
+	want := map[string][2]int{
+		FactorLength:      {0, 6},
+		FactorLowercase:   {0, 0},
+		FactorUppercase:   {4, 4},
+		FactorNumbers:     {-1, -1},
+		FactorSymbols:     {3, 3},
+		FactorUserContext: {4, 6},
+	}
+	for _, factor := range result.Factors {
+		span, ok := want[factor.Name]
+		if !ok {
+			continue
+		}
+		if factor.Start != span[0] || factor.End != span[1] {
+			t.Errorf("%s evidence = %d-%d, want %d-%d", factor.Name, factor.Start, factor.End, span[0], span[1])
+		}
+		if factor.Rationale == "" {
+			t.Errorf("%s has no rationale", factor.Name)
+		}
+		delete(want, factor.Name)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing factors: %v", want)
+	}
+}
@@ -0,0 +1,918 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PCFGModelVersion is the format version of serialized models
+const PCFGModelVersion = 1
+
+// Segment classes of a password structure such as L6D2S1
+const (
+	classLetter = 'L'
+	classDigit  = 'D'
+	classSymbol = 'S'
+)
+
+// Capitalization patterns of letter runs
+const (
+	caseLower       = "lower"
+	caseCapitalized = "capitalized"
+	caseUpper       = "upper"
+	caseMixed       = "mixed"
+)
+
+const (
+	// markovOrder is the n-gram size of the letter model: each letter is
+	// conditioned on the two before it
+	markovOrder = 3
+	// markovStart pads the context at the start of a letter run
+	markovStart = '^'
+	// maxSegmentLength bounds the run lengths the structure back-off generates
+	maxSegmentLength = 32
+	// pcfgSamples is how many passwords are drawn from a model to turn
+	// probabilities into guess numbers
+	pcfgSamples = 10000
+	// pcfgSeed makes the drawn sample, and so every estimate, reproducible
+	pcfgSeed = 1
+	// pcfgHeadCount is how many times the most popular embedded password
+	// counts. Past this rank the list is the long tail, where every password
+	// is about as rare as the ones it leaves out.
+	pcfgHeadCount = 1000
+)
+
+// symbolAlphabet is the printable ASCII symbols, the search space for symbol runs
+const symbolAlphabet = " !\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// leetLetters maps the substitutions crackers' mangling rules undo
+var leetLetters = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '8': 'b',
+	'@': 'a', '$': 's', '!': 'i', '+': 't',
+}
+
+// PCFGModel estimates how many guesses a probabilistic cracker needs for a
+// password. The cracker guesses structures such as L6D2S1 (six letters, two
+// digits, a symbol) in order of probability and fills each slot with the
+// most likely runs by how often they were seen. Letter runs never seen back
+// off to a character-level Markov model. The model holds only these
+// aggregate counts, never whole passwords.
+type PCFGModel struct {
+	Version        int            `json:"version"`
+	Passwords      int            `json:"passwords"`      // passwords trained on
+	Structures     map[string]int `json:"structures"`     // e.g. "L6D2" -> count
+	Transitions    map[string]int `json:"transitions"`    // class pairs within structures, ^ and $ marking the ends
+	Lengths        map[string]int `json:"lengths"`        // run lengths by class, e.g. "D2"
+	Digits         map[string]int `json:"digits"`         // digit runs
+	Symbols        map[string]int `json:"symbols"`        // symbol runs
+	Capitalization map[string]int `json:"capitalization"` // letter runs by case pattern
+	Words          map[string]int `json:"words"`          // lowercase letter runs
+	Letters        map[string]int `json:"letters"`        // lowercase letter n-grams of every order up to markovOrder
+
+	prepareOnce sync.Once
+	derived     pcfgDerived
+}
+
+// pcfgDerived holds the totals and sample computed from a model's counts
+type pcfgDerived struct {
+	structureTotal, structureTypes int
+	transitionTotals               map[rune]int
+	lengthTotals                   map[rune]int
+	digitTotals, digitTypes        map[int]int // by run length
+	symbolTotals, symbolTypes      map[int]int
+	wordTotals, wordTypes          map[int]int
+	caseTotal                      int
+	contextTotals, contextTypes    map[string]int
+	alphabet                       []rune
+
+	structures             weightedStrings
+	digits, symbols, words map[int]weightedStrings // runs by length
+	// sampleLog2 holds the log2 probabilities of the drawn passwords, most
+	// probable first, and sampleGuesses[i] the guesses that reach sample i
+	sampleLog2, sampleGuesses []float64
+}
+
+// weightedStrings supports drawing strings in proportion to their counts
+type weightedStrings struct {
+	values     []string
+	cumulative []int
+}
+
+// newWeightedStrings orders counts deterministically for drawing
+func newWeightedStrings(counts map[string]int) weightedStrings {
+	ws := weightedStrings{values: make([]string, 0, len(counts))}
+	for value := range counts {
+		ws.values = append(ws.values, value)
+	}
+	sort.Strings(ws.values)
+	total := 0
+	for _, value := range ws.values {
+		total += counts[value]
+		ws.cumulative = append(ws.cumulative, total)
+	}
+	return ws
+}
+
+// draw picks a string with probability proportional to its count, or "" when there are none
+func (ws weightedStrings) draw(rng *rand.Rand) string {
+	if len(ws.values) == 0 {
+		return ""
+	}
+	target := rng.Intn(ws.cumulative[len(ws.cumulative)-1])
+	return ws.values[sort.SearchInts(ws.cumulative, target+1)]
+}
+
+// pcfgSegment is a maximal run of one class within a password
+type pcfgSegment struct {
+	class      rune
+	text       string
+	start, end int // rune indexes, end inclusive
+}
+
+// runeClass returns the segment class of r
+func runeClass(r rune) rune {
+	switch {
+	case r >= '0' && r <= '9':
+		return classDigit
+	case unicode.IsLetter(r):
+		return classLetter
+	default:
+		return classSymbol
+	}
+}
+
+// pcfgSegments splits a password into maximal runs of letters, digits and symbols
+func pcfgSegments(password string) []pcfgSegment {
+	var segments []pcfgSegment
+	index := 0
+	for _, r := range password {
+		class := runeClass(r)
+		if n := len(segments); n > 0 && segments[n-1].class == class {
+			segments[n-1].text += string(r)
+			segments[n-1].end = index
+		} else {
+			segments = append(segments, pcfgSegment{class: class, text: string(r), start: index, end: index})
+		}
+		index++
+	}
+	return segments
+}
+
+// pcfgStructure returns the structure of segments, such as L6D2S1
+func pcfgStructure(segments []pcfgSegment) string {
+	var structure strings.Builder
+	for _, segment := range segments {
+		structure.WriteString(fmt.Sprintf("%c%d", segment.class, utf8.RuneCountInString(segment.text)))
+	}
+	return structure.String()
+}
+
+// parseStructure splits a structure into its classes and run lengths
+func parseStructure(structure string) (classes []rune, lengths []int) {
+	for i := 0; i < len(structure); {
+		j := i + 1
+		for j < len(structure) && structure[j] >= '0' && structure[j] <= '9' {
+			j++
+		}
+		length, _ := strconv.Atoi(structure[i+1 : j])
+		classes = append(classes, rune(structure[i]))
+		lengths = append(lengths, length)
+		i = j
+	}
+	return classes, lengths
+}
+
+// Validate checks that a model read from outside, such as a hand-edited
+// file, has the shape training produces: positive counts, structures and run
+// lengths that parse, and letter n-grams of one to markovOrder letters
+func (m *PCFGModel) Validate() error {
+	counts := map[string]map[string]int{
+		"structures": m.Structures, "transitions": m.Transitions, "lengths": m.Lengths,
+		"digits": m.Digits, "symbols": m.Symbols, "capitalization": m.Capitalization,
+		"words": m.Words, "letters": m.Letters,
+	}
+	for name, table := range counts {
+		for key, count := range table {
+			if key == "" {
+				return fmt.Errorf("%s: empty key", name)
+			}
+			if count <= 0 {
+				return fmt.Errorf("%s: count %d for %q is not positive", name, count, key)
+			}
+		}
+	}
+	if m.Passwords <= 0 {
+		return fmt.Errorf("passwords: %d is not positive", m.Passwords)
+	}
+
+	for structure := range m.Structures {
+		if !validStructure(structure) {
+			return fmt.Errorf("structures: %q is not a sequence of L, D or S runs such as L6D2", structure)
+		}
+	}
+	for length := range m.Lengths {
+		if classes, _ := parseStructure(length); len(classes) != 1 || !validStructure(length) {
+			return fmt.Errorf("lengths: %q is not a single run such as D2", length)
+		}
+	}
+	for transition := range m.Transitions {
+		if utf8.RuneCountInString(transition) != 2 {
+			return fmt.Errorf("transitions: %q is not a pair of classes", transition)
+		}
+	}
+	for ngram := range m.Letters {
+		if utf8.RuneCountInString(ngram) > markovOrder {
+			return fmt.Errorf("letters: %q is longer than %d letters", ngram, markovOrder)
+		}
+	}
+	return nil
+}
+
+// validStructure reports whether structure is one or more runs of a class
+// and a positive length
+func validStructure(structure string) bool {
+	if structure == "" {
+		return false
+	}
+	for i := 0; i < len(structure); {
+		switch structure[i] {
+		case classLetter, classDigit, classSymbol:
+		default:
+			return false
+		}
+		j := i + 1
+		for j < len(structure) && structure[j] >= '0' && structure[j] <= '9' {
+			j++
+		}
+		if length, err := strconv.Atoi(structure[i+1 : j]); err != nil || length <= 0 {
+			return false
+		}
+		i = j
+	}
+	return true
+}
+
+// capitalization classifies the case pattern of a letter run
+func capitalization(letters string) string {
+	runes := []rune(letters)
+	lower, upper := 0, 0
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			upper++
+		} else {
+			lower++
+		}
+	}
+	switch {
+	case upper == 0:
+		return caseLower
+	case unicode.IsUpper(runes[0]) && upper == 1:
+		return caseCapitalized
+	case lower == 0:
+		return caseUpper
+	default:
+		return caseMixed
+	}
+}
+
+// PCFGTrainer accumulates the counts of a PCFGModel from a password corpus
+type PCFGTrainer struct {
+	model *PCFGModel
+}
+
+// NewPCFGTrainer creates a trainer with no passwords seen
+func NewPCFGTrainer() *PCFGTrainer {
+	return &PCFGTrainer{model: &PCFGModel{
+		Version:        PCFGModelVersion,
+		Structures:     make(map[string]int),
+		Transitions:    make(map[string]int),
+		Lengths:        make(map[string]int),
+		Digits:         make(map[string]int),
+		Symbols:        make(map[string]int),
+		Capitalization: make(map[string]int),
+		Words:          make(map[string]int),
+		Letters:        make(map[string]int),
+	}}
+}
+
+// Add counts a password seen count times; empty passwords are ignored
+func (pt *PCFGTrainer) Add(password string, count int) {
+	if password == "" || count <= 0 {
+		return
+	}
+	m := pt.model
+	segments := pcfgSegments(password)
+	m.Passwords += count
+	m.Structures[pcfgStructure(segments)] += count
+
+	previous := markovStart
+	for _, segment := range segments {
+		m.Transitions[string(previous)+string(segment.class)] += count
+		m.Lengths[fmt.Sprintf("%c%d", segment.class, utf8.RuneCountInString(segment.text))] += count
+		previous = segment.class
+
+		switch segment.class {
+		case classDigit:
+			m.Digits[segment.text] += count
+		case classSymbol:
+			m.Symbols[segment.text] += count
+		default:
+			m.Capitalization[capitalization(segment.text)] += count
+			m.Words[strings.ToLower(segment.text)] += count
+			context := []rune(strings.Repeat(string(markovStart), markovOrder-1))
+			for _, r := range strings.ToLower(segment.text) {
+				for order := 1; order <= markovOrder; order++ {
+					m.Letters[string(context[len(context)-order+1:])+string(r)] += count
+				}
+				context = append(context[1:], r)
+			}
+		}
+	}
+	m.Transitions[string(previous)+"$"] += count
+}
+
+// Model returns the trained model. Structures, runs and letter sequences
+// seen fewer than minCount times are left out, so a password
+// that appears only once in the corpus can't be read back from the model.
+func (pt *PCFGTrainer) Model(minCount int) *PCFGModel {
+	m := pt.model
+	return &PCFGModel{
+		Version:        m.Version,
+		Passwords:      m.Passwords,
+		Structures:     pruneCounts(m.Structures, minCount),
+		Transitions:    copyCounts(m.Transitions),
+		Lengths:        copyCounts(m.Lengths),
+		Digits:         pruneCounts(m.Digits, minCount),
+		Symbols:        pruneCounts(m.Symbols, minCount),
+		Capitalization: copyCounts(m.Capitalization),
+		Words:          pruneCounts(m.Words, minCount),
+		Letters:        pruneLetters(m.Letters, minCount),
+	}
+}
+
+// pruneLetters copies the letter n-grams of at least minCount, keeping every
+// single letter so the alphabet is complete
+func pruneLetters(counts map[string]int, minCount int) map[string]int {
+	pruned := pruneCounts(counts, minCount)
+	for ngram, count := range counts {
+		if utf8.RuneCountInString(ngram) == 1 {
+			pruned[ngram] = count
+		}
+	}
+	return pruned
+}
+
+// pruneCounts copies the counts of at least minCount
+func pruneCounts(counts map[string]int, minCount int) map[string]int {
+	pruned := make(map[string]int, len(counts))
+	for key, count := range counts {
+		if count >= minCount {
+			pruned[key] = count
+		}
+	}
+	return pruned
+}
+
+// copyCounts copies counts so later training doesn't change a returned model
+func copyCounts(counts map[string]int) map[string]int {
+	return pruneCounts(counts, 0)
+}
+
+var (
+	defaultPCFGModel     *PCFGModel
+	defaultPCFGModelOnce sync.Once
+)
+
+// defaultCapitalization stands in for the case statistics of the embedded
+// corpus, which is case-folded: most passwords are lowercase and most of the
+// rest are capitalized
+var defaultCapitalization = map[string]int{caseLower: 800, caseCapitalized: 150, caseUpper: 40, caseMixed: 10}
+
+// DefaultPCFGModel returns the model trained on the embedded leaked-password
+// list, training it on first use. The list is ordered by popularity without
+// counts, so each password counts as if popularity fell off as 1/rank from
+// pcfgHeadCount, and no less than once.
+func DefaultPCFGModel() *PCFGModel {
+	defaultPCFGModelOnce.Do(func() {
+		data, _ := dictionaryFiles.ReadFile("data/passwords.txt")
+		var passwords []string
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			if password := strings.TrimSpace(scanner.Text()); password != "" {
+				passwords = append(passwords, password)
+			}
+		}
+
+		trainer := NewPCFGTrainer()
+		for i, password := range passwords {
+			trainer.Add(password, max(pcfgHeadCount/(i+1), 1))
+		}
+		defaultPCFGModel = trainer.Model(1)
+		defaultPCFGModel.Capitalization = defaultCapitalization
+	})
+	return defaultPCFGModel
+}
+
+// prepare computes the totals the probabilities need and draws the sample
+// that converts probabilities to guess numbers
+func (m *PCFGModel) prepare() {
+	m.prepareOnce.Do(func() {
+		d := &m.derived
+		for _, count := range m.Structures {
+			d.structureTotal += count
+		}
+		d.structureTypes = len(m.Structures)
+		d.transitionTotals = classTotals(m.Transitions)
+		d.lengthTotals = classTotals(m.Lengths)
+		d.digitTotals, d.digitTypes = lengthTotals(m.Digits)
+		d.symbolTotals, d.symbolTypes = lengthTotals(m.Symbols)
+		d.wordTotals, d.wordTypes = lengthTotals(m.Words)
+		for _, count := range m.Capitalization {
+			d.caseTotal += count
+		}
+
+		d.contextTotals = make(map[string]int)
+		d.contextTypes = make(map[string]int)
+		letters := make(map[rune]bool)
+		for r := 'a'; r <= 'z'; r++ {
+			letters[r] = true
+		}
+		for ngram, count := range m.Letters {
+			runes := []rune(ngram)
+			context := string(runes[:len(runes)-1])
+			d.contextTotals[context] += count
+			d.contextTypes[context]++
+			letters[runes[len(runes)-1]] = true
+		}
+		for r := range letters {
+			d.alphabet = append(d.alphabet, r)
+		}
+		sort.Slice(d.alphabet, func(i, j int) bool { return d.alphabet[i] < d.alphabet[j] })
+
+		d.structures = newWeightedStrings(m.Structures)
+		d.digits = runsByLength(m.Digits)
+		d.symbols = runsByLength(m.Symbols)
+		d.words = runsByLength(m.Words)
+		m.drawSample()
+	})
+}
+
+// classTotals sums counts keyed by a leading class rune
+func classTotals(counts map[string]int) map[rune]int {
+	totals := make(map[rune]int)
+	for key, count := range counts {
+		r, _ := utf8.DecodeRuneInString(key)
+		totals[r] += count
+	}
+	return totals
+}
+
+// lengthTotals sums run counts and distinct runs by run length
+func lengthTotals(counts map[string]int) (totals, types map[int]int) {
+	totals, types = make(map[int]int), make(map[int]int)
+	for run, count := range counts {
+		length := utf8.RuneCountInString(run)
+		totals[length] += count
+		types[length]++
+	}
+	return totals, types
+}
+
+// runsByLength groups runs by length for drawing
+func runsByLength(counts map[string]int) map[int]weightedStrings {
+	grouped := make(map[int]map[string]int)
+	for run, count := range counts {
+		length := utf8.RuneCountInString(run)
+		if grouped[length] == nil {
+			grouped[length] = make(map[string]int)
+		}
+		grouped[length][run] = count
+	}
+	runs := make(map[int]weightedStrings, len(grouped))
+	for length, counts := range grouped {
+		runs[length] = newWeightedStrings(counts)
+	}
+	return runs
+}
+
+// drawSample draws passwords from the model. Following Dell'Amico and
+// Filippone, a password of probability p needs about the sum of 1/(n*q) over
+// the n samples of probability q > p guesses.
+func (m *PCFGModel) drawSample() {
+	rng := rand.New(rand.NewSource(pcfgSeed))
+	d := &m.derived
+	d.sampleLog2 = make([]float64, pcfgSamples)
+	for i := range d.sampleLog2 {
+		d.sampleLog2[i], _, _ = m.log2Probability(m.draw(rng))
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(d.sampleLog2)))
+
+	d.sampleGuesses = make([]float64, pcfgSamples+1)
+	for i, log2p := range d.sampleLog2 {
+		d.sampleGuesses[i+1] = d.sampleGuesses[i] + math.Exp2(-log2p)/pcfgSamples
+	}
+}
+
+// draw generates a password from the model
+func (m *PCFGModel) draw(rng *rand.Rand) string {
+	var password strings.Builder
+	classes, lengths := parseStructure(m.drawStructure(rng))
+	for i, class := range classes {
+		switch class {
+		case classDigit:
+			password.WriteString(drawRun(rng, m.derived.digits[lengths[i]], "0123456789", lengths[i]))
+		case classSymbol:
+			password.WriteString(drawRun(rng, m.derived.symbols[lengths[i]], symbolAlphabet, lengths[i]))
+		default:
+			password.WriteString(m.drawLetters(rng, lengths[i]))
+		}
+	}
+	return password.String()
+}
+
+// drawStructure picks a seen structure, or with the back-off's share of the
+// probability generates one class and run length at a time
+func (m *PCFGModel) drawStructure(rng *rand.Rand) string {
+	d := &m.derived
+	if d.structureTotal > 0 && rng.Intn(d.structureTotal+d.structureTypes) < d.structureTotal {
+		return d.structures.draw(rng)
+	}
+
+	var structure strings.Builder
+	previous := markovStart
+	for {
+		candidates := nextClasses(previous)
+		weights := make([]float64, len(candidates))
+		for i, next := range candidates {
+			weights[i] = m.transitionProbability(previous, next)
+		}
+		next := candidates[drawIndex(rng, weights)]
+		if next == '$' {
+			return structure.String()
+		}
+		weights = make([]float64, maxSegmentLength)
+		for length := range weights {
+			weights[length] = m.lengthProbability(next, length+1)
+		}
+		structure.WriteString(fmt.Sprintf("%c%d", next, drawIndex(rng, weights)+1))
+		previous = next
+	}
+}
+
+// drawRun picks a seen run of the length, or with the unseen share a uniformly random one
+func drawRun(rng *rand.Rand, seen weightedStrings, alphabet string, length int) string {
+	if types := len(seen.values); types > 0 {
+		if total := seen.cumulative[types-1]; rng.Intn(total+types) < total {
+			return seen.draw(rng)
+		}
+	}
+	run := make([]byte, length)
+	for i := range run {
+		run[i] = alphabet[rng.Intn(len(alphabet))]
+	}
+	return string(run)
+}
+
+// drawLetters picks a seen letter run or, with the unseen share, generates
+// one from the Markov model, then applies a case pattern
+func (m *PCFGModel) drawLetters(rng *rand.Rand, length int) string {
+	var letters []rune
+	if seen := m.derived.words[length]; len(seen.values) > 0 {
+		if total := seen.cumulative[len(seen.values)-1]; rng.Intn(total+len(seen.values)) < total {
+			letters = []rune(seen.draw(rng))
+		}
+	}
+	if letters == nil {
+		alphabet := m.derived.alphabet
+		context := []rune(strings.Repeat(string(markovStart), markovOrder-1))
+		letters = make([]rune, 0, length)
+		weights := make([]float64, len(alphabet))
+		for len(letters) < length {
+			for i, r := range alphabet {
+				weights[i] = m.letterProbability(context, r)
+			}
+			r := alphabet[drawIndex(rng, weights)]
+			letters = append(letters, r)
+			context = append(context[1:], r)
+		}
+	}
+
+	patterns := []string{caseLower, caseCapitalized, caseUpper, caseMixed}
+	weights := make([]float64, len(patterns))
+	for i, pattern := range patterns {
+		weights[i] = m.caseProbability(pattern)
+	}
+	switch patterns[drawIndex(rng, weights)] {
+	case caseCapitalized:
+		letters[0] = unicode.ToUpper(letters[0])
+	case caseUpper:
+		for i := range letters {
+			letters[i] = unicode.ToUpper(letters[i])
+		}
+	case caseMixed:
+		if length > 1 {
+			for capitalization(string(letters)) != caseMixed {
+				for i := range letters {
+					letters[i] = unicode.ToLower(letters[i])
+					if rng.Intn(2) == 0 {
+						letters[i] = unicode.ToUpper(letters[i])
+					}
+				}
+			}
+		}
+	}
+	return string(letters)
+}
+
+// drawIndex picks an index with probability proportional to its weight
+func drawIndex(rng *rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+	target := rng.Float64() * total
+	for i, weight := range weights {
+		if target < weight {
+			return i
+		}
+		target -= weight
+	}
+	return len(weights) - 1
+}
+
+// nextClasses lists the classes that can follow previous; runs are maximal,
+// so a class never follows itself, and a password has at least one run
+func nextClasses(previous rune) []rune {
+	var next []rune
+	for _, class := range []rune{classLetter, classDigit, classSymbol} {
+		if class != previous {
+			next = append(next, class)
+		}
+	}
+	if previous != markovStart {
+		next = append(next, '$')
+	}
+	return next
+}
+
+// transitionProbability is P(next class | previous class), add-one smoothed
+func (m *PCFGModel) transitionProbability(previous, next rune) float64 {
+	count := m.Transitions[string(previous)+string(next)]
+	return float64(count+1) / float64(m.derived.transitionTotals[previous]+len(nextClasses(previous)))
+}
+
+// lengthProbability is P(run length | class), add-one smoothed over 1..maxSegmentLength
+func (m *PCFGModel) lengthProbability(class rune, length int) float64 {
+	count := m.Lengths[fmt.Sprintf("%c%d", class, length)]
+	return float64(count+1) / float64(m.derived.lengthTotals[class]+maxSegmentLength)
+}
+
+// structureProbability is P(structure), Witten-Bell smoothed towards the
+// back-off that generates structures a class and run length at a time
+func (m *PCFGModel) structureProbability(structure string) float64 {
+	classes, lengths := parseStructure(structure)
+	backoff := 1.0
+	previous := markovStart
+	for i, class := range classes {
+		backoff *= m.transitionProbability(previous, class) * m.lengthProbability(class, lengths[i])
+		previous = class
+	}
+	backoff *= m.transitionProbability(previous, '$')
+
+	d := &m.derived
+	if d.structureTotal == 0 {
+		return backoff
+	}
+	return (float64(m.Structures[structure]) + float64(d.structureTypes)*backoff) /
+		float64(d.structureTotal+d.structureTypes)
+}
+
+// runProbability is P(run | class, length), Witten-Bell smoothed towards a
+// uniform choice from the alphabet
+func runProbability(count, total, types, alphabetSize, length int) float64 {
+	uniform := math.Pow(float64(alphabetSize), -float64(length))
+	if total == 0 {
+		return uniform
+	}
+	return (float64(count) + float64(types)*uniform) / float64(total+types)
+}
+
+// letterProbability is P(r | the letters before it), interpolating the
+// Markov orders with Witten-Bell smoothing from a uniform choice upwards
+func (m *PCFGModel) letterProbability(context []rune, r rune) float64 {
+	d := &m.derived
+	p := 1 / float64(len(d.alphabet))
+	for order := 1; order <= markovOrder; order++ {
+		ctx := string(context[len(context)-order+1:])
+		total := d.contextTotals[ctx]
+		if total == 0 {
+			continue
+		}
+		types := d.contextTypes[ctx]
+		p = (float64(m.Letters[ctx+string(r)]) + float64(types)*p) / float64(total+types)
+	}
+	return p
+}
+
+// caseProbability is P(case pattern), add-one smoothed
+func (m *PCFGModel) caseProbability(pattern string) float64 {
+	return float64(m.Capitalization[pattern]+1) / float64(m.derived.caseTotal+4)
+}
+
+// lettersProbability is P(letter run | its length): the probability of the
+// lowercase letters, Witten-Bell smoothed from the runs seen in training
+// towards the Markov model, times that of the case pattern
+func (m *PCFGModel) lettersProbability(letters string) float64 {
+	lower := strings.ToLower(letters)
+	p := 1.0
+	context := []rune(strings.Repeat(string(markovStart), markovOrder-1))
+	for _, r := range lower {
+		p *= m.letterProbability(context, r)
+		context = append(context[1:], r)
+	}
+	d := &m.derived
+	length := utf8.RuneCountInString(letters)
+	if total := d.wordTotals[length]; total > 0 {
+		types := d.wordTypes[length]
+		p = (float64(m.Words[lower]) + float64(types)*p) / float64(total+types)
+	}
+
+	pattern := capitalization(letters)
+	p *= m.caseProbability(pattern)
+	if pattern == caseMixed && length > 1 {
+		// Every mask but all-lower, capitalized and all-upper is mixed
+		p /= math.Exp2(float64(length)) - 3
+	}
+	return p
+}
+
+// log2Probability returns the log2 probability of a password with the
+// structure and the log2 probability of each run
+func (m *PCFGModel) log2Probability(password string) (float64, []pcfgSegment, []float64) {
+	segments := pcfgSegments(password)
+	d := &m.derived
+	total := math.Log2(m.structureProbability(pcfgStructure(segments)))
+	runs := make([]float64, len(segments))
+	for i, segment := range segments {
+		length := utf8.RuneCountInString(segment.text)
+		var p float64
+		switch segment.class {
+		case classDigit:
+			p = runProbability(m.Digits[segment.text], d.digitTotals[length], d.digitTypes[length], 10, length)
+		case classSymbol:
+			p = runProbability(m.Symbols[segment.text], d.symbolTotals[length], d.symbolTypes[length], len(symbolAlphabet), length)
+		default:
+			p = m.lettersProbability(segment.text)
+		}
+		runs[i] = math.Log2(p)
+		total += runs[i]
+	}
+	return total, segments, runs
+}
+
+// guessesFor converts a log2 probability to a guess number using the sample
+func (m *PCFGModel) guessesFor(log2p float64) float64 {
+	d := &m.derived
+	// Samples are sorted most probable first; count those more probable
+	more := sort.Search(len(d.sampleLog2), func(i int) bool { return d.sampleLog2[i] <= log2p })
+	if more == len(d.sampleLog2) {
+		// Rarer than anything drawn: the undrawn tail holds about 1/n of the
+		// probability, so at most 1/(n*p) passwords are more likely than it
+		return d.sampleGuesses[more] + math.Exp2(-log2p)/float64(len(d.sampleLog2))
+	}
+	return d.sampleGuesses[more] + 1
+}
+
+// PCFGEstimate is the guesses a probabilistic cracker needs for a password
+type PCFGEstimate struct {
+	Guesses      float64
+	GuessesLog10 float64
+	Structure    string          // such as L6D2S1, after undoing l33t substitutions
+	Factors      []ScoringFactor // add up to GuessesLog10, see Estimate
+}
+
+// Estimate returns the guesses needed for password. Mangled words are rated
+// as the word they spell at the cost of a doubling per l33t substitution, and
+// a password is never rated above brute-forcing its character set.
+//
+// The structure, l33t and run factors are the -log10 probabilities that make
+// up the password's odds; a guess rank factor then moves those odds to the
+// guess number a cracker trying the likeliest passwords first reaches it at,
+// so the factors add up to GuessesLog10.
+func (m *PCFGModel) Estimate(password string) PCFGEstimate {
+	m.prepare()
+	length := utf8.RuneCountInString(password)
+	if length == 0 {
+		return PCFGEstimate{Guesses: 1}
+	}
+
+	log2p, segments, runs := m.log2Probability(password)
+	substitutions := 0
+	if unleeted, n := unleet(password); n > 0 {
+		if leetLog2p, leetSegments, leetRuns := m.log2Probability(unleeted); leetLog2p-float64(n) > log2p {
+			log2p, segments, runs, substitutions = leetLog2p-float64(n), leetSegments, leetRuns, n
+		}
+	}
+	structure := pcfgStructure(segments)
+	guessesLog10 := math.Log10(m.guessesFor(log2p))
+
+	bruteForceLog10 := float64(length)*math.Log10(float64(charsetSize(password))) - math.Log10(2)
+	if bruteForceLog10 < guessesLog10 {
+		start, end := wholePassword(length)
+		return PCFGEstimate{
+			Guesses:      math.Pow(10, bruteForceLog10),
+			GuessesLog10: bruteForceLog10,
+			Structure:    structure,
+			Factors: []ScoringFactor{{Name: FactorBruteForce, Contribution: bruteForceLog10, Start: start, End: end,
+				Rationale: fmt.Sprintf("brute force over %d characters is faster than guessing structure %s", charsetSize(password), structure)}},
+		}
+	}
+
+	structureLog10 := -math.Log10(m.structureProbability(structure))
+	factors := []ScoringFactor{unspannedFactor(FactorStructure, structureLog10,
+		fmt.Sprintf("structure %s is 1 in %s", structure, formatOdds(structureLog10)))}
+	if substitutions > 0 {
+		factors = append(factors, unspannedFactor(FactorLeet, float64(substitutions)*math.Log10(2),
+			fmt.Sprintf("%d l33t substitutions undone", substitutions)))
+	}
+	for i, segment := range segments {
+		contribution := -runs[i] * math.Log10(2)
+		factors = append(factors, ScoringFactor{Name: runFactorNames[segment.class], Contribution: contribution,
+			Start: segment.start, End: segment.end,
+			Rationale: fmt.Sprintf("'%s' is 1 in %s runs of its class and length", segment.text, formatOdds(contribution))})
+	}
+	oddsLog10 := -log2p * math.Log10(2)
+	factors = append(factors, unspannedFactor(FactorGuessRank, guessesLog10-oddsLog10,
+		fmt.Sprintf("odds of 1 in %s put it at guess %s when the likeliest passwords are tried first", formatOdds(oddsLog10), formatOdds(guessesLog10))))
+	return PCFGEstimate{Guesses: math.Pow(10, guessesLog10), GuessesLog10: guessesLog10, Structure: structure, Factors: factors}
+}
+
+// runFactorNames names the factor for each run class
+var runFactorNames = map[rune]string{classLetter: FactorLetterRun, classDigit: FactorDigitRun, classSymbol: FactorSymbolRun}
+
+// formatOdds renders 10^log10 compactly
+func formatOdds(log10 float64) string {
+	return fmt.Sprintf("%.3g", math.Pow(10, log10))
+}
+
+// unleet undoes l33t substitutions inside words: runs of substitutable
+// characters with letters on both sides. It returns the result and the
+// number of substitutions.
+func unleet(password string) (string, int) {
+	runes := []rune(password)
+	substitutions := 0
+	for i := 0; i < len(runes); {
+		if _, ok := leetLetters[runes[i]]; !ok || i == 0 || !unicode.IsLetter(runes[i-1]) {
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && leetLetters[runes[j]] != 0 {
+			j++
+		}
+		if j < len(runes) && unicode.IsLetter(runes[j]) {
+			for k := i; k < j; k++ {
+				runes[k] = leetLetters[runes[k]]
+				substitutions++
+			}
+		}
+		i = j
+	}
+	return string(runes), substitutions
+}
+
+// charsetSize is the size of the character set a brute-force attack on
+// password has to cover
+func charsetSize(password string) int {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLetter(r):
+			lower = true
+		case r >= '0' && r <= '9':
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	size := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, len(symbolAlphabet)}} {
+		if class.present {
+			size += class.size
+		}
+	}
+	return size
+}
@@ -0,0 +1,170 @@
+package services
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPCFGModel_Estimate(t *testing.T) {
+	model := DefaultPCFGModel()
+
+	tests := []struct {
+		name          string
+		password      string
+		maxLog10      float64
+		wantStructure string
+	}{
+		{name: "top password", password: "123456", maxLog10: 2, wantStructure: "D6"},
+		{name: "word and digit", password: "password1", maxLog10: 3, wantStructure: "L8D1"},
+		{name: "l33t word", password: "P@ssw0rd!", maxLog10: 7, wantStructure: "L8S1"},
+		{name: "capitalized season", password: "Summer2024!", maxLog10: 16, wantStructure: "L6D4S1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := model.Estimate(tt.password)
+			if estimate.GuessesLog10 > tt.maxLog10 {
+				t.Errorf("Estimate(%q) log10 = %.2f, want <= %.2f", tt.password, estimate.GuessesLog10, tt.maxLog10)
+			}
+			if estimate.Structure != tt.wantStructure {
+				t.Errorf("Estimate(%q) structure = %s, want %s", tt.password, estimate.Structure, tt.wantStructure)
+			}
+		})
+	}
+}
+
+func TestPCFGModel_TopPasswords(t *testing.T) {
+	model := DefaultPCFGModel()
+	data, err := dictionaryFiles.ReadFile("data/passwords.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A cracker tries the most common passwords first
+	for _, password := range strings.Fields(string(data))[:50] {
+		if estimate := model.Estimate(password); estimate.GuessesLog10 > 3 {
+			t.Errorf("Estimate(%q) log10 = %.2f, want <= 3 for a top-50 password", password, estimate.GuessesLog10)
+		}
+	}
+}
+
+func TestPCFGModel_MangledWordVsRandom(t *testing.T) {
+	model := DefaultPCFGModel()
+
+	// Same length and character classes; only one is a decorated word
+	mangled := model.Estimate("Tr0ub4dor&3")
+	random := model.Estimate("Xq3vzk7Jw&8")
+	if mangled.GuessesLog10+2 > random.GuessesLog10 {
+		t.Errorf("Tr0ub4dor&3 needs 10^%.1f guesses, want far fewer than the random string's 10^%.1f",
+			mangled.GuessesLog10, random.GuessesLog10)
+	}
+	if mangled.Structure != "L9S1D1" {
+		t.Errorf("Tr0ub4dor&3 structure = %s, want L9S1D1 after undoing l33t", mangled.Structure)
+	}
+
+	names := make(map[string]bool)
+	for _, factor := range mangled.Factors {
+		names[factor.Name] = true
+		if factor.Name == FactorLetterRun && (factor.Start != 0 || factor.End != 8) {
+			t.Errorf("letter run factor spans %d-%d, want 0-8", factor.Start, factor.End)
+		}
+	}
+	for _, name := range []string{FactorStructure, FactorLeet, FactorLetterRun, FactorSymbolRun, FactorDigitRun} {
+		if !names[name] {
+			t.Errorf("Factors = %+v, missing %s", mangled.Factors, name)
+		}
+	}
+
+	// Rating is deterministic, as the sample is drawn with a fixed seed
+	if again := model.Estimate("Tr0ub4dor&3"); again.GuessesLog10 != mangled.GuessesLog10 {
+		t.Errorf("Estimate() = %v then %v, want the same", mangled.GuessesLog10, again.GuessesLog10)
+	}
+}
+
+func TestPCFGModel_FactorsAddUpToGuesses(t *testing.T) {
+	model := DefaultPCFGModel()
+	for _, password := range []string{"123456", "password1", "Tr0ub4dor&3", "Summer2024!", "q7"} {
+		estimate := model.Estimate(password)
+		total := 0.0
+		for _, factor := range estimate.Factors {
+			total += factor.Contribution
+		}
+		if math.Abs(total-estimate.GuessesLog10) > 1e-9 {
+			t.Errorf("Estimate(%q) factors add up to %.3f, want %.3f: %+v", password, total, estimate.GuessesLog10, estimate.Factors)
+		}
+	}
+}
+
+func TestPCFGModel_BruteForceCap(t *testing.T) {
+	estimate := DefaultPCFGModel().Estimate("q7")
+	if len(estimate.Factors) != 1 || estimate.Factors[0].Name != FactorBruteForce {
+		t.Fatalf("Factors = %+v, want brute force only", estimate.Factors)
+	}
+	if estimate.Guesses > 36*36/2 {
+		t.Errorf("Guesses = %v, want at most half of 36^2", estimate.Guesses)
+	}
+}
+
+func TestPCFGTrainer_Model(t *testing.T) {
+	trainer := NewPCFGTrainer()
+	trainer.Add("monkey12", 3)
+	trainer.Add("Monkey12!", 2)
+	trainer.Add("zebra99", 1)
+	trainer.Add("", 5)
+
+	model := trainer.Model(2)
+	if model.Passwords != 6 {
+		t.Errorf("Passwords = %d, want 6", model.Passwords)
+	}
+	if model.Structures["L6D2"] != 3 || model.Structures["L6D2S1"] != 2 || model.Digits["12"] != 5 {
+		t.Errorf("model counts = %+v", model)
+	}
+	if model.Capitalization[caseCapitalized] != 2 || model.Capitalization[caseLower] != 4 {
+		t.Errorf("Capitalization = %v", model.Capitalization)
+	}
+
+	// The password seen once can't be read back from the model
+	data, err := json.Marshal(model)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if model.Words["monkey"] != 5 {
+		t.Errorf("Words = %v, want monkey counted in either case", model.Words)
+	}
+	for _, leaked := range []string{`"L5D2"`, `"99"`, `"zebra"`, `"zeb"`, `"^ze"`} {
+		if strings.Contains(string(data), leaked) {
+			t.Errorf("model kept %s from a password seen once: %s", leaked, data)
+		}
+	}
+
+	var decoded PCFGModel
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Estimate("monkey12").GuessesLog10 >= decoded.Estimate("zebra99").GuessesLog10 {
+		t.Error("a decoded model rated the trained password no more likely than an unseen one")
+	}
+}
+
+func TestUnleet(t *testing.T) {
+	tests := []struct {
+		password          string
+		want              string
+		wantSubstitutions int
+	}{
+		{"P@ssw0rd", "Password", 2},
+		{"Tr0ub4dor&3", "Troubador&3", 2},
+		{"h3ll0", "hell0", 1}, // a trailing digit is a suffix, not a letter
+		{"b00k", "book", 2},
+		{"abc123", "abc123", 0},
+	}
+
+	for _, tt := range tests {
+		got, substitutions := unleet(tt.password)
+		if got != tt.want || substitutions != tt.wantSubstitutions {
+			t.Errorf("unleet(%q) = %q, %d, want %q, %d", tt.password, got, substitutions, tt.want, tt.wantSubstitutions)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// PolicyChecker evaluates passwords against the policy of a compliance
+// profile. Account-management rules such as expiry and history can't be
+// judged from a password alone and are left to the profile's rule listing.
+type PolicyChecker struct {
+	guessEstimator *GuessEstimator
+}
+
+// NewPolicyChecker creates a checker that uses guessEstimator for profiles
+// requiring a blocklist
+func NewPolicyChecker(guessEstimator *GuessEstimator) *PolicyChecker {
+	return &PolicyChecker{guessEstimator: guessEstimator}
+}
+
+// Check evaluates a password against a profile. As with the NIST checker,
+// blocklist evidence is taken from result and userInputs.
+func (pc *PolicyChecker) Check(password string, profile entities.ComplianceProfile, result StrengthCheckResult, userInputs ...string) ComplianceResult {
+	compliance := ComplianceResult{
+		Standard:   profile.Name,
+		Normalized: password,
+		Length:     utf8.RuneCountInString(password),
+		MinLength:  profile.Policy.MinLength,
+		Checks:     pc.compositionChecks(password, profile),
+	}
+
+	if profile.Policy.Blocklist {
+		for _, check := range []ComplianceCheck{
+			breachCheck(result),
+			commonPasswordCheck(pc.guessEstimator, password),
+			contextCheck(password, result, userInputs),
+		} {
+			check.Reference = profile.References["blocklist"]
+			compliance.Checks = append(compliance.Checks, check)
+		}
+	}
+
+	compliance.Compliant = true
+	for _, check := range compliance.Checks {
+		if check.Status == ComplianceStatusFail {
+			compliance.Compliant = false
+		}
+	}
+	return compliance
+}
+
+// Satisfies reports whether a password meets the profile's length and
+// character rules. Generators use it to discard passwords that happen to
+// miss a required character type.
+func (pc *PolicyChecker) Satisfies(password string, profile entities.ComplianceProfile) bool {
+	for _, check := range pc.compositionChecks(password, profile) {
+		if check.Status == ComplianceStatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// compositionChecks evaluates the length and character rules of a profile
+func (pc *PolicyChecker) compositionChecks(password string, profile entities.ComplianceProfile) []ComplianceCheck {
+	policy := profile.Policy
+	var checks []ComplianceCheck
+	add := func(field, requirement string, passed bool, detail string) {
+		status := ComplianceStatusPass
+		if !passed {
+			status = ComplianceStatusFail
+		}
+		checks = append(checks, ComplianceCheck{
+			Requirement: requirement, Status: status, Detail: detail, Reference: profile.References[field],
+		})
+	}
+
+	length := utf8.RuneCountInString(password)
+	add("min_length", "Minimum length", length >= policy.MinLength,
+		fmt.Sprintf("%d characters (at least %d)", length, policy.MinLength))
+	if policy.MaxLength > 0 {
+		add("max_length", "Maximum length", length <= policy.MaxLength,
+			fmt.Sprintf("%d characters (at most %d)", length, policy.MaxLength))
+	}
+
+	var lower, upper, letters, numbers, symbols, spaces, nonASCII bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower, letters = true, true
+		case unicode.IsUpper(r):
+			upper, letters = true, true
+		case unicode.IsLetter(r):
+			letters = true
+		case unicode.IsDigit(r):
+			numbers = true
+		case unicode.IsSpace(r):
+			spaces = true
+		default:
+			symbols = true
+		}
+		if r > unicode.MaxASCII {
+			nonASCII = true
+		}
+	}
+
+	present := func(ok bool, what string) string {
+		if ok {
+			return "contains " + what
+		}
+		return "no " + what
+	}
+	if policy.RequireLetters {
+		add("require_letters", "Letters", letters, present(letters, "letters"))
+	}
+	if policy.RequireLower {
+		add("require_lower", "Lowercase", lower, present(lower, "lowercase letters"))
+	}
+	if policy.RequireUpper {
+		add("require_upper", "Uppercase", upper, present(upper, "uppercase letters"))
+	}
+	if policy.RequireNumbers {
+		add("require_numbers", "Numbers", numbers, present(numbers, "numbers"))
+	}
+	if policy.RequireSymbols {
+		add("require_symbols", "Symbols", symbols, present(symbols, "symbols"))
+	}
+	if policy.MinCharClasses > 0 {
+		var classes []string
+		for _, class := range []struct {
+			present bool
+			name    string
+		}{{lower, "lowercase"}, {upper, "uppercase"}, {numbers, "numbers"}, {symbols, "symbols"}} {
+			if class.present {
+				classes = append(classes, class.name)
+			}
+		}
+		add("min_char_classes", "Character types", len(classes) >= policy.MinCharClasses,
+			fmt.Sprintf("%d of %d required (%s)", len(classes), policy.MinCharClasses, strings.Join(classes, ", ")))
+	}
+	if spaces && !policy.AllowSpaces {
+		add("allow_spaces", "Spaces", false, "spaces are not allowed")
+	}
+	if nonASCII && !policy.AllowUnicode {
+		add("allow_unicode", "Unicode characters", false, "only ASCII characters are allowed")
+	}
+
+	return checks
+}
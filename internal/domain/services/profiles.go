@@ -0,0 +1,69 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"sort"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// Built-in compliance profiles, one JSON file per profile. Adding a file here
+// (or to the user's profile directory) adds a profile; no code changes needed.
+//
+//go:embed data/profiles/*.json
+var profileFiles embed.FS
+
+// ProfileLibrary holds the compliance profiles selectable by name
+type ProfileLibrary struct {
+	profiles map[string]entities.ComplianceProfile
+}
+
+// NewProfileLibrary creates a library holding the built-in profiles
+func NewProfileLibrary() *ProfileLibrary {
+	library := &ProfileLibrary{profiles: make(map[string]entities.ComplianceProfile)}
+
+	entries, _ := profileFiles.ReadDir("data/profiles")
+	for _, entry := range entries {
+		data, err := profileFiles.ReadFile("data/profiles/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		// Built-in profiles are validated by the tests, so a bad one is a build defect
+		profile, err := entities.ParseComplianceProfile(bytes.NewReader(data))
+		if err != nil {
+			panic(err)
+		}
+		library.profiles[profile.Name] = profile
+	}
+	return library
+}
+
+// Add registers profiles, replacing any with the same name
+func (pl *ProfileLibrary) Add(profiles ...entities.ComplianceProfile) *ProfileLibrary {
+	for _, profile := range profiles {
+		pl.profiles[profile.Name] = profile
+	}
+	return pl
+}
+
+// Get returns the profile with the given name
+func (pl *ProfileLibrary) Get(name string) (entities.ComplianceProfile, error) {
+	profile, ok := pl.profiles[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return entities.ComplianceProfile{}, entities.NewPasswordError(
+			"unknown profile: " + name + " (available: " + strings.Join(pl.Names(), ", ") + ")")
+	}
+	return profile, nil
+}
+
+// Names lists the profile names in alphabetical order
+func (pl *ProfileLibrary) Names() []string {
+	names := make([]string, 0, len(pl.profiles))
+	for name := range pl.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
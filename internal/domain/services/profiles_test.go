@@ -0,0 +1,81 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+func TestProfileLibrary_Builtin(t *testing.T) {
+	library := NewProfileLibrary()
+
+	for _, name := range []string{"pci-dss-4", "cis-controls-8", "hipaa", "ad-default"} {
+		profile, err := library.Get(name)
+		if err != nil {
+			t.Errorf("Get(%q) error = %v", name, err)
+			continue
+		}
+		if profile.Title == "" || len(profile.References) == 0 {
+			t.Errorf("profile %s lacks a title or references", name)
+		}
+		for _, rule := range profile.Rules() {
+			if rule.Field == "min_length" && rule.Reference == "" {
+				t.Errorf("profile %s has no reference for its minimum length", name)
+			}
+		}
+	}
+
+	if _, err := library.Get("sox"); err == nil {
+		t.Error("Get(unknown) succeeded, want an error")
+	}
+}
+
+func TestProfileLibrary_Add(t *testing.T) {
+	custom := entities.ComplianceProfile{
+		Name:       "pci-dss-4",
+		Title:      "Stricter PCI",
+		Generation: entities.ProfileGeneration{Length: 24, Lower: true},
+		Policy:     entities.PasswordPolicy{MinLength: 20},
+	}
+	library := NewProfileLibrary().Add(custom)
+
+	profile, err := library.Get("PCI-DSS-4")
+	if err != nil || profile.Title != "Stricter PCI" {
+		t.Errorf("Get() = %+v, %v, want the added profile to replace the built-in one", profile, err)
+	}
+}
+
+func TestPolicyChecker_Check(t *testing.T) {
+	checker := NewPolicyChecker(NewGuessEstimator())
+	strength := NewPasswordStrengthChecker()
+	library := NewProfileLibrary()
+
+	tests := []struct {
+		profile       string
+		password      string
+		wantCompliant bool
+	}{
+		{"pci-dss-4", "violet-orbit-42-lamp", true},
+		{"pci-dss-4", "violet-orbit-lamp", false}, // no number
+		{"pci-dss-4", "v1olet-orb", false},        // too short
+		{"ad-default", "Summer2024", true},        // three character types
+		{"ad-default", "summer2024", false},
+		{"hipaa", "correct horse battery staple", true},
+		{"hipaa", "passwordpassword", false}, // blocklisted repeat
+	}
+
+	for _, tt := range tests {
+		profile, err := library.Get(tt.profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := strength.CheckPasswordStrength(entities.NewPassword(tt.password))
+		compliance := checker.Check(tt.password, profile, result)
+		if compliance.Compliant != tt.wantCompliant {
+			t.Errorf("Check(%q, %s).Compliant = %v, want %v (%+v)", tt.password, tt.profile, compliance.Compliant, tt.wantCompliant, compliance.Checks)
+		}
+		if checker.Satisfies(tt.password, profile) && !compliance.Compliant && !profile.Policy.Blocklist {
+			t.Errorf("Satisfies(%q, %s) = true for a non-compliant password", tt.password, tt.profile)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// Scoring factor names
+const (
+	FactorLength      = "length"
+	FactorLengthBonus = "length bonus"
+	FactorLowercase   = "lowercase"
+	FactorUppercase   = "uppercase"
+	FactorNumbers     = "numbers"
+	FactorSymbols     = "symbols"
+	FactorBreached    = "breached"
+	FactorBannedWord  = "banned word"
+	FactorUserContext = "account details"
+	FactorMixedScript = "mixed scripts"
+	FactorConfusable  = "lookalike character"
+	FactorCharset     = "character set"
+	FactorDictionary  = "dictionary word"
+	FactorStructure   = "structure"
+	FactorLetterRun   = "letter run"
+	FactorDigitRun    = "digit run"
+	FactorSymbolRun   = "symbol run"
+	FactorLeet        = "l33t substitution"
+	FactorBruteForce  = "brute force"
+	FactorGuessRank   = "guess rank"
+)
+
+// ScoringFactor is one reason a password got its rating. The contributions of
+// a result's factors add up to its rating: checklist points for the strength
+// checker, bits of entropy for the analyzer, log10 guesses for pcfg.
+type ScoringFactor struct {
+	Name         string
+	Contribution float64 // negative when the factor lowers the rating
+	Start        int     // rune index of the first character of the evidence, -1 when there is none
+	End          int     // rune index of the last character of the evidence (inclusive)
+	Rationale    string
+}
+
+// HasSpan reports whether the factor points at characters of the password
+func (f ScoringFactor) HasSpan() bool {
+	return f.Start >= 0
+}
+
+// wholePassword is the span of a password of length runes
+func wholePassword(length int) (int, int) {
+	return 0, length - 1
+}
+
+// unspannedFactor is a factor about something the password lacks
+func unspannedFactor(name string, contribution float64, rationale string) ScoringFactor {
+	return ScoringFactor{Name: name, Contribution: contribution, Start: -1, End: -1, Rationale: rationale}
+}
+
+// confusableFactors records mixed scripts and lookalike characters. They
+// contribute nothing to the rating: they make a password hard to retype, not
+// easier to guess.
+func confusableFactors(report entities.ConfusableReport, length int) []ScoringFactor {
+	var factors []ScoringFactor
+	if report.MixedScript {
+		start, end := wholePassword(length)
+		factors = append(factors, ScoringFactor{Name: FactorMixedScript, Start: start, End: end,
+			Rationale: fmt.Sprintf("Mixes %s letters", joinWithAnd(report.Scripts))})
+	}
+	for _, char := range report.Characters {
+		factors = append(factors, ScoringFactor{Name: FactorConfusable, Start: char.Position, End: char.Position,
+			Rationale: fmt.Sprintf("'%c' (%s) looks like '%s'", char.Char, char.Script, char.LooksLike)})
+	}
+	return factors
+}
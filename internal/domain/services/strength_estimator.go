@@ -0,0 +1,234 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// Built-in estimator names
+const (
+	EstimatorChecklist = "checklist"
+	EstimatorEntropy   = "entropy"
+	EstimatorZxcvbn    = "zxcvbn"
+	EstimatorPCFG      = "pcfg"
+)
+
+// Ways of combining several estimators' ratings into one
+const (
+	AggregateMin = "min" // the most pessimistic rating wins
+	AggregateMax = "max" // the most optimistic rating wins
+)
+
+// StrengthEstimate is one estimator's rating of a password
+type StrengthEstimate struct {
+	Estimator string
+	Strength  entities.PasswordStrength
+	Score     float64 // in the estimator's own unit
+	MaxScore  float64 // 0 when the unit has no upper bound
+	Unit      string
+	Factors   []ScoringFactor // the reasons for Score
+}
+
+// StrengthEstimator rates passwords. Implementations register with an
+// EstimatorRegistry to become selectable by name.
+type StrengthEstimator interface {
+	Name() string
+	Estimate(password entities.Password) (StrengthEstimate, error)
+}
+
+// EstimatorRegistry holds the strength estimators selectable by name
+type EstimatorRegistry struct {
+	estimators map[string]StrengthEstimator
+}
+
+// NewEstimatorRegistry creates a registry holding the built-in estimators
+func NewEstimatorRegistry(checker *PasswordStrengthChecker, analyzer *PasswordAnalyzer, guessEstimator *GuessEstimator) *EstimatorRegistry {
+	registry := &EstimatorRegistry{estimators: make(map[string]StrengthEstimator)}
+	for _, estimator := range []StrengthEstimator{
+		checklistEstimator{checker},
+		entropyEstimator{analyzer},
+		zxcvbnEstimator{guessEstimator},
+		pcfgEstimator{},
+	} {
+		registry.estimators[estimator.Name()] = estimator
+	}
+	return registry
+}
+
+// IsBuiltinEstimator reports whether name belongs to a built-in estimator
+func IsBuiltinEstimator(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case EstimatorChecklist, EstimatorEntropy, EstimatorZxcvbn, EstimatorPCFG:
+		return true
+	default:
+		return false
+	}
+}
+
+// Register adds estimators, replacing any earlier one with the same name.
+// Built-in estimators can't be replaced.
+func (er *EstimatorRegistry) Register(estimators ...StrengthEstimator) error {
+	for _, estimator := range estimators {
+		if IsBuiltinEstimator(estimator.Name()) {
+			return entities.NewPasswordError("estimator name " + estimator.Name() + " is taken by a built-in estimator")
+		}
+		er.estimators[strings.ToLower(estimator.Name())] = estimator
+	}
+	return nil
+}
+
+// UsePCFGModel makes the pcfg estimator rate with a trained model in place of
+// the one trained on the embedded corpus
+func (er *EstimatorRegistry) UsePCFGModel(model *PCFGModel) {
+	er.estimators[EstimatorPCFG] = pcfgEstimator{model: model}
+}
+
+// Get returns the estimator with the given name
+func (er *EstimatorRegistry) Get(name string) (StrengthEstimator, error) {
+	estimator, ok := er.estimators[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, entities.NewPasswordError(
+			"unknown estimator: " + name + " (available: " + strings.Join(er.Names(), ", ") + ")")
+	}
+	return estimator, nil
+}
+
+// Names lists the registered estimators alphabetically
+func (er *EstimatorRegistry) Names() []string {
+	names := make([]string, 0, len(er.estimators))
+	for name := range er.estimators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EstimateAll rates a password with each named estimator, in the order given
+func (er *EstimatorRegistry) EstimateAll(password entities.Password, names []string) ([]StrengthEstimate, error) {
+	estimates := make([]StrengthEstimate, 0, len(names))
+	for _, name := range names {
+		estimator, err := er.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		estimate, err := estimator.Estimate(password)
+		if err != nil {
+			return nil, err
+		}
+		estimates = append(estimates, estimate)
+	}
+	return estimates, nil
+}
+
+// CombineStrengths reduces several ratings to one by aggregation
+func CombineStrengths(estimates []StrengthEstimate, aggregation string) (entities.PasswordStrength, error) {
+	if aggregation != AggregateMin && aggregation != AggregateMax {
+		return entities.VeryWeak, entities.NewPasswordError("unknown aggregation: " + aggregation + " (available: min, max)")
+	}
+	if len(estimates) == 0 {
+		return entities.VeryWeak, entities.NewPasswordError("no estimates to combine")
+	}
+	combined := estimates[0].Strength
+	for _, estimate := range estimates[1:] {
+		if (aggregation == AggregateMin && estimate.Strength < combined) ||
+			(aggregation == AggregateMax && estimate.Strength > combined) {
+			combined = estimate.Strength
+		}
+	}
+	return combined, nil
+}
+
+// checklistEstimator rates by the character-class checklist
+type checklistEstimator struct {
+	checker *PasswordStrengthChecker
+}
+
+func (ce checklistEstimator) Name() string { return EstimatorChecklist }
+
+func (ce checklistEstimator) Estimate(password entities.Password) (StrengthEstimate, error) {
+	result := ce.checker.CheckPasswordStrength(password)
+	return StrengthEstimate{
+		Estimator: EstimatorChecklist,
+		Strength:  result.Strength,
+		Score:     float64(result.Score),
+		MaxScore:  float64(result.MaxScore),
+		Unit:      "points",
+		Factors:   result.Factors,
+	}, nil
+}
+
+// entropyEstimator rates by the entropy of the character types present, less
+// what recognized dictionary words give away
+type entropyEstimator struct {
+	analyzer *PasswordAnalyzer
+}
+
+func (ee entropyEstimator) Name() string { return EstimatorEntropy }
+
+func (ee entropyEstimator) Estimate(password entities.Password) (StrengthEstimate, error) {
+	analysis := ee.analyzer.AnalyzePassword(password, password.ObservedConfig())
+	return StrengthEstimate{
+		Estimator: EstimatorEntropy,
+		Strength:  analysis.Strength,
+		Score:     analysis.Entropy,
+		Unit:      "bits",
+		Factors:   analysis.Factors,
+	}, nil
+}
+
+// zxcvbnEstimator rates by the guesses needed for the cheapest decomposition
+// into patterns, converted to bits for the shared scale
+type zxcvbnEstimator struct {
+	guessEstimator *GuessEstimator
+}
+
+func (ze zxcvbnEstimator) Name() string { return EstimatorZxcvbn }
+
+func (ze zxcvbnEstimator) Estimate(password entities.Password) (StrengthEstimate, error) {
+	estimate := ze.guessEstimator.Estimate(password.Value)
+
+	factors := make([]ScoringFactor, 0, len(estimate.Sequence))
+	for _, match := range estimate.Sequence {
+		factors = append(factors, ScoringFactor{Name: match.Pattern, Contribution: match.GuessesLog10,
+			Start: match.Start, End: match.End, Rationale: "'" + match.Token + "' matched as " + match.Pattern})
+	}
+
+	// Analyzer guesses are half the search space, so the space is twice the guesses
+	bits := math.Log2(2 * estimate.Guesses)
+	return StrengthEstimate{
+		Estimator: EstimatorZxcvbn,
+		Strength:  StrengthForEntropy(bits),
+		Score:     estimate.GuessesLog10,
+		Unit:      "log10 guesses",
+		Factors:   factors,
+	}, nil
+}
+
+// pcfgEstimator rates by the guesses a probabilistic cracker needs, converted
+// to bits for the shared scale
+type pcfgEstimator struct {
+	model *PCFGModel // nil for the model trained on the embedded corpus
+}
+
+func (pe pcfgEstimator) Name() string { return EstimatorPCFG }
+
+func (pe pcfgEstimator) Estimate(password entities.Password) (StrengthEstimate, error) {
+	model := pe.model
+	if model == nil {
+		model = DefaultPCFGModel()
+	}
+	estimate := model.Estimate(password.Value)
+
+	// As for zxcvbn, the space is twice the guesses
+	bits := math.Log2(2 * estimate.Guesses)
+	return StrengthEstimate{
+		Estimator: EstimatorPCFG,
+		Strength:  StrengthForEntropy(bits),
+		Score:     estimate.GuessesLog10,
+		Unit:      "log10 guesses",
+		Factors:   estimate.Factors,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// fixedEstimator rates every password the same, standing in for an in-house estimator
+type fixedEstimator struct {
+	name     string
+	strength entities.PasswordStrength
+}
+
+func (fe fixedEstimator) Name() string { return fe.name }
+
+func (fe fixedEstimator) Estimate(password entities.Password) (StrengthEstimate, error) {
+	return StrengthEstimate{Estimator: fe.name, Strength: fe.strength}, nil
+}
+
+func newTestRegistry() *EstimatorRegistry {
+	return NewEstimatorRegistry(NewPasswordStrengthChecker(), NewPasswordAnalyzer(), NewGuessEstimator())
+}
+
+func TestEstimatorRegistry_BuiltinEstimators(t *testing.T) {
+	registry := newTestRegistry()
+	if got, want := registry.Names(), []string{EstimatorChecklist, EstimatorEntropy, EstimatorPCFG, EstimatorZxcvbn}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Names() = %v, want %v", got, want)
+	}
+
+	// The checklist is fooled by a decorated common password; the others aren't
+	estimates, err := registry.EstimateAll(entities.NewPassword("P@ssw0rd!"), []string{"checklist", "ENTROPY", "zxcvbn"})
+	if err != nil {
+		t.Fatalf("EstimateAll() unexpected error: %v", err)
+	}
+	if len(estimates) != 3 {
+		t.Fatalf("EstimateAll() returned %d estimates, want 3", len(estimates))
+	}
+	checklist, entropy, zxcvbn := estimates[0], estimates[1], estimates[2]
+	if checklist.Estimator != EstimatorChecklist || checklist.MaxScore != 8 || len(checklist.Factors) == 0 {
+		t.Errorf("checklist estimate = %+v", checklist)
+	}
+	if entropy.Unit != "bits" || len(entropy.Factors) == 0 {
+		t.Errorf("entropy estimate = %+v", entropy)
+	}
+	if zxcvbn.Strength >= checklist.Strength {
+		t.Errorf("zxcvbn rated %v, want below the checklist's %v", zxcvbn.Strength, checklist.Strength)
+	}
+
+	if _, err := registry.EstimateAll(entities.NewPassword("x"), []string{"missing"}); err == nil {
+		t.Error("expected error for an unknown estimator")
+	}
+}
+
+func TestEstimatorRegistry_Register(t *testing.T) {
+	registry := newTestRegistry()
+	if err := registry.Register(fixedEstimator{"inhouse", entities.Strong}); err != nil {
+		t.Fatalf("Register() unexpected error: %v", err)
+	}
+
+	estimates, err := registry.EstimateAll(entities.NewPassword("x"), []string{"inhouse"})
+	if err != nil {
+		t.Fatalf("EstimateAll() unexpected error: %v", err)
+	}
+	if estimates[0].Strength != entities.Strong {
+		t.Errorf("EstimateAll() = %+v, want the registered estimator", estimates)
+	}
+
+	if err := registry.Register(fixedEstimator{"ZXCVBN", entities.VeryStrong}); err == nil {
+		t.Error("Register() should refuse the name of a built-in estimator")
+	}
+	if estimates, _ := registry.EstimateAll(entities.NewPassword("x"), []string{"zxcvbn"}); estimates[0].Strength == entities.VeryStrong {
+		t.Error("the built-in zxcvbn estimator was replaced")
+	}
+}
+
+func TestCombineStrengths(t *testing.T) {
+	estimates := []StrengthEstimate{{Strength: entities.Strong}, {Strength: entities.Weak}, {Strength: entities.Medium}}
+
+	tests := []struct {
+		aggregation string
+		want        entities.PasswordStrength
+		wantErr     bool
+	}{
+		{AggregateMin, entities.Weak, false},
+		{AggregateMax, entities.Strong, false},
+		{"mean", entities.VeryWeak, true},
+	}
+	for _, tt := range tests {
+		got, err := CombineStrengths(estimates, tt.aggregation)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("CombineStrengths(%s) = %v, %v, want %v (error %v)", tt.aggregation, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if _, err := CombineStrengths(nil, AggregateMin); err == nil {
+		t.Error("expected error combining no estimates")
+	}
+}
@@ -150,18 +150,8 @@ func (wpg *WordPasswordGenerator) AnalyzeWordPassword(password, originalWord str
 	// Create password entity and basic config for analysis
 	passwordEntity := entities.NewPassword(password)
 
-	// Create a basic config based on password characteristics
-	config := entities.PasswordConfig{
-		Length:         len(password),
-		IncludeLower:   passwordEntity.HasLowercase(),
-		IncludeUpper:   passwordEntity.HasUppercase(),
-		IncludeNumbers: passwordEntity.HasNumbers(),
-		IncludeSymbols: passwordEntity.HasSymbols(),
-		Count:          1,
-	}
-
-	// Use the existing password analyzer
-	analysis := wpg.analyzer.AnalyzePassword(passwordEntity, config)
+	// Use the existing password analyzer, with a config based on password characteristics
+	analysis := wpg.analyzer.AnalyzePassword(passwordEntity, passwordEntity.ObservedConfig())
 
 	// Add word-specific insights
 	analysis.WordBased = true
@@ -171,54 +161,49 @@ func (wpg *WordPasswordGenerator) AnalyzeWordPassword(password, originalWord str
 	return &analysis, nil
 }
 
-// assessTransformationQuality evaluates how well the word was transformed
+// assessTransformationQuality evaluates how well the word was transformed.
+// The word is looked for after undoing l33t substitutions and case changes,
+// since cracking rules try those first.
 func (wpg *WordPasswordGenerator) assessTransformationQuality(password, originalWord string) string {
-	password = strings.ToLower(password)
-	originalWord = strings.ToLower(originalWord)
-
-	// Check if original word is still clearly visible
-	if strings.Contains(password, originalWord) {
-		baseWordIndex := strings.Index(password, originalWord)
-		beforeWord := password[:baseWordIndex]
-		afterWord := password[baseWordIndex+len(originalWord):]
-
-		hasPrefix := len(beforeWord) > 0
-		hasSuffix := len(afterWord) > 0
-		hasNumbers := strings.ContainsAny(password, "0123456789")
-		hasSymbols := strings.ContainsAny(password, "!@#$%^&*()_+-=[]{}|;:,.<>?")
-		hasMixedCase := password != strings.ToLower(password)
-
-		transformationCount := 0
-		if hasPrefix {
-			transformationCount++
-		}
-		if hasSuffix {
-			transformationCount++
-		}
-		if hasNumbers {
-			transformationCount++
-		}
-		if hasSymbols {
-			transformationCount++
-		}
-		if hasMixedCase {
+	matches := entities.NewBannedWordMatcher(0, entities.NewBannedWordList("word", []string{originalWord})).Match(password)
+	if len(matches) == 0 {
+		return "Advanced transformation - original word well-disguised"
+	}
+	match := matches[0]
+	runes := []rune(password)
+	beforeWord := string(runes[:match.Start])
+	afterWord := string(runes[match.End+1:])
+
+	// Digits and symbols standing in for letters are undone by cracking rules,
+	// so only those around the word add to the transformation
+	hasPrefix := beforeWord != ""
+	hasSuffix := afterWord != ""
+	hasNumbers := strings.ContainsAny(beforeWord+afterWord, "0123456789")
+	hasSymbols := strings.ContainsAny(beforeWord+afterWord, "!@#$%^&*()_+-=[]{}|;:,.<>?")
+	hasMixedCase := password != strings.ToLower(password) && password != strings.ToUpper(password)
+
+	transformationCount := 0
+	for _, applied := range []bool{hasPrefix, hasSuffix, hasNumbers, hasSymbols, hasMixedCase} {
+		if applied {
 			transformationCount++
 		}
-
-		switch {
-		case transformationCount >= 4:
-			return "Excellent transformation - highly secure while memorable"
-		case transformationCount >= 3:
-			return "Good transformation - secure and readable"
-		case transformationCount >= 2:
-			return "Moderate transformation - could be more complex"
-		default:
-			return "Basic transformation - consider adding more complexity"
-		}
 	}
 
-	// If original word is heavily modified (leetspeak, etc.)
-	return "Advanced transformation - original word well-disguised"
+	var quality string
+	switch {
+	case transformationCount >= 4:
+		quality = "Excellent transformation - highly secure while memorable"
+	case transformationCount >= 3:
+		quality = "Good transformation - secure and readable"
+	case transformationCount >= 2:
+		quality = "Moderate transformation - could be more complex"
+	default:
+		quality = "Basic transformation - consider adding more complexity"
+	}
+	if match.Leet {
+		quality += fmt.Sprintf(" ('%s' reads as '%s' once l33t substitutions are undone)", match.Token, match.Word)
+	}
+	return quality
 }
 
 // GetWordStrategySuggestions provides suggestions for improving word-based passwords
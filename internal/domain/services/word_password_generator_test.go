@@ -133,3 +133,22 @@ func TestWordPasswordGenerator_AnalyzeWordPassword(t *testing.T) {
 		t.Error("Transformation quality should not be empty")
 	}
 }
+
+func TestWordPasswordGenerator_TransformationQualityUndoesLeet(t *testing.T) {
+	generator := NewWordPasswordGenerator(NewPasswordAnalyzer())
+
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{"S3cur1ty!23", "Excellent transformation - highly secure while memorable ('S3cur1ty' reads as 'security' once l33t substitutions are undone)"},
+		{"s3cur1ty", "Basic transformation - consider adding more complexity ('s3cur1ty' reads as 'security' once l33t substitutions are undone)"},
+		{"security42", "Moderate transformation - could be more complex"},
+		{"Zq9#vT2!", "Advanced transformation - original word well-disguised"},
+	}
+	for _, tt := range tests {
+		if got := generator.assessTransformationQuality(tt.password, "security"); got != tt.want {
+			t.Errorf("assessTransformationQuality(%q) = %q, want %q", tt.password, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+func TestReadPasswords_Lines(t *testing.T) {
+	entries, err := ReadPasswords(strings.NewReader("hunter2\r\n\npassword\n  spaced  \n"), ReadOptions{})
+	if err != nil {
+		t.Fatalf("ReadPasswords() error = %v", err)
+	}
+
+	want := []services.AuditEntry{{Line: 1, Password: "hunter2"}, {Line: 3, Password: "password"}, {Line: 4, Password: "  spaced  "}}
+	if len(entries) != len(want) {
+		t.Fatalf("ReadPasswords() = %+v, want %+v", entries, want)
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+}
+
+func TestReadPasswords_CSV(t *testing.T) {
+	input := "user,Password\nbob,hunter2\nalice,\"pa,ss\"\ncarol,\n"
+
+	byName, err := ReadPasswords(strings.NewReader(input), ReadOptions{CSV: true, Column: "password"})
+	if err != nil {
+		t.Fatalf("ReadPasswords() error = %v", err)
+	}
+	if len(byName) != 2 || byName[0] != (services.AuditEntry{Line: 2, Password: "hunter2"}) || byName[1].Password != "pa,ss" {
+		t.Errorf("ReadPasswords() by name = %+v", byName)
+	}
+
+	byIndex, err := ReadPasswords(strings.NewReader(input), ReadOptions{CSV: true, Column: "2", Header: true})
+	if err != nil {
+		t.Fatalf("ReadPasswords() error = %v", err)
+	}
+	if len(byIndex) != 2 || byIndex[1] != (services.AuditEntry{Line: 3, Password: "pa,ss"}) {
+		t.Errorf("ReadPasswords() by index = %+v", byIndex)
+	}
+
+	if _, err := ReadPasswords(strings.NewReader(input), ReadOptions{CSV: true, Column: "secret"}); err == nil {
+		t.Error("ReadPasswords() should fail for a missing column")
+	}
+}
+
+func TestWriteHTML_EscapesPasswords(t *testing.T) {
+	report := services.AuditReport{
+		Total:          1,
+		Unique:         1,
+		WorstOffenders: []services.AuditOffender{{AuditRef: services.AuditRef{Line: 1}, Password: "<script>alert(1)</script>"}},
+	}
+
+	var out bytes.Buffer
+	if err := WriteHTML(&out, report); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	if strings.Contains(out.String(), "<script>") {
+		t.Error("WriteHTML() must escape passwords")
+	}
+}
+
+func TestReadExport(t *testing.T) {
+	tests := []struct {
+		format string
+		input  string
+		want   []services.AuditEntry
+	}{
+		{
+			format: FormatBitwarden,
+			input: `{"encrypted":false,"items":[
+				{"type":2,"name":"Note","notes":"secret"},
+				{"type":1,"name":"GitHub","login":{"username":"octocat","password":"hunter2","uris":[{"uri":"https://github.com"}]}}]}`,
+			want: []services.AuditEntry{{Line: 2, Title: "GitHub", URL: "https://github.com", Username: "octocat", Password: "hunter2"}},
+		},
+		{
+			format: FormatKeePassXML,
+			input: `<KeePassFile><Root><Group><Name>Root</Name><Group>
+				<Entry><String><Key>Title</Key><Value>Bank</Value></String><String><Key>UserName</Key><Value>jdoe</Value></String>
+					<String><Key>Password</Key><Value>current</Value></String>
+					<History><Entry><String><Key>Password</Key><Value>old</Value></String></Entry></History></Entry>
+				</Group></Group></Root></KeePassFile>`,
+			want: []services.AuditEntry{{Line: 1, Title: "Bank", Username: "jdoe", Password: "current"}},
+		},
+		{
+			format: FormatLastPass,
+			input:  "url,username,password,totp,extra,name,grouping,fav\nhttps://mail.example,jdoe,hunter2,,,Mail,,0\n",
+			want:   []services.AuditEntry{{Line: 2, Title: "Mail", URL: "https://mail.example", Username: "jdoe", Password: "hunter2"}},
+		},
+		{
+			format: FormatChrome,
+			input:  "\ufeffname,url,username,password\nForum,https://forum.example,jd,pa55word\nEmpty,https://x.example,jd,\n",
+			want:   []services.AuditEntry{{Line: 2, Title: "Forum", URL: "https://forum.example", Username: "jd", Password: "pa55word"}},
+		},
+		{
+			format: FormatKeePassCSV,
+			input:  "\"Group\",\"Title\",\"Username\",\"Password\",\"URL\",\"Notes\"\n\"Root\",\"Bank\",\"jdoe\",\"hunter2\",\"https://bank.example\",\"\"\n",
+			want:   []services.AuditEntry{{Line: 2, Title: "Bank", URL: "https://bank.example", Username: "jdoe", Password: "hunter2"}},
+		},
+		{
+			// KeePass 2.x names its columns differently from KeePassXC
+			format: FormatKeePassCSV,
+			input:  "\"Account\",\"Login Name\",\"Password\",\"Web Site\",\"Comments\"\n\"Bank\",\"jdoe\",\"hunter2\",\"https://bank.example\",\"\"\n",
+			want:   []services.AuditEntry{{Line: 2, Title: "Bank", URL: "https://bank.example", Username: "jdoe", Password: "hunter2"}},
+		},
+		{
+			format: Format1Password,
+			input:  "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\nVPN,https://vpn.example,jdoe,\"x,y\",,false,false,,\n",
+			want:   []services.AuditEntry{{Line: 2, Title: "VPN", URL: "https://vpn.example", Username: "jdoe", Password: "x,y"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			entries, err := ReadExport(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("ReadExport() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("ReadExport() = %+v, want %+v", entries, tt.want)
+			}
+			for i := range tt.want {
+				if entries[i] != tt.want[i] {
+					t.Errorf("entry %d = %+v, want %+v", i, entries[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadExport_Errors(t *testing.T) {
+	if _, err := ReadExport(strings.NewReader(`{"encrypted":true,"items":[]}`), FormatBitwarden); err == nil {
+		t.Error("ReadExport() should reject encrypted Bitwarden exports")
+	}
+	if _, err := ReadExport(strings.NewReader("a,b\n"), FormatFirefox); err == nil {
+		t.Error("ReadExport() should fail without a password column")
+	}
+	if _, err := ReadExport(strings.NewReader(""), "dashlane"); err == nil {
+		t.Error("ReadExport() should reject unknown formats")
+	}
+}
+
+func TestReadErrors_Classified(t *testing.T) {
+	var malformed *MalformedError
+	for name, err := range map[string]error{
+		"no password column": func() error {
+			_, err := ReadExport(strings.NewReader("\"Account\",\"Login Name\"\n"), FormatKeePassCSV)
+			return err
+		}(),
+		"invalid JSON": func() error {
+			_, err := ReadExport(strings.NewReader("{"), FormatBitwarden)
+			return err
+		}(),
+		"overlong line": func() error {
+			_, err := ReadPasswords(strings.NewReader(strings.Repeat("x", maxLineLength+1)), ReadOptions{})
+			return err
+		}(),
+	} {
+		if !errors.As(err, &malformed) {
+			t.Errorf("%s: error = %v, want a MalformedError", name, err)
+		}
+	}
+
+	// A failing reader is a read error, not malformed input
+	readFailure := errors.New("read failed")
+	for _, read := range []func() error{
+		func() error { _, err := ReadPasswords(iotest.ErrReader(readFailure), ReadOptions{}); return err },
+		func() error { _, err := ReadExport(iotest.ErrReader(readFailure), FormatKeePassCSV); return err },
+	} {
+		if err := read(); !errors.Is(err, readFailure) || errors.As(err, &malformed) {
+			t.Errorf("error = %v, want the read failure, not a MalformedError", err)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MalformedError is input that was read but isn't in the expected format, as
+// opposed to input that couldn't be read at all
+type MalformedError struct {
+	Err error
+}
+
+func (e *MalformedError) Error() string { return e.Err.Error() }
+
+func (e *MalformedError) Unwrap() error { return e.Err }
+
+// malformedf creates a MalformedError with a formatted message
+func malformedf(format string, args ...any) error {
+	return &MalformedError{Err: fmt.Errorf(format, args...)}
+}
+
+// classify marks parse failures as malformed input, leaving read failures as they are
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	var (
+		malformed  *MalformedError
+		csvErr     *csv.ParseError
+		jsonSyntax *json.SyntaxError
+		jsonType   *json.UnmarshalTypeError
+		xmlSyntax  *xml.SyntaxError
+	)
+	if errors.As(err, &malformed) {
+		return err
+	}
+	if errors.As(err, &csvErr) || errors.As(err, &jsonSyntax) || errors.As(err, &jsonType) || errors.As(err, &xmlSyntax) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, bufio.ErrTooLong) {
+		return &MalformedError{Err: err}
+	}
+	return err
+}
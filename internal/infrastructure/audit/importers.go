@@ -0,0 +1,240 @@
+package audit
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// Password manager export formats accepted by ReadExport
+const (
+	FormatBitwarden  = "bitwarden"
+	FormatKeePassXML = "keepass-xml"
+	FormatKeePassCSV = "keepass-csv"
+	Format1Password  = "1password"
+	FormatLastPass   = "lastpass"
+	FormatChrome     = "chrome"
+	FormatFirefox    = "firefox"
+)
+
+// exportColumns names the CSV header columns of an export format, each with
+// the alternative names different versions of the exporter use
+type exportColumns struct {
+	Title, URL, Username, Password []string
+}
+
+// csvExports maps CSV export formats to their header columns. Header names are
+// matched case-insensitively. KeePass CSV covers both KeePassXC and KeePass 2.x
+// ("Account", "Login Name", "Password", "Web Site").
+var csvExports = map[string]exportColumns{
+	FormatKeePassCSV: {Title: []string{"title", "account"}, URL: []string{"url", "web site"}, Username: []string{"username", "login name"}, Password: []string{"password"}},
+	Format1Password:  {Title: []string{"title"}, URL: []string{"url"}, Username: []string{"username"}, Password: []string{"password"}},
+	FormatLastPass:   {Title: []string{"name"}, URL: []string{"url"}, Username: []string{"username"}, Password: []string{"password"}},
+	FormatChrome:     {Title: []string{"name"}, URL: []string{"url"}, Username: []string{"username"}, Password: []string{"password"}},
+	FormatFirefox:    {URL: []string{"url"}, Username: []string{"username"}, Password: []string{"password"}},
+}
+
+// ExportFormats lists the supported password manager export formats
+func ExportFormats() []string {
+	formats := []string{FormatBitwarden, FormatKeePassXML}
+	for format := range csvExports {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// ReadExport reads login entries from a password manager export. Entry line
+// numbers are CSV line numbers, or the entry's position for JSON and XML.
+// Entries without a password, such as secure notes, are skipped. Input that
+// isn't a valid export fails with a MalformedError.
+func ReadExport(r io.Reader, format string) ([]services.AuditEntry, error) {
+	entries, err := readExport(r, format)
+	return entries, classify(err)
+}
+
+// readExport dispatches to the reader for format
+func readExport(r io.Reader, format string) ([]services.AuditEntry, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case FormatBitwarden:
+		return readBitwarden(r)
+	case FormatKeePassXML:
+		return readKeePassXML(r)
+	}
+
+	columns, ok := csvExports[format]
+	if !ok {
+		return nil, malformedf("unknown export format: %s (available: %s)", format, strings.Join(ExportFormats(), ", "))
+	}
+	return readExportCSV(r, format, columns)
+}
+
+// bitwardenExport is the subset of an unencrypted Bitwarden JSON export used
+// for auditing
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Items     []struct {
+		Name  string `json:"name"`
+		Login *struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+			URIs     []struct {
+				URI string `json:"uri"`
+			} `json:"uris"`
+		} `json:"login"`
+	} `json:"items"`
+}
+
+// readBitwarden reads an unencrypted Bitwarden JSON export
+func readBitwarden(r io.Reader) ([]services.AuditEntry, error) {
+	var export bitwardenExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("reading Bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		return nil, malformedf("Bitwarden export is encrypted; export as unencrypted JSON")
+	}
+
+	var entries []services.AuditEntry
+	for i, item := range export.Items {
+		if item.Login == nil || item.Login.Password == "" {
+			continue
+		}
+		entry := services.AuditEntry{
+			Line:     i + 1,
+			Password: item.Login.Password,
+			Title:    item.Name,
+			Username: item.Login.Username,
+		}
+		if len(item.Login.URIs) > 0 {
+			entry.URL = item.Login.URIs[0].URI
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// keePassEntry is an Entry element of a KeePass 2.x XML export
+type keePassEntry struct {
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+// readKeePassXML reads a KeePass 2.x XML export. Entries are found at any
+// group depth; the history kept inside each entry is ignored.
+func readKeePassXML(r io.Reader) ([]services.AuditEntry, error) {
+	decoder := xml.NewDecoder(r)
+
+	var entries []services.AuditEntry
+	position := 0
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading KeePass export: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "Entry" {
+			continue
+		}
+		var element keePassEntry
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return nil, fmt.Errorf("reading KeePass export: %w", err)
+		}
+		position++
+
+		entry := services.AuditEntry{Line: position}
+		for _, field := range element.Strings {
+			switch field.Key {
+			case "Title":
+				entry.Title = field.Value
+			case "URL":
+				entry.URL = field.Value
+			case "UserName":
+				entry.Username = field.Value
+			case "Password":
+				entry.Password = field.Value
+			}
+		}
+		if entry.Password != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// readExportCSV reads a CSV export whose first row names its columns
+func readExportCSV(r io.Reader, format string, columns exportColumns) ([]services.AuditEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s export header: %w", format, err)
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, seen := index[name]; !seen {
+			index[name] = i
+		}
+	}
+	// column finds the first of a column's names in the header
+	column := func(names []string) (int, bool) {
+		for _, name := range names {
+			if i, ok := index[name]; ok {
+				return i, true
+			}
+		}
+		return -1, false
+	}
+	passwordColumn, ok := column(columns.Password)
+	if !ok {
+		return nil, malformedf("%s export has no %q column", format, columns.Password[0])
+	}
+
+	field := func(record []string, names []string) string {
+		if i, ok := column(names); ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var entries []services.AuditEntry
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s export: %w", format, err)
+		}
+		if passwordColumn >= len(record) || record[passwordColumn] == "" {
+			continue
+		}
+
+		line, _ := reader.FieldPos(passwordColumn)
+		entries = append(entries, services.AuditEntry{
+			Line:     line,
+			Password: record[passwordColumn],
+			Title:    field(record, columns.Title),
+			URL:      field(record, columns.URL),
+			Username: field(record, columns.Username),
+		})
+	}
+	return entries, nil
+}
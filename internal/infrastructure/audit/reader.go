@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// maxLineLength bounds a single input line; longer lines are an error
+const maxLineLength = 1 << 20
+
+// ReadOptions describes the layout of audit input
+type ReadOptions struct {
+	CSV    bool   // input is CSV rather than one password per line
+	Column string // CSV column holding passwords: a header name or a 1-based index
+	Header bool   // the first CSV row is a header (implied when Column is a name)
+}
+
+// ReadPasswords reads audit entries from r, skipping empty passwords. Line
+// numbers refer to the input so findings can be traced back to it. Input
+// that can't be parsed fails with a MalformedError.
+func ReadPasswords(r io.Reader, opts ReadOptions) ([]services.AuditEntry, error) {
+	var entries []services.AuditEntry
+	var err error
+	if opts.CSV {
+		entries, err = readCSV(r, opts)
+	} else {
+		entries, err = readLines(r)
+	}
+	return entries, classify(err)
+}
+
+// readLines reads one password per line, tolerating CRLF line endings
+func readLines(r io.Reader) ([]services.AuditEntry, error) {
+	var entries []services.AuditEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	for line := 1; scanner.Scan(); line++ {
+		password := strings.TrimSuffix(scanner.Text(), "\r")
+		if password != "" {
+			entries = append(entries, services.AuditEntry{Line: line, Password: password})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading passwords: %w", err)
+	}
+	return entries, nil
+}
+
+// readCSV reads the password column of a CSV file
+func readCSV(r io.Reader, opts ReadOptions) ([]services.AuditEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	column := strings.TrimSpace(opts.Column)
+	if column == "" {
+		column = "1"
+	}
+	index, err := strconv.Atoi(column)
+	header := opts.Header
+	if err != nil {
+		index, header = -1, true
+	} else if index < 1 {
+		return nil, malformedf("CSV column index must be 1 or greater, got %d", index)
+	} else {
+		index--
+	}
+
+	var entries []services.AuditEntry
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading CSV: %w", err)
+		}
+
+		if first && header {
+			if index < 0 {
+				for i, name := range record {
+					if strings.EqualFold(strings.TrimSpace(name), column) {
+						index = i
+					}
+				}
+				if index < 0 {
+					return nil, malformedf("CSV header has no column named %q", column)
+				}
+			}
+			continue
+		}
+
+		if index >= len(record) || record[index] == "" {
+			continue
+		}
+		line, _ := reader.FieldPos(index)
+		entries = append(entries, services.AuditEntry{Line: line, Password: record[index]})
+	}
+	return entries, nil
+}
@@ -0,0 +1,101 @@
+package audit
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// WriteJSON writes the report as indented JSON
+func WriteJSON(w io.Writer, report services.AuditReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteHTML writes the report as a self-contained HTML page
+func WriteHTML(w io.Writer, report services.AuditReport) error {
+	return htmlReport.Execute(w, report)
+}
+
+// htmlReport renders an audit report; html/template escapes every value,
+// including passwords shown with --show-passwords
+var htmlReport = template.Must(template.New("audit").Funcs(template.FuncMap{
+	"percent": func(count, total int) float64 {
+		if total == 0 {
+			return 0
+		}
+		return float64(count) * 100 / float64(total)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>passgen audit report</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 0.3em 0.8em; text-align: left; border-bottom: 1px solid #ddd; }
+td.bar { width: 20em; }
+td.bar div { background: #4a7bd0; height: 0.9em; }
+code { font-size: 1.05em; }
+</style>
+</head>
+<body>
+<h1>🔐 Password Audit</h1>
+<p>{{.Total}} passwords audited, {{.Unique}} unique.</p>
+{{$total := .Total}}
+<h2>Strength</h2>
+<table>
+<tr><th>Strength</th><th>Count</th><th></th></tr>
+{{range .Strengths}}<tr><td>{{.Label}}</td><td>{{.Count}}</td><td class="bar"><div style="width: {{percent .Count $total | printf "%.1f"}}%"></div></td></tr>
+{{end}}</table>
+
+<h2>Guess Score</h2>
+<table>
+<tr><th>Score</th><th>Count</th><th></th></tr>
+{{range .Scores}}<tr><td>{{.Label}}/4</td><td>{{.Count}}</td><td class="bar"><div style="width: {{percent .Count $total | printf "%.1f"}}%"></div></td></tr>
+{{end}}</table>
+
+<h2>Length</h2>
+<table>
+<tr><th>Length</th><th>Count</th><th></th></tr>
+{{range .Lengths}}<tr><td>{{.Label}}</td><td>{{.Count}}</td><td class="bar"><div style="width: {{percent .Count $total | printf "%.1f"}}%"></div></td></tr>
+{{end}}</table>
+
+<h2>Patterns</h2>
+{{if .Patterns}}<table>
+<tr><th>Pattern</th><th>Passwords</th></tr>
+{{range .Patterns}}<tr><td>{{.Label}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>{{else}}<p>No common patterns found.</p>{{end}}
+
+<h2>Reuse</h2>
+{{if .Reused}}<table>
+<tr><th>Password</th><th>Uses</th><th>Entries</th></tr>
+{{range .Reused}}<tr><td><code>{{.Password}}</code></td><td>{{.Count}}</td><td>{{range $i, $entry := .Entries}}{{if $i}}, {{end}}{{$entry.Label}}{{end}}</td></tr>
+{{end}}</table>{{else}}<p>No password is used more than once.</p>{{end}}
+
+{{if .Similar}}<h2>Similar Passwords</h2>
+<table>
+<tr><th>Passwords</th><th>Entries</th></tr>
+{{range .Similar}}<tr><td>{{range $i, $p := .Passwords}}{{if $i}} ≈ {{end}}<code>{{$p}}</code>{{end}}</td><td>{{range $i, $entry := .Entries}}{{if $i}}, {{end}}{{$entry.Label}}{{end}}</td></tr>
+{{end}}</table>{{end}}
+{{if .SimilarSkipped}}<h2>Similar Passwords</h2>
+<p>Not checked: too many unique passwords to compare.</p>{{end}}
+
+<h2>Worst Offenders</h2>
+<table>
+<tr><th>Entry</th><th>Password</th><th>Strength</th><th>Score</th><th>Guesses</th><th>Patterns</th></tr>
+{{range .WorstOffenders}}<tr><td>{{.Label}}</td><td><code>{{.Password}}</code></td><td>{{.Strength}}</td><td>{{.Score}}/4</td><td>10<sup>{{printf "%.1f" .GuessesLog10}}</sup></td><td>{{range $i, $p := .Patterns}}{{if $i}}, {{end}}{{$p}}{{end}}</td></tr>
+{{end}}</table>
+
+{{if .Findings}}<h2>Entries Needing Attention</h2>
+<table>
+<tr><th>Entry</th><th>URL</th><th>Username</th><th>Strength</th><th>Issues</th></tr>
+{{range .Findings}}<tr><td>{{.Label}}</td><td>{{.URL}}</td><td>{{.Username}}</td><td>{{.Strength}}</td><td>{{range $i, $issue := .Issues}}{{if $i}}, {{end}}{{$issue}}{{end}}</td></tr>
+{{end}}</table>{{end}}
+</body>
+</html>
+`))
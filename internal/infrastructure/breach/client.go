@@ -0,0 +1,170 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// DefaultAPIURL is the public Pwned Passwords range API
+const DefaultAPIURL = "https://api.pwnedpasswords.com"
+
+// prefixLength is the number of hash characters sent to the range API; the
+// remaining suffix never leaves the machine
+const prefixLength = 5
+
+// maxRangeResponse bounds the size of a range response (a padded response is
+// well under 100 KB)
+const maxRangeResponse = 4 << 20
+
+// ClientOptions configures a range API client
+type ClientOptions struct {
+	Timeout  time.Duration // per-request timeout; zero means 10 seconds
+	Padding  bool          // ask the server to pad responses so their size leaks nothing
+	CacheDir string        // where range responses are cached; empty disables caching
+	CacheTTL time.Duration // how long a cached range stays fresh; zero disables caching
+}
+
+// Client checks passwords against a Pwned Passwords-compatible range API
+// using k-anonymity: only the first five hex characters of the SHA-1 are sent
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	options    ClientOptions
+}
+
+// NewClient creates a client for the range API rooted at baseURL
+func NewClient(baseURL string, options ClientOptions) *Client {
+	if options.Timeout <= 0 {
+		options.Timeout = 10 * time.Second
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: options.Timeout},
+		options:    options,
+	}
+}
+
+// DefaultCacheDir returns the range cache location in the user cache directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "passgen", "pwned-ranges"), nil
+}
+
+// Lookup reports how often password appears according to the range API
+func (c *Client) Lookup(password string) (entities.BreachResult, error) {
+	hash := HashPassword(password, entities.BreachHashSHA1)
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	body, err := c.fetchRange(prefix)
+	if err != nil {
+		return entities.BreachResult{}, err
+	}
+
+	count, err := findSuffix(body, suffix)
+	if err != nil {
+		return entities.BreachResult{}, fmt.Errorf("parsing range %s from %s: %w", prefix, c.baseURL, err)
+	}
+
+	return entities.BreachResult{
+		Source:   c.baseURL,
+		HashType: entities.BreachHashSHA1,
+		Found:    count > 0,
+		Count:    count,
+	}, nil
+}
+
+// fetchRange returns the range response for prefix, from the cache when fresh
+func (c *Client) fetchRange(prefix string) ([]byte, error) {
+	cachePath := c.cachePath(prefix)
+	if cachePath != "" {
+		if info, err := os.Stat(cachePath); err == nil && time.Since(info.ModTime()) < c.options.CacheTTL {
+			if body, err := os.ReadFile(cachePath); err == nil {
+				return body, nil
+			}
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "passgen")
+	if c.options.Padding {
+		req.Header.Set("Add-Padding", "true")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying breach API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("breach API %s returned %s", c.baseURL, resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRangeResponse))
+	if err != nil {
+		return nil, fmt.Errorf("reading breach API response: %w", err)
+	}
+
+	if cachePath != "" {
+		// A failed cache write only costs a repeat request next time
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err == nil {
+			os.WriteFile(cachePath, body, 0o600)
+		}
+	}
+
+	return body, nil
+}
+
+// cachePath returns where the range for prefix is cached, or "" when caching
+// is disabled. Ranges are keyed by endpoint so stand-ins never mix with the
+// real service.
+func (c *Client) cachePath(prefix string) string {
+	if c.options.CacheDir == "" || c.options.CacheTTL <= 0 {
+		return ""
+	}
+	endpoint := sha1.Sum([]byte(c.baseURL))
+	return filepath.Join(c.options.CacheDir, hex.EncodeToString(endpoint[:8]), prefix)
+}
+
+// findSuffix scans a "SUFFIX:COUNT" range response for suffix. Padding
+// entries carry a count of zero and so never report a match.
+func findSuffix(body []byte, suffix string) (int64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		idx := strings.IndexByte(line, ':')
+		if idx < 0 {
+			return 0, fmt.Errorf("malformed line %q", line)
+		}
+		if !strings.EqualFold(line[:idx], suffix) {
+			continue
+		}
+		count, err := strconv.ParseInt(strings.TrimSpace(line[idx+1:]), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("malformed count in line %q", line)
+		}
+		return count, nil
+	}
+	return 0, scanner.Err()
+}
@@ -0,0 +1,102 @@
+package breach
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// rangeServer stands in for the range API, serving the given password counts
+// plus zero-count padding entries when padding is requested
+func rangeServer(t *testing.T, counts map[string]int, requests *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		if len(prefix) != prefixLength {
+			t.Errorf("request path %q should carry a %d-character prefix", r.URL.Path, prefixLength)
+		}
+
+		for password, count := range counts {
+			hash := HashPassword(password, entities.BreachHashSHA1)
+			if hash[:prefixLength] == prefix {
+				fmt.Fprintf(w, "%s:%d\r\n", hash[prefixLength:], count)
+			}
+		}
+		if r.Header.Get("Add-Padding") == "true" {
+			fmt.Fprintf(w, "%s:0\r\n", strings.Repeat("0", 35))
+			fmt.Fprintf(w, "%s:0\r\n", strings.Repeat("F", 35))
+		}
+	}))
+}
+
+func TestClient_Lookup(t *testing.T) {
+	var requests int32
+	server := rangeServer(t, map[string]int{"password": 9545824}, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL, ClientOptions{Padding: true})
+
+	result, err := client.Lookup("password")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if !result.Found || result.Count != 9545824 {
+		t.Errorf("Lookup(password) = %+v, want 9545824 occurrences", result)
+	}
+
+	result, err = client.Lookup("kD8#mQ2$vL9!xR4@")
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if result.Found {
+		t.Errorf("Lookup() of unbreached password = %+v, want not found", result)
+	}
+}
+
+func TestClient_Cache(t *testing.T) {
+	var requests int32
+	server := rangeServer(t, map[string]int{"hunter2": 17043}, &requests)
+	defer server.Close()
+
+	client := NewClient(server.URL, ClientOptions{CacheDir: t.TempDir(), CacheTTL: time.Hour})
+	for i := 0; i < 3; i++ {
+		result, err := client.Lookup("hunter2")
+		if err != nil {
+			t.Fatalf("Lookup() error = %v", err)
+		}
+		if result.Count != 17043 {
+			t.Errorf("Lookup() count = %d, want 17043", result.Count)
+		}
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("server saw %d requests, want 1 with a warm cache", requests)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+
+	if _, err := NewClient(failing.URL, ClientOptions{}).Lookup("password"); err == nil {
+		t.Error("Lookup() should fail on a non-200 response")
+	}
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+	}))
+	defer slow.Close()
+
+	if _, err := NewClient(slow.URL, ClientOptions{Timeout: 20 * time.Millisecond}).Lookup("password"); err == nil {
+		t.Error("Lookup() should fail when the request times out")
+	}
+}
@@ -0,0 +1,223 @@
+package breach
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// Compact index layout: an 8-byte header (magic, version, hash type, digest
+// length) followed by fixed-size records of digest bytes and a big-endian
+// uint32 prevalence count, sorted by digest.
+const (
+	indexMagic   = "PGBI"
+	indexVersion = 1
+	headerSize   = 8
+	countSize    = 4
+)
+
+// maxLineLength bounds a line in the raw text dump ("HASH:COUNT")
+const maxLineLength = 128
+
+// Database looks passwords up in a locally stored Pwned Passwords corpus,
+// either the raw sorted text dump or a compact index built from it
+type Database struct {
+	file       *os.File
+	path       string
+	size       int64
+	hashType   entities.BreachHashType
+	indexed    bool
+	recordSize int64
+}
+
+// OpenDatabase opens a corpus file. Compact indexes are detected by their
+// header and carry their own hash type; for raw text dumps hashType says how
+// the file was hashed.
+func OpenDatabase(path string, hashType entities.BreachHashType) (*Database, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	db := &Database{file: file, path: path, size: info.Size(), hashType: hashType}
+
+	header := make([]byte, headerSize)
+	if n, _ := file.ReadAt(header, 0); n == headerSize && string(header[:4]) == indexMagic {
+		if header[4] != indexVersion {
+			file.Close()
+			return nil, fmt.Errorf("unsupported breach index version %d", header[4])
+		}
+		db.indexed = true
+		db.hashType = indexHashType(header[5])
+		db.recordSize = int64(header[6]) + countSize
+		if (db.size-headerSize)%db.recordSize != 0 {
+			file.Close()
+			return nil, fmt.Errorf("breach index %s is truncated", path)
+		}
+	}
+
+	return db, nil
+}
+
+// Close releases the underlying file
+func (db *Database) Close() error {
+	return db.file.Close()
+}
+
+// HashType returns the hash type the corpus is keyed by
+func (db *Database) HashType() entities.BreachHashType {
+	return db.hashType
+}
+
+// Lookup reports how often password appears in the corpus
+func (db *Database) Lookup(password string) (entities.BreachResult, error) {
+	hash := HashPassword(password, db.hashType)
+
+	var count int64
+	var err error
+	if db.indexed {
+		count, err = db.lookupIndex(hash)
+	} else {
+		count, err = db.lookupText(hash)
+	}
+	if err != nil {
+		return entities.BreachResult{}, err
+	}
+
+	return entities.BreachResult{
+		Source:   db.path,
+		HashType: db.hashType,
+		Found:    count > 0,
+		Count:    count,
+	}, nil
+}
+
+// lookupIndex binary-searches the fixed-size records of a compact index
+func (db *Database) lookupIndex(hash string) (int64, error) {
+	target, err := hex.DecodeString(hash)
+	if err != nil {
+		return 0, err
+	}
+
+	record := make([]byte, db.recordSize)
+	digestLen := int(db.recordSize) - countSize
+	lo, hi := int64(0), (db.size-headerSize)/db.recordSize
+
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, err := db.file.ReadAt(record, headerSize+mid*db.recordSize); err != nil {
+			return 0, err
+		}
+		switch bytes.Compare(record[:digestLen], target) {
+		case 0:
+			return int64(binary.BigEndian.Uint32(record[digestLen:])), nil
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, nil
+}
+
+// lookupText binary-searches the byte offsets of a sorted "HASH:COUNT" text
+// dump, resynchronising on the next line start after each probe
+func (db *Database) lookupText(hash string) (int64, error) {
+	lo, hi := int64(0), db.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		line, _, err := db.lineAtOrAfter(mid)
+		if err != nil {
+			return 0, err
+		}
+		if line == "" || lineHash(line) >= hash {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+
+	line, _, err := db.lineAtOrAfter(lo)
+	if err != nil || line == "" || lineHash(line) != hash {
+		return 0, err
+	}
+	return lineCount(line)
+}
+
+// lineAtOrAfter returns the first complete line starting at or after offset
+func (db *Database) lineAtOrAfter(offset int64) (string, int64, error) {
+	start := offset
+	buf := make([]byte, 2*maxLineLength)
+
+	if offset > 0 {
+		// Skip the remainder of the line containing offset-1
+		n, err := db.file.ReadAt(buf[:maxLineLength], offset-1)
+		if err != nil && err != io.EOF {
+			return "", 0, err
+		}
+		newline := bytes.IndexByte(buf[:n], '\n')
+		if newline < 0 {
+			return "", db.size, nil
+		}
+		start = offset + int64(newline)
+	}
+
+	if start >= db.size {
+		return "", db.size, nil
+	}
+	n, err := db.file.ReadAt(buf, start)
+	if err != nil && err != io.EOF {
+		return "", 0, err
+	}
+	line := buf[:n]
+	if newline := bytes.IndexByte(line, '\n'); newline >= 0 {
+		line = line[:newline]
+	}
+	return strings.TrimSpace(string(line)), start, nil
+}
+
+// lineHash extracts the uppercase hash from a "HASH:COUNT" line
+func lineHash(line string) string {
+	if idx := strings.IndexByte(line, ':'); idx >= 0 {
+		line = line[:idx]
+	}
+	return strings.ToUpper(line)
+}
+
+// lineCount extracts the prevalence count from a "HASH:COUNT" line
+func lineCount(line string) (int64, error) {
+	idx := strings.IndexByte(line, ':')
+	if idx < 0 {
+		return 1, nil
+	}
+	return strconv.ParseInt(strings.TrimSpace(line[idx+1:]), 10, 64)
+}
+
+// indexHashType decodes the hash type byte of an index header
+func indexHashType(b byte) entities.BreachHashType {
+	if b == 2 {
+		return entities.BreachHashNTLM
+	}
+	return entities.BreachHashSHA1
+}
+
+// indexHashTypeByte encodes a hash type for an index header
+func indexHashTypeByte(hashType entities.BreachHashType) byte {
+	if hashType == entities.BreachHashNTLM {
+		return 2
+	}
+	return 1
+}
@@ -0,0 +1,122 @@
+package breach
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+func TestHashPassword(t *testing.T) {
+	tests := []struct {
+		hashType entities.BreachHashType
+		want     string
+	}{
+		{entities.BreachHashSHA1, "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"},
+		{entities.BreachHashNTLM, "8846F7EAEE8FB117AD06BDD830B7586C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.hashType), func(t *testing.T) {
+			if got := HashPassword("password", tt.hashType); got != tt.want {
+				t.Errorf("HashPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// writeDump creates a sorted "HASH:COUNT" dump containing the given passwords
+// plus filler entries, returning its path
+func writeDump(t *testing.T, hashType entities.BreachHashType, counts map[string]int) string {
+	t.Helper()
+
+	var lines []string
+	for password, count := range counts {
+		lines = append(lines, fmt.Sprintf("%s:%d", HashPassword(password, hashType), count))
+	}
+	for i := 0; i < 500; i++ {
+		lines = append(lines, fmt.Sprintf("%s:%d", HashPassword(fmt.Sprintf("filler-%d", i), hashType), i+1))
+	}
+	sort.Strings(lines)
+
+	path := filepath.Join(t.TempDir(), "pwned.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\r\n")+"\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDatabase_Lookup(t *testing.T) {
+	counts := map[string]int{"password": 9545824, "hunter2": 17043, "Summer2024!": 311}
+
+	for _, hashType := range []entities.BreachHashType{entities.BreachHashSHA1, entities.BreachHashNTLM} {
+		textPath := writeDump(t, hashType, counts)
+
+		raw, err := os.ReadFile(textPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var index bytes.Buffer
+		records, err := BuildIndex(bytes.NewReader(raw), &index, hashType)
+		if err != nil {
+			t.Fatalf("BuildIndex() error = %v", err)
+		}
+		if records != int64(len(counts)+500) {
+			t.Errorf("BuildIndex() records = %d, want %d", records, len(counts)+500)
+		}
+		indexPath := filepath.Join(t.TempDir(), "pwned.idx")
+		if err := os.WriteFile(indexPath, index.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, path := range []string{textPath, indexPath} {
+			t.Run(string(hashType)+"/"+filepath.Ext(path), func(t *testing.T) {
+				// Raw text dumps are assumed SHA-1 unless told otherwise; indexes know their type
+				openAs := hashType
+				if path == indexPath {
+					openAs = entities.BreachHashSHA1
+				}
+				db, err := OpenDatabase(path, openAs)
+				if err != nil {
+					t.Fatalf("OpenDatabase() error = %v", err)
+				}
+				defer db.Close()
+
+				if db.HashType() != hashType {
+					t.Errorf("HashType() = %v, want %v", db.HashType(), hashType)
+				}
+				for password, count := range counts {
+					result, err := db.Lookup(password)
+					if err != nil {
+						t.Fatalf("Lookup(%q) error = %v", password, err)
+					}
+					if !result.Found || result.Count != int64(count) {
+						t.Errorf("Lookup(%q) = %+v, want count %d", password, result, count)
+					}
+				}
+
+				result, err := db.Lookup("kD8#mQ2$vL9!xR4@")
+				if err != nil {
+					t.Fatalf("Lookup() error = %v", err)
+				}
+				if result.Found {
+					t.Errorf("Lookup() of unbreached password = %+v, want not found", result)
+				}
+			})
+		}
+	}
+}
+
+func TestBuildIndex_RejectsUnsortedInput(t *testing.T) {
+	input := "FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF:1\n0000000000000000000000000000000000000000:2\n"
+
+	_, err := BuildIndex(strings.NewReader(input), &bytes.Buffer{}, entities.BreachHashSHA1)
+	if err == nil {
+		t.Error("BuildIndex() should reject unsorted input")
+	}
+}
@@ -0,0 +1,41 @@
+package breach
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"golang.org/x/crypto/md4"
+)
+
+// HashPassword returns the uppercase hex digest used by Pwned Passwords for
+// the given hash type
+func HashPassword(password string, hashType entities.BreachHashType) string {
+	var digest []byte
+	switch hashType {
+	case entities.BreachHashNTLM:
+		// NTLM is MD4 over the UTF-16LE encoding of the password
+		encoded := utf16.Encode([]rune(password))
+		buf := make([]byte, 0, len(encoded)*2)
+		for _, unit := range encoded {
+			buf = append(buf, byte(unit), byte(unit>>8))
+		}
+		h := md4.New()
+		h.Write(buf)
+		digest = h.Sum(nil)
+	default:
+		sum := sha1.Sum([]byte(password))
+		digest = sum[:]
+	}
+	return strings.ToUpper(hex.EncodeToString(digest))
+}
+
+// digestLength returns the raw digest size in bytes for a hash type
+func digestLength(hashType entities.BreachHashType) int {
+	if hashType == entities.BreachHashNTLM {
+		return md4.Size
+	}
+	return sha1.Size
+}
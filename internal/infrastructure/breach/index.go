@@ -0,0 +1,75 @@
+package breach
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// BuildIndex converts a sorted Pwned Passwords text dump ("HASH:COUNT" per
+// line) into the compact binary index read by OpenDatabase. It returns the
+// number of records written. Lines must be sorted by hash, as in the
+// "ordered by hash" downloads.
+func BuildIndex(r io.Reader, w io.Writer, hashType entities.BreachHashType) (int64, error) {
+	digestLen := digestLength(hashType)
+
+	out := bufio.NewWriterSize(w, 1<<20)
+	header := []byte{indexMagic[0], indexMagic[1], indexMagic[2], indexMagic[3],
+		indexVersion, indexHashTypeByte(hashType), byte(digestLen), 0}
+	if _, err := out.Write(header); err != nil {
+		return 0, err
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, maxLineLength), maxLineLength)
+
+	var records int64
+	var previous []byte
+	record := make([]byte, digestLen+countSize)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		digest, err := hex.DecodeString(lineHash(line))
+		if err != nil || len(digest) != digestLen {
+			return records, fmt.Errorf("line %d: invalid %s hash", lineNumber, hashType)
+		}
+		if previous != nil && bytes.Compare(digest, previous) <= 0 {
+			return records, fmt.Errorf("line %d: input is not sorted by hash", lineNumber)
+		}
+
+		count, err := lineCount(line)
+		if err != nil {
+			return records, fmt.Errorf("line %d: invalid count", lineNumber)
+		}
+		if count > math.MaxUint32 {
+			count = math.MaxUint32
+		}
+
+		copy(record, digest)
+		binary.BigEndian.PutUint32(record[digestLen:], uint32(count))
+		if _, err := out.Write(record); err != nil {
+			return records, err
+		}
+
+		previous = append(previous[:0], digest...)
+		records++
+	}
+	if err := scanner.Err(); err != nil {
+		return records, err
+	}
+
+	return records, out.Flush()
+}
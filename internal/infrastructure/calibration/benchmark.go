@@ -0,0 +1,137 @@
+package calibration
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Work factors for the key derivation functions, matching current OWASP
+// recommendations so the measured rates reflect well-configured systems
+const (
+	PBKDF2Iterations = 600000
+	ScryptN          = 32768
+	ScryptR          = 8
+	ScryptP          = 1
+)
+
+// Bounds on the bcrypt cost factors benchmarked. Each step doubles the work,
+// and one hash at cost 16 already takes seconds, so higher costs would keep a
+// run going for hours.
+const (
+	MinBcryptCost = 4
+	MaxBcryptCost = 16
+)
+
+// DefaultBcryptCosts are the bcrypt cost factors benchmarked by default
+var DefaultBcryptCosts = []int{10, 12}
+
+// Options controls a benchmark run
+type Options struct {
+	Duration    time.Duration // minimum time spent on each algorithm
+	BcryptCosts []int
+	Workers     int // parallel hashing goroutines; defaults to the CPU count
+}
+
+// hashFunc performs one password hash
+type hashFunc func(password, salt []byte)
+
+// Benchmark measures how many hashes per second this machine computes for
+// each supported algorithm, using all CPUs
+func Benchmark(opts Options) []entities.HashRate {
+	if opts.Duration <= 0 {
+		opts.Duration = time.Second
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.NumCPU()
+	}
+	if len(opts.BcryptCosts) == 0 {
+		opts.BcryptCosts = DefaultBcryptCosts
+	}
+
+	var rates []entities.HashRate
+	for _, cost := range opts.BcryptCosts {
+		cost := cost
+		rates = append(rates, entities.HashRate{
+			Algorithm: "bcrypt",
+			Cost:      cost,
+			HashesPerSecond: measure(opts, 1, func(password, _ []byte) {
+				_, _ = bcrypt.GenerateFromPassword(password, cost)
+			}),
+		})
+	}
+
+	rates = append(rates,
+		entities.HashRate{
+			Algorithm: "pbkdf2-sha256",
+			Cost:      PBKDF2Iterations,
+			HashesPerSecond: measure(opts, 1, func(password, salt []byte) {
+				pbkdf2.Key(password, salt, PBKDF2Iterations, 32, sha256.New)
+			}),
+		},
+		entities.HashRate{
+			Algorithm: "scrypt",
+			Cost:      ScryptN,
+			HashesPerSecond: measure(opts, 1, func(password, salt []byte) {
+				_, _ = scrypt.Key(password, salt, ScryptN, ScryptR, ScryptP, 32)
+			}),
+		},
+		entities.HashRate{
+			Algorithm: "sha256",
+			HashesPerSecond: measure(opts, fastHashBatch, func(password, _ []byte) {
+				sha256.Sum256(password)
+			}),
+		},
+		entities.HashRate{
+			Algorithm: "md5",
+			HashesPerSecond: measure(opts, fastHashBatch, func(password, _ []byte) {
+				md5.Sum(password)
+			}),
+		},
+	)
+
+	return rates
+}
+
+// fastHashBatch is how many fast hashes run between clock checks, so the
+// timing overhead does not dominate the measurement
+const fastHashBatch = 4096
+
+// measure runs hash on every worker, batch hashes at a time, until
+// opts.Duration has elapsed and at least one batch per worker has completed,
+// returning the aggregate rate
+func measure(opts Options, batch int, hash hashFunc) float64 {
+	var count int64
+	var wg sync.WaitGroup
+	start := time.Now()
+	deadline := start.Add(opts.Duration)
+
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			password := []byte("correct horse battery staple")
+			salt := []byte("passgen-calibration")
+			for {
+				for i := 0; i < batch; i++ {
+					hash(password, salt)
+				}
+				atomic.AddInt64(&count, int64(batch))
+				if time.Now().After(deadline) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return float64(count) / time.Since(start).Seconds()
+}
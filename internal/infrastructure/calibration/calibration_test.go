@@ -0,0 +1,76 @@
+package calibration
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+func TestStore_SaveLoad(t *testing.T) {
+	store, err := NewStore(filepath.Join(t.TempDir(), "nested", "calibration.json"))
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	// A path given explicitly has to exist
+	missing, err := store.Load()
+	if !errors.Is(err, os.ErrNotExist) || !strings.Contains(err.Error(), store.Path()) || missing != nil {
+		t.Fatalf("Load() on missing file = %v, %v; want an os.ErrNotExist error naming the path", missing, err)
+	}
+
+	saved := &entities.Calibration{
+		MeasuredAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Multiplier: 250,
+		Rates:      []entities.HashRate{{Algorithm: "bcrypt", Cost: 12, HashesPerSecond: 42.5}},
+	}
+	if err := store.Save(saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !loaded.MeasuredAt.Equal(saved.MeasuredAt) || loaded.Multiplier != saved.Multiplier {
+		t.Errorf("Load() = %+v, want %+v", loaded, saved)
+	}
+	if len(loaded.Rates) != 1 || loaded.Rates[0] != saved.Rates[0] {
+		t.Errorf("Load() rates = %+v, want %+v", loaded.Rates, saved.Rates)
+	}
+}
+
+func TestBenchmark(t *testing.T) {
+	rates := Benchmark(Options{Duration: time.Millisecond, BcryptCosts: []int{4}, Workers: 1})
+
+	want := []string{"bcrypt:4", "pbkdf2-sha256:600000", "scrypt:32768", "sha256", "md5"}
+	if len(rates) != len(want) {
+		t.Fatalf("Benchmark() returned %d rates, want %d", len(rates), len(want))
+	}
+	for i, rate := range rates {
+		if rate.Label() != want[i] {
+			t.Errorf("rate %d label = %v, want %v", i, rate.Label(), want[i])
+		}
+		if rate.HashesPerSecond <= 0 {
+			t.Errorf("%s hashes per second = %v, want > 0", rate.Label(), rate.HashesPerSecond)
+		}
+	}
+}
+
+func TestStore_LoadDefaultMissing(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+
+	store, err := NewStore("")
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+	if calibration, err := store.Load(); calibration != nil || err != nil {
+		t.Errorf("Load() with nothing calibrated = %v, %v; want nil, nil", calibration, err)
+	}
+}
@@ -0,0 +1,74 @@
+package calibration
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// Store persists calibration results as JSON
+type Store struct {
+	path     string
+	explicit bool // path was given rather than defaulted
+}
+
+// NewStore creates a Store at path, or at DefaultPath when path is empty
+func NewStore(path string) (*Store, error) {
+	if path != "" {
+		return &Store{path: path, explicit: true}, nil
+	}
+	defaultPath, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return &Store{path: defaultPath}, nil
+}
+
+// DefaultPath returns the calibration file location in the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "passgen", "calibration.json"), nil
+}
+
+// Path returns the file the store reads and writes
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the stored calibration. It returns nil without error when
+// nothing has been stored at the default path; a missing file at a path given
+// to NewStore is an os.ErrNotExist error naming the path.
+func (s *Store) Load() (*entities.Calibration, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) && !s.explicit {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var calibration entities.Calibration
+	if err := json.Unmarshal(data, &calibration); err != nil {
+		return nil, err
+	}
+	return &calibration, nil
+}
+
+// Save writes the calibration, creating the parent directory if needed
+func (s *Store) Save(calibration *entities.Calibration) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(calibration, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0o644)
+}
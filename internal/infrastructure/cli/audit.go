@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/application"
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/domain/services"
+	"github.com/kumarasakti/passgen/internal/infrastructure/audit"
+	"github.com/spf13/cobra"
+)
+
+// HandleAudit reads a batch of passwords and prints an aggregate report
+func (h *Handler) HandleAudit(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	csvInput, _ := cmd.Flags().GetBool("csv")
+	column, _ := cmd.Flags().GetString("column")
+	header, _ := cmd.Flags().GetBool("header")
+	top, _ := cmd.Flags().GetInt("top")
+	outputPath, _ := cmd.Flags().GetString("output")
+	importFormat, _ := cmd.Flags().GetString("import")
+
+	if format != "text" && format != "json" && format != "html" {
+		return entities.NewPasswordError(fmt.Sprintf("invalid format '%s'. Available: text, json, html", format))
+	}
+
+	var input io.Reader = os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening input: %w", err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	var entries []services.AuditEntry
+	var err error
+	if importFormat != "" {
+		entries, err = audit.ReadExport(input, importFormat)
+	} else {
+		entries, err = audit.ReadPasswords(input, audit.ReadOptions{CSV: csvInput, Column: column, Header: header})
+	}
+	var malformed *audit.MalformedError
+	if errors.As(err, &malformed) {
+		return usageError(err)
+	}
+	if err != nil {
+		return fmt.Errorf("reading input: %w", err)
+	}
+
+	bannedWords, err := h.loadBannedWords(cmd)
+	if err != nil {
+		return err
+	}
+	resp, err := h.passwordService.AuditPasswords(application.AuditPasswordsRequest{
+		Entries:     entries,
+		Top:         top,
+		Display:     passwordDisplay(cmd, "show-passwords"),
+		BannedWords: bannedWords,
+	})
+	if err != nil {
+		return fmt.Errorf("auditing passwords: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if outputPath != "" {
+		file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fmt.Errorf("creating report: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch format {
+	case "json":
+		err = audit.WriteJSON(out, resp.Report)
+	case "html":
+		err = audit.WriteHTML(out, resp.Report)
+	default:
+		_, err = io.WriteString(out, h.formatter.FormatAuditReport(resp.Report))
+	}
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// createAuditCommand creates the audit subcommand
+func (h *Handler) createAuditCommand() *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit [file]",
+		Short: "Audit many passwords at once and report aggregate weaknesses",
+		Long: `Read passwords from a file or stdin (one per line, a CSV column, or a password
+manager export) and report the strength distribution, common patterns, length
+histogram, reused and similar passwords, the worst offenders and, for exports,
+the entries needing attention. Reading from stdin keeps passwords out of the
+process table. Passwords are masked in the report unless --show-partial or
+--show-passwords is set.
+
+Examples:
+  passgen audit passwords.txt
+  passgen audit export.csv --csv --column password --format html --output report.html
+  passgen audit vault.json --import bitwarden
+  some-export-tool | passgen audit --format json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: h.HandleAudit,
+	}
+
+	auditCmd.Flags().String("format", "text", "Report format (text, json, html)")
+	auditCmd.Flags().StringP("output", "o", "", "Write the report to a file instead of stdout")
+	auditCmd.Flags().Bool("csv", false, "Read CSV input instead of one password per line")
+	auditCmd.Flags().String("column", "1", "CSV column holding passwords: a header name or 1-based index")
+	auditCmd.Flags().Bool("header", false, "Skip the first CSV row (implied when --column is a name)")
+	auditCmd.Flags().String("import", "", "Read a password manager export ("+strings.Join(audit.ExportFormats(), ", ")+")")
+	auditCmd.Flags().Int("top", 10, "Number of worst offenders to list")
+	auditCmd.Flags().Bool("show-passwords", false, "Show passwords in the report instead of masking them")
+	auditCmd.Flags().Bool("show-partial", false, "Show the first and last character of each password instead of a fixed mask")
+	addBannedFlags(auditCmd)
+
+	return auditCmd
+}
+
+// passwordDisplay returns how a report shows passwords: in full when the
+// fullFlag is set, partially with --show-partial, and masked otherwise
+func passwordDisplay(cmd *cobra.Command, fullFlag string) services.PasswordDisplay {
+	if full, _ := cmd.Flags().GetBool(fullFlag); full {
+		return services.DisplayFull
+	}
+	if partial, _ := cmd.Flags().GetBool("show-partial"); partial {
+		return services.DisplayPartial
+	}
+	return services.DisplayMasked
+}
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/spf13/cobra"
+)
+
+// addBannedFlags registers the banned word flags shared by generation and check
+func addBannedFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("banned-list", nil, "Files of context-specific banned words, one per line (company, products, city, teams)")
+	cmd.Flags().StringSlice("banned-words", nil, "Banned words given inline, comma-separated")
+	cmd.Flags().Int("banned-distance", 1, "Edit distance tolerated when matching banned words of 5+ characters (0 for exact matches only)")
+}
+
+// loadBannedWords builds a matcher from the banned word flags. It returns nil
+// when no banned words were given.
+func (h *Handler) loadBannedWords(cmd *cobra.Command) (*entities.BannedWordMatcher, error) {
+	paths, _ := cmd.Flags().GetStringSlice("banned-list")
+	inline, _ := cmd.Flags().GetStringSlice("banned-words")
+	distance, _ := cmd.Flags().GetInt("banned-distance")
+
+	var lists []entities.BannedWordList
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening banned list: %w", err)
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		list, err := entities.ParseBannedWordList(name, file)
+		file.Close()
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, list)
+	}
+	if len(inline) > 0 {
+		lists = append(lists, entities.NewBannedWordList("inline", inline))
+	}
+
+	if len(lists) == 0 {
+		return nil, nil
+	}
+	return entities.NewBannedWordMatcher(distance, lists...), nil
+}
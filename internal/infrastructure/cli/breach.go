@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/application"
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/infrastructure/breach"
+	"github.com/spf13/cobra"
+)
+
+// openBreachLookups opens the breach corpora selected on the check command.
+// The returned function closes them and is safe to call more than once.
+func (h *Handler) openBreachLookups(cmd *cobra.Command) ([]application.BreachLookup, func(), error) {
+	var lookups []application.BreachLookup
+	var closers []func() error
+
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+		closers = nil
+	}
+
+	if path, _ := cmd.Flags().GetString("breach-db"); path != "" {
+		hashName, _ := cmd.Flags().GetString("breach-hash")
+		hashType, err := entities.ParseBreachHashType(hashName)
+		if err != nil {
+			return nil, closeAll, err
+		}
+
+		db, err := breach.OpenDatabase(path, hashType)
+		if err != nil {
+			return nil, closeAll, fmt.Errorf("opening breach database: %w", err)
+		}
+		lookups = append(lookups, db)
+		closers = append(closers, db.Close)
+	}
+
+	if url, _ := cmd.Flags().GetString("breach-api"); url != "" {
+		timeout, _ := cmd.Flags().GetDuration("breach-timeout")
+		cacheTTL, _ := cmd.Flags().GetDuration("breach-cache-ttl")
+		noPadding, _ := cmd.Flags().GetBool("breach-no-padding")
+
+		options := breach.ClientOptions{Timeout: timeout, Padding: !noPadding, CacheTTL: cacheTTL}
+		if cacheTTL > 0 {
+			// Without a usable cache directory the check simply goes uncached
+			options.CacheDir, _ = breach.DefaultCacheDir()
+		}
+		lookups = append(lookups, breach.NewClient(url, options))
+	}
+
+	return lookups, closeAll, nil
+}
+
+// addBreachFlags registers the breach lookup flags on the check command
+func addBreachFlags(cmd *cobra.Command) {
+	cmd.Flags().String("breach-db", "", "Pwned Passwords file or index to check against (offline)")
+	cmd.Flags().String("breach-hash", "sha1", "Hash type of a raw --breach-db text file (sha1, ntlm)")
+	cmd.Flags().String("breach-api", "", "Pwned Passwords-compatible range API to query, e.g. "+breach.DefaultAPIURL)
+	cmd.Flags().Duration("breach-timeout", 10*time.Second, "Timeout for --breach-api requests")
+	cmd.Flags().Duration("breach-cache-ttl", 24*time.Hour, "How long --breach-api ranges are cached locally (0 disables the cache)")
+	cmd.Flags().Bool("breach-no-padding", false, "Don't request padded --breach-api responses")
+}
+
+// HandleBreachIndex converts a raw Pwned Passwords dump into a compact index
+func (h *Handler) HandleBreachIndex(cmd *cobra.Command, args []string) error {
+	hashName, _ := cmd.Flags().GetString("hash")
+	hashType, err := entities.ParseBreachHashType(hashName)
+	if err != nil {
+		return err
+	}
+
+	in, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening input: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.Create(args[1])
+	if err != nil {
+		return fmt.Errorf("creating index: %w", err)
+	}
+
+	records, err := breach.BuildIndex(bufio.NewReaderSize(in, 1<<20), out, hashType)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(args[1])
+		return fmt.Errorf("building index: %w", err)
+	}
+
+	fmt.Printf("✅ Indexed %d %s hashes into %s\n", records, hashType, args[1])
+	return nil
+}
+
+// createBreachCommand creates the breach subcommand and its children
+func (h *Handler) createBreachCommand() *cobra.Command {
+	breachCmd := &cobra.Command{
+		Use:   "breach",
+		Short: "Manage offline breached-password corpora",
+		Long: `Tools for checking passwords against a locally downloaded Pwned Passwords
+corpus, for environments where the online range API is unreachable.
+
+Examples:
+  passgen breach index pwned-passwords-sha1-ordered-by-hash-v8.txt pwned.idx
+  passgen check "hunter2" --breach-db pwned.idx`,
+	}
+
+	indexCmd := &cobra.Command{
+		Use:   "index [input] [output]",
+		Short: "Build a compact lookup index from a sorted Pwned Passwords text dump",
+		Args:  cobra.ExactArgs(2),
+		RunE:  h.HandleBreachIndex,
+	}
+	indexCmd.Flags().String("hash", "sha1", "Hash type of the input dump (sha1, ntlm)")
+
+	breachCmd.AddCommand(indexCmd)
+	return breachCmd
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/spf13/cobra"
+)
+
+// addCharsetFlags registers the flags that add character classes to generation
+func addCharsetFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArray("charset-add", nil, fmt.Sprintf(
+		"Add a character class: a built-in set (%s) or name=characters (repeatable)",
+		strings.Join(entities.BuiltinCharacterClassNames(), ", ")))
+	cmd.Flags().String("charset-file", "", "File of character classes, one name or name=characters per line")
+}
+
+// loadCharacterClasses reads the character class flags. Characters dropped
+// from a class are reported on stderr so stdout stays limited to the passwords.
+func (h *Handler) loadCharacterClasses(cmd *cobra.Command) ([]entities.CharacterClass, error) {
+	specs, _ := cmd.Flags().GetStringArray("charset-add")
+	path, _ := cmd.Flags().GetString("charset-file")
+
+	var classes []entities.CharacterClass
+	for _, spec := range specs {
+		class, err := entities.ParseCharacterClass(spec)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, class)
+	}
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("opening charset file: %w", err)
+		}
+		fromFile, err := entities.ParseCharacterClasses(file)
+		file.Close()
+		if err != nil {
+			return nil, usageError(fmt.Errorf("%s: %w", path, err))
+		}
+		classes = append(classes, fromFile...)
+	}
+
+	for _, class := range classes {
+		if len(class.Excluded) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: character class %s leaves out %q (changed by Unicode normalization or not a standalone character)\n",
+				class.Name, string(class.Excluded))
+		}
+	}
+	return classes, nil
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+	"github.com/kumarasakti/passgen/internal/infrastructure/estimators"
+	"github.com/spf13/cobra"
+)
+
+// estimatorFlagUsage describes --estimator for every command that takes it
+const estimatorFlagUsage = "Strength estimators to rate with, comma-separated (checklist, entropy, zxcvbn, pcfg, or one defined in the user config directory)"
+
+// addEstimatorFlag registers the flags selecting strength estimators and how
+// their ratings are combined
+func addEstimatorFlag(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("estimator", nil, estimatorFlagUsage)
+	cmd.Flags().String("aggregate", services.AggregateMin, "How to combine several estimators' ratings (min, max)")
+	cmd.Flags().String("model", "", "Guess model for the pcfg estimator, as written by 'model train --output' (default: user config directory)")
+}
+
+// selectedEstimators returns the estimators named by --estimator, first
+// registering the user's guess model and external estimators so they can be
+// selected
+func (h *Handler) selectedEstimators(cmd *cobra.Command) ([]string, error) {
+	names, _ := cmd.Flags().GetStringSlice("estimator")
+	modelPath, _ := cmd.Flags().GetString("model")
+	if modelPath != "" && !selectsEstimator(names, services.EstimatorPCFG) {
+		return nil, usageError(errors.New("--model applies to the pcfg estimator; add --estimator pcfg"))
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	if selectsEstimator(names, services.EstimatorPCFG) {
+		if err := h.loadGuessModel(modelPath); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := estimators.DefaultDir()
+	if err != nil {
+		return names, nil
+	}
+	external, err := estimators.LoadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("loading estimators: %w", err)
+	}
+	if err := h.passwordService.RegisterEstimators(external...); err != nil {
+		return nil, fmt.Errorf("loading estimators: %w", err)
+	}
+	return names, nil
+}
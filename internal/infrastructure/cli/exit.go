@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes, kept stable for scripts and CI jobs that branch on the result
+const (
+	ExitOK              = 0
+	ExitFailure         = 1 // an internal failure, such as an unreadable file or the random source
+	ExitUsage           = 2 // invalid arguments, flags or input files
+	ExitPolicyViolation = 3 // a standard, profile or banned-word list was not satisfied
+	ExitWeakPassword    = 4 // the password is rated below --min-strength
+	ExitBreached        = 5 // the password appears in a breach corpus
+)
+
+// exitCodes maps the kinds of domain errors to exit codes
+var exitCodes = map[entities.ErrorKind]int{
+	entities.KindInvalidInput:    ExitUsage,
+	entities.KindPolicyViolation: ExitPolicyViolation,
+	entities.KindWeakPassword:    ExitWeakPassword,
+	entities.KindBreached:        ExitBreached,
+	entities.KindInternal:        ExitFailure,
+}
+
+// ExitError is a command failure carrying the exit code it calls for
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit code for an error returned by a command:
+// an ExitError's own code, the code for a domain error's kind, or ExitFailure
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return exitCodes[entities.ErrorKindOf(err)]
+}
+
+// usageError marks err as a usage error
+func usageError(err error) error {
+	return &ExitError{Code: ExitUsage, Err: err}
+}
+
+// markUsageErrors makes cobra's flag and argument validation errors, for cmd
+// and all its subcommands, exit with ExitUsage
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return usageError(err)
+	})
+	var mark func(*cobra.Command)
+	mark = func(c *cobra.Command) {
+		if validate := c.Args; validate != nil {
+			c.Args = func(c *cobra.Command, args []string) error {
+				if err := validate(c, args); err != nil {
+					return usageError(err)
+				}
+				return nil
+			}
+		}
+		for _, sub := range c.Commands() {
+			mark(sub)
+		}
+	}
+	mark(cmd)
+}
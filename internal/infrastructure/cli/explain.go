@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kumarasakti/passgen/internal/application"
+	"github.com/spf13/cobra"
+)
+
+// HandleExplain prints the factors behind a password's ratings next to a map
+// of the characters they concern
+func (h *Handler) HandleExplain(cmd *cobra.Command, args []string) error {
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	aggregation, _ := cmd.Flags().GetString("aggregate")
+
+	bannedWords, err := h.loadBannedWords(cmd)
+	if err != nil {
+		return err
+	}
+	estimatorNames, err := h.selectedEstimators(cmd)
+	if err != nil {
+		return err
+	}
+	password, err := h.readPassword(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.passwordService.ExplainPassword(application.ExplainPasswordRequest{
+		Password:    password,
+		BannedWords: bannedWords,
+		UserContext: userContextFromFlags(cmd),
+		Estimators:  estimatorNames,
+		Aggregation: aggregation,
+	})
+	if err != nil {
+		return fmt.Errorf("explaining password: %w", err)
+	}
+
+	fmt.Print(h.formatter.FormatExplanation(resp, !noColor && colorSupported()))
+	return nil
+}
+
+// colorSupported reports whether stdout is a terminal and the user hasn't
+// opted out of color through the NO_COLOR convention
+func colorSupported() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// createExplainCommand creates the explain subcommand
+func (h *Handler) createExplainCommand() *cobra.Command {
+	explainCmd := &cobra.Command{
+		Use:   "explain [password]",
+		Short: "Show why a password gets its strength rating",
+		Long: `Break a password's ratings down into the factors behind them. A character map
+marks which characters were recognized as dictionary words, keyboard walks,
+sequences, repeats, mirrored segments, dates, banned words or lookalikes,
+each pattern found is listed with its characters underlined, and tables list
+every checklist point and entropy adjustment with the characters it is based
+on.
+
+` + passwordInputHelp + `
+
+Examples:
+  passgen explain
+  passgen explain --stdin --banned-words acme --no-color < secret.txt
+  passgen explain --estimator zxcvbn
+  passgen explain --estimator zxcvbn,pcfg --aggregate max`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: h.HandleExplain,
+	}
+
+	explainCmd.Flags().Bool("no-color", false, "Mark characters with letters only, without ANSI colors")
+	addPasswordInputFlags(explainCmd)
+	addBannedFlags(explainCmd)
+	addUserContextFlags(explainCmd)
+	addEstimatorFlag(explainCmd)
+
+	return explainCmd
+}
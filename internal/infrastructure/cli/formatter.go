@@ -2,9 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/kumarasakti/passgen/internal/application"
+	"github.com/kumarasakti/passgen/internal/domain/entities"
 	"github.com/kumarasakti/passgen/internal/domain/services"
 )
 
@@ -16,8 +18,10 @@ func NewFormatter() *Formatter {
 	return &Formatter{}
 }
 
-// FormatPasswordGeneration formats password generation results for display
-func (f *Formatter) FormatPasswordGeneration(analyses []services.PasswordAnalysis, excludeSimilar bool) string {
+// FormatPasswordGeneration formats password generation results for display,
+// with each password's crack times under the selected attack scenarios when
+// showCrackTimes is set
+func (f *Formatter) FormatPasswordGeneration(analyses []services.PasswordAnalysis, excludeSimilar, showCrackTimes bool) string {
 	var output strings.Builder
 
 	for i, analysis := range analyses {
@@ -30,34 +34,36 @@ func (f *Formatter) FormatPasswordGeneration(analyses []services.PasswordAnalysi
 
 		// Create a box around the password for maximum visibility
 		password := analysis.Password.Value
-		output.WriteString("┌" + strings.Repeat("─", len(password)+2) + "┐\n")
+		output.WriteString("┌" + strings.Repeat("─", entities.DisplayWidth(password)+2) + "┐\n")
 		output.WriteString(fmt.Sprintf("│ %s │\n", password))
-		output.WriteString("└" + strings.Repeat("─", len(password)+2) + "┘\n\n")
+		output.WriteString("└" + strings.Repeat("─", entities.DisplayWidth(password)+2) + "┘\n\n")
 
 		// Brief one-line summary
 		output.WriteString(fmt.Sprintf("📊 Length: %d | Character types: %s | Strength: %s %s\n",
 			analysis.Password.Length,
 			strings.Join(analysis.CharacterTypes, ", "),
 			analysis.Strength.String(),
-			analysis.StrengthEmoji))
+			strengthEmoji(analysis.Strength)))
 
 		// Optional: Show detailed analysis only if single password
 		if len(analyses) == 1 {
-			output.WriteString(fmt.Sprintf("\n🔒 Security info: %.1f bits entropy, cracks in %s\n",
-				analysis.Entropy, analysis.TimeToCrack))
+			output.WriteString(fmt.Sprintf("\n🔒 Security info: %.1f bits entropy", analysis.Entropy))
+			if crackTime, ok := summaryCrackTime(analysis.CrackTimes); ok {
+				output.WriteString(", cracks in " + crackTime)
+			}
+			output.WriteString("\n")
 
 			// Tips if password is weak
-			if len(analysis.Tips) > 0 {
+			if tips := analysisTips(analysis); len(tips) > 0 {
 				output.WriteString("\n💡 Suggestions:\n")
-				for _, tip := range analysis.Tips {
+				for _, tip := range tips {
 					output.WriteString(fmt.Sprintf("   • %s\n", tip))
 				}
 			}
+		}
 
-			// Add the sarcastic comment for fun
-			if analysis.Celebration != "" {
-				output.WriteString(fmt.Sprintf("\n💬 %s\n", analysis.Celebration))
-			}
+		if showCrackTimes {
+			output.WriteString(f.FormatCrackTimes(analysis.CrackTimes))
 		}
 
 		// Add separator for multiple passwords
@@ -71,7 +77,738 @@ func (f *Formatter) FormatPasswordGeneration(analyses []services.PasswordAnalysi
 
 // FormatPasswordStrengthCheck formats password strength check results
 func (f *Formatter) FormatPasswordStrengthCheck(result services.StrengthCheckResult) string {
-	return result.FormattedResult
+	var output strings.Builder
+	password := result.Password
+
+	output.WriteString("🔍 Password Analysis Results:\n")
+	output.WriteString(fmt.Sprintf("Strength: %s %s (Score: %d/%d)\n", result.Strength.String(), strengthEmoji(result.Strength), result.Score, result.MaxScore))
+	length := fmt.Sprintf("%d characters", password.Length)
+	if password.Graphemes != password.Length {
+		length += fmt.Sprintf(" (%d as displayed)", password.Graphemes)
+	}
+	output.WriteString(fmt.Sprintf("Length: %s | Character types: %s\n", length, strings.Join(password.GetCharacterTypes(), ", ")))
+	if result.Rejected() {
+		output.WriteString("\nRejected: never use this password\n")
+	} else {
+		output.WriteString(fmt.Sprintf("\n%s\n", strengthSummaries[result.Strength]))
+	}
+
+	if suggestions := checkSuggestions(result); len(suggestions) > 0 {
+		output.WriteString("\n💡 Actionable Suggestions:\n")
+		for _, suggestion := range suggestions {
+			output.WriteString(fmt.Sprintf("• %s\n", suggestion))
+		}
+		output.WriteString("\nPro tip: Try 'passgen --secure -l 16' for a password that actually means business! 🚀\n")
+	}
+
+	return output.String()
+}
+
+// strengthEmojis badge each strength in summaries
+var strengthEmojis = map[entities.PasswordStrength]string{
+	entities.VeryWeak:        "🚨",
+	entities.Weak:            "😰",
+	entities.Medium:          "⚡",
+	entities.Strong:          "💯",
+	entities.VeryStrong:      "💪",
+	entities.ExtremelyStrong: "🔥",
+}
+
+// strengthEmoji returns the badge shown next to a strength
+func strengthEmoji(strength entities.PasswordStrength) string {
+	return strengthEmojis[strength]
+}
+
+// strengthSummaries say what each strength is good for
+var strengthSummaries = map[entities.PasswordStrength]string{
+	entities.VeryWeak:        "Not recommended for any security purposes",
+	entities.Weak:            "Suitable only for low-security uses",
+	entities.Medium:          "Adequate for most general purposes",
+	entities.Strong:          "Great for securing important accounts",
+	entities.VeryStrong:      "Exceeds security standards for high-value accounts",
+	entities.ExtremelyStrong: "Quantum-resistant for the foreseeable future",
+}
+
+// checklistSuggestions say how to earn a checklist point the password missed
+var checklistSuggestions = map[string]string{
+	services.FactorLength:    "Password should be at least 8 characters long",
+	services.FactorLowercase: "Add lowercase letters",
+	services.FactorUppercase: "Add uppercase letters",
+	services.FactorNumbers:   "Add numbers",
+	services.FactorSymbols:   "Add special characters",
+}
+
+// checkSuggestions lists what to change about a checked password: the reasons
+// it was rejected, resemblances to the account holder with their severity,
+// the checklist points it missed and any lookalike characters
+func checkSuggestions(result services.StrengthCheckResult) []string {
+	var suggestions []string
+	if result.Breached() {
+		var total int64
+		for _, breach := range result.Breaches {
+			total += breach.Count
+		}
+		suggestions = append(suggestions, fmt.Sprintf("Password appears %d times in known data breaches - never use it", total))
+	}
+	for _, factor := range result.Factors {
+		if factor.Name == services.FactorBannedWord {
+			suggestions = append(suggestions, "Remove "+factor.Rationale)
+		}
+	}
+	for _, finding := range result.ContextFindings {
+		suggestions = append(suggestions, fmt.Sprintf("[%s] %s", finding.Severity, finding.Description()))
+	}
+	for _, factor := range result.Factors {
+		if suggestion, ok := checklistSuggestions[factor.Name]; ok && factor.Contribution == 0 {
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+	return append(suggestions, confusableWarnings(result.Factors)...)
+}
+
+// analysisTips suggests how to strengthen a generated or analyzed password
+func analysisTips(analysis services.PasswordAnalysis) []string {
+	var tips []string
+	switch analysis.Strength {
+	case entities.Medium:
+		if analysis.Password.Length < 12 {
+			tips = append(tips, "Consider using 12+ characters for better security")
+		}
+		if len(analysis.CharacterTypes) < 3 {
+			tips = append(tips, "Add more character types (symbols, numbers) for stronger security")
+		}
+	case entities.Weak:
+		tips = append(tips, "Use at least 12 characters", "Include uppercase, lowercase, numbers, and symbols",
+			"Try `passgen --secure` for maximum protection!")
+	case entities.VeryWeak:
+		tips = append(tips, "Use at least 12 characters", "Include multiple character types",
+			"Try `passgen --secure -l 16` for excellent security!")
+	}
+	for _, word := range analysis.RecognizedWords {
+		tips = append(tips, fmt.Sprintf("'%s' is the dictionary word '%s'; attackers try l33t and case variants of words first", word.Token, word.MatchedWord))
+	}
+	return append(tips, confusableWarnings(analysis.Factors)...)
+}
+
+// confusableWarnings describes the mixed scripts and lookalike characters
+// among factors, which show up as "wrong password" errors when a password is
+// read off a screen or retyped on another keyboard
+func confusableWarnings(factors []services.ScoringFactor) []string {
+	var warnings, lookalikes []string
+	for _, factor := range factors {
+		switch factor.Name {
+		case services.FactorMixedScript:
+			warnings = append(warnings, factor.Rationale+"; lookalikes from different scripts are easy to retype wrongly")
+		case services.FactorConfusable:
+			lookalikes = append(lookalikes, factor.Rationale)
+		}
+	}
+	if len(lookalikes) > 0 {
+		warnings = append(warnings, "Contains visually identical characters: "+strings.Join(lookalikes, ", "))
+	}
+	return warnings
+}
+
+// summaryCrackTime picks the estimate used for one-line summaries: the
+// default offline fast-hash scenario or, when --attack chose others, the
+// fastest of those, named so the reader knows which attack it assumes
+func summaryCrackTime(crackTimes []services.CrackTimeEstimate) (string, bool) {
+	if len(crackTimes) == 0 {
+		return "", false
+	}
+	fastest := crackTimes[0]
+	for _, crackTime := range crackTimes {
+		if crackTime.Scenario.Name == entities.AttackOfflineFast {
+			return crackTime.Duration.String(), true
+		}
+		if crackTime.Scenario.GuessesPerSecond > fastest.Scenario.GuessesPerSecond {
+			fastest = crackTime
+		}
+	}
+	return fmt.Sprintf("%s (%s)", fastest.Duration.String(), fastest.Scenario.Name), true
+}
+
+// mapMark is how the character map shows one kind of character
+type mapMark struct {
+	symbol rune
+	color  string // ANSI SGR parameters
+	label  string
+}
+
+// Character map marks, in legend order
+var (
+	markWord      = mapMark{'W', "31", "dictionary word"}
+	markKeyboard  = mapMark{'K', "33", "keyboard walk"}
+	markSequence  = mapMark{'S', "33", "sequence"}
+	markRepeat    = mapMark{'R', "33", "repeat"}
+	markMirror    = mapMark{'M', "33", "mirrored"}
+	markDate      = mapMark{'D', "33", "date, year or number"}
+	markRandom    = mapMark{'.', "32", "random"}
+	markPersonal  = mapMark{'!', "1;31", "banned word or account detail"}
+	markLookalike = mapMark{'~', "35", "lookalike"}
+	mapLegend     = []mapMark{markWord, markKeyboard, markSequence, markRepeat, markMirror, markDate, markRandom, markPersonal, markLookalike}
+	// markSpan and markBlank underline one finding's characters
+	markSpan  = mapMark{'^', "1;33", ""}
+	markBlank = mapMark{' ', "0", ""}
+)
+
+// patternMarks maps guess estimator patterns to character map marks
+var patternMarks = map[string]mapMark{
+	services.PatternDictionary: markWord,
+	services.PatternSpatial:    markKeyboard,
+	services.PatternSequence:   markSequence,
+	services.PatternRepeat:     markRepeat,
+	services.PatternDate:       markDate,
+	services.PatternRegex:      markDate,
+}
+
+// detectorMarks maps pattern detector findings to character map marks;
+// sequential_ findings are marked as sequences
+var detectorMarks = map[string]mapMark{
+	"repeating_chars":  markRepeat,
+	"repeated_block":   markRepeat,
+	"mirrored":         markMirror,
+	"keyboard_pattern": markKeyboard,
+	"common_word":      markWord,
+	"banned_word":      markPersonal,
+	"date_pattern":     markDate,
+	"year_pattern":     markDate,
+	"phone_pattern":    markDate,
+	"postcode_pattern": markDate,
+}
+
+// detectorMark returns the mark for a pattern detector finding
+func detectorMark(pattern entities.PasswordPattern) (mapMark, bool) {
+	if strings.HasPrefix(pattern.Type, "sequential_") {
+		return markSequence, true
+	}
+	m, ok := detectorMarks[pattern.Type]
+	return m, ok
+}
+
+// FormatPatterns lists the patterns found in a password, each with its
+// characters underlined
+func (f *Formatter) FormatPatterns(password entities.Password, patterns []entities.PasswordPattern) string {
+	if len(patterns) == 0 {
+		return ""
+	}
+	var output strings.Builder
+
+	output.WriteString("\n🧩 Patterns:\n")
+	for _, pattern := range patterns {
+		output.WriteString(fmt.Sprintf("   • [%s] %s\n", pattern.Severity, pattern.Description))
+		marks := make([]mapMark, password.Length)
+		for i := range marks {
+			marks[i] = markBlank
+			if i >= pattern.Start && i <= pattern.End {
+				marks[i] = markSpan
+			}
+		}
+		var highlight strings.Builder
+		f.writeCharacterMap(&highlight, password.Value, marks, false)
+		for _, line := range strings.Split(strings.TrimRight(highlight.String(), "\n"), "\n") {
+			output.WriteString("  " + strings.TrimRight(line, " ") + "\n")
+		}
+	}
+
+	return output.String()
+}
+
+// FormatExplanation formats the factors behind a password's ratings, led by
+// a map marking what each character was recognized as
+func (f *Formatter) FormatExplanation(resp application.ExplainPasswordResponse, color bool) string {
+	var output strings.Builder
+	password := resp.Result.Password
+
+	output.WriteString("🔎 Character map:\n")
+	f.writeCharacterMap(&output, password.Value, f.characterMarks(resp), color)
+	var legend []string
+	for _, mark := range mapLegend {
+		legend = append(legend, string(mark.symbol)+" "+mark.label)
+	}
+	output.WriteString("   " + strings.Join(legend, " · ") + "\n")
+	output.WriteString(f.FormatPatterns(password, resp.Patterns))
+
+	result := resp.Result
+	output.WriteString(fmt.Sprintf("\n📋 Checklist: %s %s (Score: %d/%d)\n", result.Strength.String(), strengthEmoji(result.Strength), result.Score, result.MaxScore))
+	f.writeFactorTable(&output, result.Factors, password.Length, "Points", "%+.0f")
+
+	analysis := resp.Analysis
+	output.WriteString(fmt.Sprintf("\n🎲 Entropy: %.1f bits for the character types present (%s %s)\n", analysis.Entropy, analysis.Strength.String(), strengthEmoji(analysis.Strength)))
+	f.writeFactorTable(&output, analysis.Factors, password.Length, "Bits", "%+.1f")
+
+	output.WriteString(f.FormatGuessEstimate(resp.Estimate))
+
+	for _, estimate := range resp.Estimates {
+		output.WriteString(fmt.Sprintf("\n📏 %s: %s (%s)\n", estimate.Estimator, estimate.Strength.String(), f.describeEstimateScore(estimate)))
+		f.writeFactorTable(&output, estimate.Factors, password.Length, "Amount", "%+.1f")
+	}
+	if len(resp.Estimates) > 1 {
+		output.WriteString(fmt.Sprintf("\n📏 Estimators (combined by %s): %s\n", resp.Aggregation, resp.Combined.String()))
+	}
+	return output.String()
+}
+
+// FormatEstimates formats each estimator's rating and their combined rating
+func (f *Formatter) FormatEstimates(estimates []services.StrengthEstimate, combined entities.PasswordStrength, aggregation string) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("\n📏 Estimators (combined by %s): %s\n", aggregation, combined.String()))
+	for _, estimate := range estimates {
+		output.WriteString(fmt.Sprintf("   • %-12s %-17s %s\n", estimate.Estimator, estimate.Strength.String(), f.describeEstimateScore(estimate)))
+	}
+
+	return output.String()
+}
+
+// describeEstimateScore shows an estimate's score in the estimator's unit
+func (f *Formatter) describeEstimateScore(estimate services.StrengthEstimate) string {
+	if estimate.MaxScore > 0 {
+		return fmt.Sprintf("%g/%g %s", estimate.Score, estimate.MaxScore, estimate.Unit)
+	}
+	return strings.TrimSpace(fmt.Sprintf("%.1f %s", estimate.Score, estimate.Unit))
+}
+
+// characterMarks decides how each character of the password is marked: by
+// the pattern the guess estimator matched it as, overridden by the pattern
+// detector's findings and then by banned words, account details and
+// lookalikes
+func (f *Formatter) characterMarks(resp application.ExplainPasswordResponse) []mapMark {
+	marks := make([]mapMark, resp.Result.Password.Length)
+	for i := range marks {
+		marks[i] = markRandom
+	}
+	mark := func(start, end int, m mapMark) {
+		for i := start; i <= end && i < len(marks); i++ {
+			if i >= 0 {
+				marks[i] = m
+			}
+		}
+	}
+
+	for _, match := range resp.Estimate.Sequence {
+		if m, ok := patternMarks[match.Pattern]; ok {
+			mark(match.Start, match.End, m)
+		}
+	}
+	for _, pattern := range resp.Patterns {
+		if m, ok := detectorMark(pattern); ok {
+			mark(pattern.Start, pattern.End, m)
+		}
+	}
+	for _, factor := range resp.Result.Factors {
+		switch factor.Name {
+		case services.FactorBannedWord, services.FactorUserContext:
+			mark(factor.Start, factor.End, markPersonal)
+		case services.FactorConfusable:
+			mark(factor.Start, factor.End, markLookalike)
+		}
+	}
+	return marks
+}
+
+// writeCharacterMap writes the password above a row of marks, one per
+// character, padding marks under wide characters so the rows line up
+func (f *Formatter) writeCharacterMap(output *strings.Builder, password string, marks []mapMark, color bool) {
+	paint := func(text string, m mapMark) string {
+		if !color {
+			return text
+		}
+		return "\x1b[" + m.color + "m" + text + "\x1b[0m"
+	}
+
+	var chars, symbols strings.Builder
+	position := 0
+	for _, cluster := range entities.Graphemes(password) {
+		m := markRandom
+		if position < len(marks) {
+			m = marks[position]
+		}
+		position += len([]rune(cluster))
+
+		width := entities.DisplayWidth(cluster)
+		if width < 1 {
+			width = 1
+		}
+		chars.WriteString(paint(cluster, m))
+		symbols.WriteString(paint(string(m.symbol)+strings.Repeat(" ", width-1), m))
+	}
+	output.WriteString("   " + chars.String() + "\n")
+	output.WriteString("   " + symbols.String() + "\n")
+}
+
+// writeFactorTable lists scoring factors with their contributions and the
+// 1-based character positions they are based on
+func (f *Formatter) writeFactorTable(output *strings.Builder, factors []services.ScoringFactor, length int, unit, contributionFormat string) {
+	output.WriteString(fmt.Sprintf("   %-20s %7s  %-10s %s\n", "Factor", unit, "Evidence", "Rationale"))
+	for _, factor := range factors {
+		evidence := "-"
+		switch {
+		case !factor.HasSpan():
+		case factor.Start == 0 && factor.End == length-1:
+			evidence = "all"
+		case factor.Start == factor.End:
+			evidence = fmt.Sprintf("%d", factor.Start+1)
+		default:
+			evidence = fmt.Sprintf("%d-%d", factor.Start+1, factor.End+1)
+		}
+		contribution := fmt.Sprintf(contributionFormat, factor.Contribution)
+		if factor.Contribution == 0 {
+			contribution = "0"
+		}
+		output.WriteString(fmt.Sprintf("   %-20s %7s  %-10s %s\n", factor.Name, contribution, evidence, factor.Rationale))
+	}
+}
+
+// FormatBreachResults formats breach corpus lookups
+func (f *Formatter) FormatBreachResults(breaches []entities.BreachResult) string {
+	var output strings.Builder
+
+	for _, breach := range breaches {
+		if breach.Found {
+			output.WriteString(fmt.Sprintf("\n🚨 Breached: seen %d times in %s (%s)\n", breach.Count, breach.Source, breach.HashType))
+		} else {
+			output.WriteString(fmt.Sprintf("\n✅ Not found in %s (%s)\n", breach.Source, breach.HashType))
+		}
+	}
+
+	return output.String()
+}
+
+// FormatGuessEstimate formats the guess estimate and the decomposition it was based on
+func (f *Formatter) FormatGuessEstimate(estimate services.GuessEstimate) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("\n🧮 Guess Estimate: ~10^%.1f guesses (score %d/4)\n", estimate.GuessesLog10, estimate.Score))
+	for _, match := range estimate.Sequence {
+		output.WriteString(fmt.Sprintf("   • %q %s · 10^%.1f\n", match.Token, f.describeMatch(match), match.GuessesLog10))
+	}
+
+	return output.String()
+}
+
+// FormatCrackTimes formats the expected time to crack under each attack scenario
+func (f *Formatter) FormatCrackTimes(crackTimes []services.CrackTimeEstimate) string {
+	var output strings.Builder
+
+	output.WriteString("\n⏱️  Time to crack:\n")
+	for _, crackTime := range crackTimes {
+		output.WriteString(fmt.Sprintf("   • %-24s %-22s %s\n",
+			crackTime.Scenario.Name, crackTime.Duration.String(), crackTime.Scenario.Description))
+	}
+
+	return output.String()
+}
+
+// FormatCalibration formats measured hash rates and where they were stored;
+// custom is set when path isn't the default location
+func (f *Formatter) FormatCalibration(calibration *entities.Calibration, path string, custom bool) string {
+	var output strings.Builder
+
+	output.WriteString("⚙️  Calibration Results:\n")
+	output.WriteString(fmt.Sprintf("   %-22s %18s %18s\n", "Algorithm", "This machine/s", "Attacker/s"))
+	for _, rate := range calibration.Rates {
+		output.WriteString(fmt.Sprintf("   %-22s %18.4g %18.4g\n",
+			rate.Label(), rate.HashesPerSecond, rate.HashesPerSecond*calibration.Multiplier))
+	}
+	output.WriteString(fmt.Sprintf("\nHardware multiplier: x%g\n", calibration.Multiplier))
+	output.WriteString(fmt.Sprintf("Saved to %s\n", path))
+	if custom {
+		output.WriteString(fmt.Sprintf("Use with: passgen check <password> --attack calibrated --calibration %s\n", path))
+	} else {
+		output.WriteString("Use with: passgen check <password> --attack calibrated\n")
+	}
+
+	return output.String()
+}
+
+// FormatModelTraining summarizes a trained guess model; custom is set when
+// path isn't the default location
+func (f *Formatter) FormatModelTraining(model *services.PCFGModel, path string, custom bool) string {
+	var output strings.Builder
+
+	output.WriteString("🧠 Guess Model Trained:\n")
+	output.WriteString(fmt.Sprintf("   %-16s %d\n", "Passwords:", model.Passwords))
+	output.WriteString(fmt.Sprintf("   %-16s %d\n", "Structures:", len(model.Structures)))
+	output.WriteString(fmt.Sprintf("   %-16s %d\n", "Digit runs:", len(model.Digits)))
+	output.WriteString(fmt.Sprintf("   %-16s %d\n", "Symbol runs:", len(model.Symbols)))
+	output.WriteString(fmt.Sprintf("   %-16s %d\n", "Letter runs:", len(model.Words)))
+	output.WriteString(fmt.Sprintf("   %-16s %d\n", "Letter n-grams:", len(model.Letters)))
+
+	structures := make([]string, 0, len(model.Structures))
+	for structure := range model.Structures {
+		structures = append(structures, structure)
+	}
+	sort.Slice(structures, func(i, j int) bool {
+		if model.Structures[structures[i]] != model.Structures[structures[j]] {
+			return model.Structures[structures[i]] > model.Structures[structures[j]]
+		}
+		return structures[i] < structures[j]
+	})
+	if len(structures) > 5 {
+		structures = structures[:5]
+	}
+	if len(structures) > 0 {
+		output.WriteString("\nMost common structures:\n")
+		for _, structure := range structures {
+			output.WriteString(fmt.Sprintf("   %-12s %5.1f%%\n", structure,
+				100*float64(model.Structures[structure])/float64(model.Passwords)))
+		}
+	}
+
+	output.WriteString(fmt.Sprintf("\nSaved to %s\n", path))
+	if custom {
+		output.WriteString(fmt.Sprintf("Use with: passgen check <password> --estimator pcfg --model %s\n", path))
+	} else {
+		output.WriteString("Use with: passgen check <password> --estimator pcfg\n")
+	}
+
+	return output.String()
+}
+
+// complianceIcons marks each compliance check outcome
+var complianceIcons = map[string]string{
+	services.ComplianceStatusPass: "✅",
+	services.ComplianceStatusWarn: "⚠️ ",
+	services.ComplianceStatusFail: "❌",
+}
+
+// FormatCompliance formats the evaluation of a password against a standard
+func (f *Formatter) FormatCompliance(result services.ComplianceResult) string {
+	var output strings.Builder
+
+	verdict := "✅ Compliant"
+	if !result.Compliant {
+		verdict = "❌ Not compliant"
+	}
+	output.WriteString(fmt.Sprintf("📜 %s: %s\n", strings.ToUpper(result.Standard), verdict))
+	output.WriteString(f.FormatComplianceChecks(result.Checks))
+
+	return output.String()
+}
+
+// FormatComplianceChecks formats compliance checks, one per line
+func (f *Formatter) FormatComplianceChecks(checks []services.ComplianceCheck) string {
+	var output strings.Builder
+
+	for _, check := range checks {
+		output.WriteString(fmt.Sprintf("   %s %s: %s\n", complianceIcons[check.Status], check.Requirement, check.Detail))
+		if check.Reference != "" {
+			output.WriteString(fmt.Sprintf("      ↳ %s\n", check.Reference))
+		}
+	}
+
+	return output.String()
+}
+
+// FormatProfile formats a compliance profile's generation settings and rules
+func (f *Formatter) FormatProfile(profile entities.ComplianceProfile) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("📜 %s (%s)\n", profile.Title, profile.Name))
+	if profile.Description != "" {
+		output.WriteString(fmt.Sprintf("\n%s\n", profile.Description))
+	}
+
+	output.WriteString("\nRules:\n")
+	for _, rule := range profile.Rules() {
+		output.WriteString(fmt.Sprintf("   • %s\n", rule.Rule))
+		if rule.Reference != "" {
+			output.WriteString(fmt.Sprintf("      ↳ %s\n", rule.Reference))
+		}
+	}
+
+	var types []string
+	for _, class := range []struct {
+		enabled bool
+		name    string
+	}{
+		{profile.Generation.Lower, "lowercase"},
+		{profile.Generation.Upper, "uppercase"},
+		{profile.Generation.Numbers, "numbers"},
+		{profile.Generation.Symbols, "symbols"},
+	} {
+		if class.enabled {
+			types = append(types, class.name)
+		}
+	}
+	output.WriteString(fmt.Sprintf("\nGenerates: %d characters of %s\n", profile.Generation.Length, strings.Join(types, ", ")))
+
+	return output.String()
+}
+
+// FormatSimilarity formats a password comparison
+func (f *Formatter) FormatSimilarity(result services.SimilarityResult) string {
+	var output strings.Builder
+
+	verdict := "✅ Different enough"
+	if result.Similar {
+		verdict = "⚠️  Near-duplicates"
+	}
+	output.WriteString(fmt.Sprintf("🔁 Similarity: %.0f%% — %s\n", result.Score*100, verdict))
+	output.WriteString(fmt.Sprintf("   • Normalized stems: %q vs %q (%d edit(s), %.0f%%)\n",
+		result.StemA, result.StemB, result.StemDistance, result.StemSimilarity*100))
+	output.WriteString(fmt.Sprintf("   • Raw edit distance: %d (%.0f%%)\n", result.RawDistance, result.RawSimilarity*100))
+	output.WriteString(fmt.Sprintf("   • Shared tokens: %.0f%%\n", result.TokenSimilarity*100))
+
+	for _, reason := range result.Reasons {
+		output.WriteString(fmt.Sprintf("\n💡 %s", reason))
+	}
+	if len(result.Reasons) > 0 {
+		output.WriteString("\n")
+	}
+
+	return output.String()
+}
+
+// FormatAuditReport formats an aggregate password audit
+func (f *Formatter) FormatAuditReport(report services.AuditReport) string {
+	var output strings.Builder
+
+	output.WriteString(fmt.Sprintf("🔐 Password Audit: %d passwords, %d unique\n", report.Total, report.Unique))
+
+	f.writeHistogram(&output, "💪 Strength", report.Strengths, report.Total)
+	f.writeHistogram(&output, "🧮 Guess score (0-4)", report.Scores, report.Total)
+	f.writeHistogram(&output, "📏 Length", report.Lengths, report.Total)
+
+	output.WriteString("\n🔎 Patterns:\n")
+	if len(report.Patterns) == 0 {
+		output.WriteString("   None found\n")
+	}
+	for _, pattern := range report.Patterns {
+		output.WriteString(fmt.Sprintf("   %-22s %6d\n", pattern.Label, pattern.Count))
+	}
+
+	output.WriteString("\n♻️  Reuse:\n")
+	if len(report.Reused) == 0 {
+		output.WriteString("   No password is used more than once\n")
+	}
+	for _, reuse := range report.Reused {
+		labels := make([]string, len(reuse.Entries))
+		for i, entry := range reuse.Entries {
+			labels[i] = entry.Label()
+		}
+		output.WriteString(fmt.Sprintf("   %-22s %6d× (%s)\n", reuse.Password, reuse.Count, strings.Join(labels, ", ")))
+	}
+
+	if report.SimilarSkipped {
+		output.WriteString("\n👯 Similar passwords: not checked, too many unique passwords\n")
+	}
+	if len(report.Similar) > 0 {
+		output.WriteString("\n👯 Similar passwords:\n")
+		for _, similar := range report.Similar {
+			labels := make([]string, len(similar.Entries))
+			for i, entry := range similar.Entries {
+				labels[i] = entry.Label()
+			}
+			output.WriteString(fmt.Sprintf("   %s (%s)\n", strings.Join(similar.Passwords, " ≈ "), strings.Join(labels, ", ")))
+		}
+	}
+
+	output.WriteString("\n🚨 Worst offenders:\n")
+	for _, offender := range report.WorstOffenders {
+		output.WriteString(fmt.Sprintf("   %-20s %-22s %-11s 10^%-5.1f %s\n", offender.Label(), offender.Password,
+			offender.Strength, offender.GuessesLog10, strings.Join(offender.Patterns, ", ")))
+	}
+
+	if len(report.Findings) > 0 {
+		output.WriteString("\n📝 Entries needing attention:\n")
+		for _, finding := range report.Findings {
+			who := ""
+			if finding.Username != "" {
+				who = " [" + finding.Username + "]"
+			}
+			output.WriteString(fmt.Sprintf("   %s%s: %s\n", finding.Label(), who, strings.Join(finding.Issues, ", ")))
+		}
+	}
+
+	return output.String()
+}
+
+// FormatSecretFindings lists candidate secrets by location with their ratings
+func (f *Formatter) FormatSecretFindings(findings []services.SecretFinding) string {
+	if len(findings) == 0 {
+		return "✅ No candidate secrets found\n"
+	}
+
+	var output strings.Builder
+	weak := 0
+	for _, finding := range findings {
+		if finding.Weak {
+			weak++
+		}
+	}
+	output.WriteString(fmt.Sprintf("🔎 Found %d candidate secrets (%d weak):\n\n", len(findings), weak))
+
+	for _, finding := range findings {
+		kind := finding.Kind
+		if finding.Key != "" {
+			kind = fmt.Sprintf("%s to %s", kind, finding.Key)
+		}
+		marker := "🔒"
+		if finding.Weak {
+			marker = "⚠️ "
+		}
+		output.WriteString(fmt.Sprintf("%s %s:%d:%d %s\n", marker, finding.Path, finding.Line, finding.Column, kind))
+		output.WriteString(fmt.Sprintf("   %s · %s (%.1f bits)\n", finding.Secret, finding.Strength, finding.Entropy))
+	}
+
+	output.WriteString("\n💡 Move secrets to a secret manager or environment variables; rotate any that were committed\n")
+	return output.String()
+}
+
+// writeHistogram writes labelled counts with proportional bars
+func (f *Formatter) writeHistogram(output *strings.Builder, title string, counts []services.AuditCount, total int) {
+	const barWidth = 30
+
+	output.WriteString(fmt.Sprintf("\n%s:\n", title))
+	for _, count := range counts {
+		bar := 0
+		if total > 0 {
+			bar = (count.Count*barWidth + total - 1) / total
+		}
+		line := fmt.Sprintf("   %-12s %6d %s", count.Label, count.Count, strings.Repeat("█", bar))
+		output.WriteString(strings.TrimRight(line, " ") + "\n")
+	}
+}
+
+// describeMatch explains why a segment of the password is guessable
+func (f *Formatter) describeMatch(match services.GuessMatch) string {
+	switch match.Pattern {
+	case services.PatternDictionary:
+		var details []string
+		details = append(details, fmt.Sprintf("%s list, rank %d", strings.ReplaceAll(match.Dictionary, "_", " "), match.Rank))
+		if match.Reversed {
+			details = append(details, "reversed")
+		}
+		if match.L33t {
+			details = append(details, "l33t")
+		}
+		return fmt.Sprintf("dictionary word '%s' (%s)", match.MatchedWord, strings.Join(details, ", "))
+	case services.PatternSpatial:
+		return fmt.Sprintf("keyboard walk on %s (%d turns, %d shifted)", match.Graph, match.Turns, match.ShiftedCount)
+	case services.PatternRepeat:
+		return fmt.Sprintf("'%s' repeated %d times", match.BaseToken, match.RepeatCount)
+	case services.PatternSequence:
+		direction := "ascending"
+		if !match.Ascending {
+			direction = "descending"
+		}
+		return fmt.Sprintf("%s %s sequence", direction, match.SequenceName)
+	case services.PatternRegex:
+		switch match.RegexName {
+		case services.RegexPhoneNumber:
+			return "phone number"
+		case services.RegexPostcode:
+			return "postcode"
+		default:
+			return "recent year"
+		}
+	case services.PatternDate:
+		return fmt.Sprintf("date %04d-%02d-%02d", match.Year, match.Month, match.Day)
+	default:
+		return "brute force"
+	}
 }
 
 // FormatWordPasswordGeneration formats word-based password generation results
@@ -87,9 +824,9 @@ func (f *Formatter) FormatWordPasswordGeneration(resp application.GenerateWordPa
 		}
 
 		// Make password VERY prominent and easy to read
-		output.WriteString("┌" + strings.Repeat("─", len(password)+2) + "┐\n")
+		output.WriteString("┌" + strings.Repeat("─", entities.DisplayWidth(password)+2) + "┐\n")
 		output.WriteString(fmt.Sprintf("│ %s │\n", password))
-		output.WriteString("└" + strings.Repeat("─", len(password)+2) + "┘\n\n")
+		output.WriteString("└" + strings.Repeat("─", entities.DisplayWidth(password)+2) + "┘\n\n")
 
 		// Brief info on one line
 		analysis := resp.Analyses[i]
@@ -98,7 +835,7 @@ func (f *Formatter) FormatWordPasswordGeneration(resp application.GenerateWordPa
 			string(resp.Pattern.Strategy),
 			analysis.Password.Length,
 			analysis.Strength,
-			analysis.StrengthEmoji))
+			strengthEmoji(analysis.Strength)))
 
 		// Add separator for multiple passwords
 		if i < len(resp.Passwords)-1 {
@@ -114,11 +851,11 @@ func (f *Formatter) FormatWordPasswordGeneration(resp application.GenerateWordPa
 		output.WriteString("\n� Details (security geek info):\n")
 		output.WriteString(fmt.Sprintf("   Entropy: %.1f bits | Character types: %s\n",
 			analysis.Entropy, strings.Join(analysis.CharacterTypes, ", ")))
-		output.WriteString(fmt.Sprintf("   Time to crack: %s\n", analysis.TimeToCrack))
-
-		// Add the sarcastic comment at the end for fun
-		if analysis.Celebration != "" {
-			output.WriteString(fmt.Sprintf("\n💬 %s\n", analysis.Celebration))
+		if crackTime, ok := summaryCrackTime(analysis.CrackTimes); ok {
+			output.WriteString(fmt.Sprintf("   Time to crack: %s\n", crackTime))
+		}
+		for _, word := range analysis.RecognizedWords {
+			output.WriteString(fmt.Sprintf("   Recognized: '%s' as %s\n", word.Token, f.describeMatch(word)))
 		}
 	}
 
@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/application"
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// analysisWithAttacks analyzes password with the crack times --attack would
+// put in its place
+func analysisWithAttacks(t *testing.T, password string, specs ...string) services.PasswordAnalysis {
+	t.Helper()
+	attacks, err := entities.ParseAttackScenarios(specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyzer := services.NewPasswordAnalyzer()
+	entity := entities.NewPassword(password)
+	analysis := analyzer.AnalyzePassword(entity, entity.ObservedConfig())
+	analysis.CrackTimes = analyzer.EstimateCrackTimes(analysis.Guesses, attacks)
+	return analysis
+}
+
+func TestFormatter_CrackTimeSummaryWithAttack(t *testing.T) {
+	formatter := NewFormatter()
+
+	tests := []struct {
+		name  string
+		specs []string
+		want  string
+	}{
+		{"default fast hash", []string{"offline-fast"}, ", cracks in "},
+		{"named fast hash", []string{"offline-fast:md5"}, "(offline-fast:md5)"},
+		{"fastest of several", []string{"online-throttled", "offline-slow"}, "(offline-slow:bcrypt:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := analysisWithAttacks(t, "k7#Qm2vX9pLw", tt.specs...)
+			crackTime, _ := summaryCrackTime(analysis.CrackTimes)
+
+			output := formatter.FormatPasswordGeneration([]services.PasswordAnalysis{analysis}, false, true)
+			if !strings.Contains(output, "cracks in "+crackTime+"\n") || !strings.Contains(output, tt.want) {
+				t.Errorf("generation output lacks crack time %q (%q):\n%s", crackTime, tt.want, output)
+			}
+
+			output = formatter.FormatWordPasswordGeneration(application.GenerateWordPasswordResponse{
+				Passwords: []string{analysis.Password.Value},
+				Analyses:  []services.PasswordAnalysis{analysis},
+			})
+			if !strings.Contains(output, "Time to crack: "+crackTime+"\n") {
+				t.Errorf("word output lacks crack time %q:\n%s", crackTime, output)
+			}
+		})
+	}
+}
+
+func TestFormatter_CrackTimeSummaryWithoutEstimates(t *testing.T) {
+	analysis := analysisWithAttacks(t, "k7#Qm2vX9pLw")
+	output := NewFormatter().FormatPasswordGeneration([]services.PasswordAnalysis{analysis}, false, false)
+	if strings.Contains(output, "cracks in") {
+		t.Errorf("output mentions a crack time without any estimates:\n%s", output)
+	}
+}
+
+func TestFormatter_PasswordStrengthCheckSuggestions(t *testing.T) {
+	checker := services.NewPasswordStrengthChecker()
+	result := checker.CheckPasswordStrength(entities.NewPassword("Pаssword"))
+	result = checker.ApplyBreachResults(result, []entities.BreachResult{{Source: "test", Found: true, Count: 7}})
+
+	output := NewFormatter().FormatPasswordStrengthCheck(result)
+	for _, want := range []string{
+		"Strength: Very Weak 🚨 (Score: 0/8)",
+		"Rejected: never use this password",
+		"• Password appears 7 times in known data breaches - never use it\n",
+		"• Add numbers\n",
+		"• Add special characters\n",
+		"• Mixes Latin and Cyrillic letters; lookalikes from different scripts are easy to retype wrongly\n",
+		"• Contains visually identical characters: 'а' (Cyrillic) looks like 'a'\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("check output lacks %q:\n%s", want, output)
+		}
+	}
+}
+
+func TestFormatter_PasswordStrengthCheckListsContextFindings(t *testing.T) {
+	checker := services.NewPasswordStrengthChecker()
+	context := entities.UserContext{Username: "alice", Organization: "Globex"}
+	result := checker.CheckPasswordStrength(entities.NewPassword("Alice-Globex-2024!"))
+	result = checker.ApplyUserContext(result, context.Match(result.Password.Value))
+
+	output := NewFormatter().FormatPasswordStrengthCheck(result)
+	suggestions := output[strings.Index(output, "Actionable Suggestions"):]
+	if !strings.HasPrefix(suggestions, "Actionable Suggestions:\n• [high] Contains your username 'alice'\n• [medium] ") {
+		t.Errorf("suggestions should lead with the findings by severity:\n%s", suggestions)
+	}
+}
+
+func TestFormatter_ExplanationMarksDetectedPatterns(t *testing.T) {
+	resp, err := application.NewPasswordService().ExplainPassword(application.ExplainPasswordRequest{Password: "k7abcabc1221"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := NewFormatter().FormatExplanation(resp, false)
+	if !strings.Contains(output, "   k7abcabc1221\n   ..RRRRRRMMMM\n") {
+		t.Errorf("character map should mark the repeated block and mirrored segment:\n%s", output)
+	}
+	if !strings.Contains(output, "• [medium] Contains mirrored segment: '1221'\n     k7abcabc1221\n             ^^^^\n") {
+		t.Errorf("pattern list should underline the mirrored segment:\n%s", output)
+	}
+}
+
+func TestFormatter_ExplanationCombinesEstimates(t *testing.T) {
+	resp, err := application.NewPasswordService().ExplainPassword(application.ExplainPasswordRequest{
+		Password:    "Summer2024!",
+		Estimators:  []string{services.EstimatorChecklist, services.EstimatorEntropy},
+		Aggregation: services.AggregateMax,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := NewFormatter().FormatExplanation(resp, false)
+	if want := "📏 Estimators (combined by max): " + resp.Combined.String() + "\n"; !strings.Contains(output, want) {
+		t.Errorf("explanation lacks %q:\n%s", want, output)
+	}
+}
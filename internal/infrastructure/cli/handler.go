@@ -1,14 +1,22 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/kumarasakti/passgen/internal/application"
 	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/infrastructure/calibration"
 	"github.com/spf13/cobra"
 )
 
+// attackFlagUsage documents the --attack flag shared by generate and check
+const attackFlagUsage = "Attack scenarios for crack time estimates (online-throttled, online-unthrottled, " +
+	"offline-slow[:bcrypt:COST|:argon2:TIME], offline-fast[:md5|ntlm|sha1], custom:RATE, calibrated[:ALGORITHM[:COST]])"
+
 // Handler manages CLI commands and interactions
 type Handler struct {
 	passwordService *application.PasswordService
@@ -54,7 +62,11 @@ func (h *Handler) CreateRootCommand(version string) *cobra.Command {
 		Short:   "Generate secure passwords",
 		Long:    h.createBanner(version),
 		Version: version,
-		Run:     h.HandleGeneratePassword,
+		Args:    cobra.NoArgs,
+		RunE:    h.HandleGeneratePassword,
+		// main reports errors and picks the exit code
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
 
 	// Set custom version template with banner
@@ -65,68 +77,231 @@ func (h *Handler) CreateRootCommand(version string) *cobra.Command {
 
 	// Add subcommands
 	rootCmd.AddCommand(h.createCheckCommand())
+	rootCmd.AddCommand(h.createExplainCommand())
 	rootCmd.AddCommand(h.createPresetCommand())
 	rootCmd.AddCommand(h.createWordCommand())
+	rootCmd.AddCommand(h.createCalibrateCommand())
+	rootCmd.AddCommand(h.createBreachCommand())
+	rootCmd.AddCommand(h.createAuditCommand())
+	rootCmd.AddCommand(h.createCompareCommand())
+	rootCmd.AddCommand(h.createPolicyCommand())
+	rootCmd.AddCommand(h.createProfileCommand())
+	rootCmd.AddCommand(h.createScanCommand())
+	rootCmd.AddCommand(h.createModelCommand())
+	markUsageErrors(rootCmd)
 
 	return rootCmd
 }
 
 // HandleGeneratePassword handles the main password generation
-func (h *Handler) HandleGeneratePassword(cmd *cobra.Command, args []string) {
+func (h *Handler) HandleGeneratePassword(cmd *cobra.Command, args []string) error {
 	// Handle convenience flags
 	h.handleConvenienceFlags(cmd)
 
-	req := application.GeneratePasswordRequest{Config: h.config}
+	attacks, err := h.parseAttackFlag(cmd)
+	if err != nil {
+		return err
+	}
+
+	standard, _ := cmd.Flags().GetString("standard")
+	mfa, _ := cmd.Flags().GetBool("mfa")
+	profile, err := h.resolveProfile(cmd)
+	if err != nil {
+		return err
+	}
+	if profile != "" {
+		if err := h.applyProfileDefaults(cmd, profile); err != nil {
+			return err
+		}
+	}
+	if h.config.ExtraClasses, err = h.loadCharacterClasses(cmd); err != nil {
+		return err
+	}
+	bannedWords, err := h.loadBannedWords(cmd)
+	if err != nil {
+		return err
+	}
+
+	req := application.GeneratePasswordRequest{
+		Config:      h.config,
+		Attacks:     attacks,
+		BannedWords: bannedWords,
+		Standard:    standard,
+		MFA:         mfa,
+		Profile:     profile,
+	}
 	resp, err := h.passwordService.GeneratePasswords(req)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating password: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("generating password: %w", err)
+	}
+
+	// Keep stdout to the passwords; conflicts with the standard are warnings
+	if len(resp.PolicyChecks) > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: these settings conflict with %s:\n%s", standard, h.formatter.FormatComplianceChecks(resp.PolicyChecks))
 	}
 
-	output := h.formatter.FormatPasswordGeneration(resp.Analyses, h.config.ExcludeSimilar)
+	output := h.formatter.FormatPasswordGeneration(resp.Analyses, h.config.ExcludeSimilar, len(attacks) > 0)
+	if resp.Profile != nil {
+		output += fmt.Sprintf("\n📜 Satisfies %s (%s); see 'passgen profile show %s'\n", resp.Profile.Title, resp.Profile.Name, resp.Profile.Name)
+	}
 	fmt.Print(output)
+	return nil
 }
 
 // HandleCheckPassword handles password strength checking
-func (h *Handler) HandleCheckPassword(cmd *cobra.Command, args []string) {
-	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "Error: exactly one password argument required\n")
-		os.Exit(1)
+func (h *Handler) HandleCheckPassword(cmd *cobra.Command, args []string) error {
+	standard, _ := cmd.Flags().GetString("standard")
+	mfa, _ := cmd.Flags().GetBool("mfa")
+	aggregation, _ := cmd.Flags().GetString("aggregate")
+	minStrength := entities.VeryWeak
+	if name, _ := cmd.Flags().GetString("min-strength"); name != "" {
+		var err error
+		if minStrength, err = entities.ParsePasswordStrength(name); err != nil {
+			return err
+		}
+	}
+
+	attacks, err := h.parseAttackFlag(cmd)
+	if err != nil {
+		return err
+	}
+	bannedWords, err := h.loadBannedWords(cmd)
+	if err != nil {
+		return err
+	}
+	profile, err := h.resolveProfile(cmd)
+	if err != nil {
+		return err
+	}
+	estimatorNames, err := h.selectedEstimators(cmd)
+	if err != nil {
+		return err
+	}
+	password, err := h.readPassword(cmd, args)
+	if err != nil {
+		return err
 	}
 
-	req := application.CheckPasswordRequest{Password: args[0]}
-	resp := h.passwordService.CheckPasswordStrength(req)
+	breachLookups, closeLookups, err := h.openBreachLookups(cmd)
+	if err != nil {
+		return err
+	}
+	defer closeLookups()
+
+	req := application.CheckPasswordRequest{
+		Password:      password,
+		Attacks:       attacks,
+		BreachLookups: breachLookups,
+		BannedWords:   bannedWords,
+		UserContext:   userContextFromFlags(cmd),
+		Standard:      standard,
+		MFA:           mfa,
+		Profile:       profile,
+		Estimators:    estimatorNames,
+		Aggregation:   aggregation,
+	}
+	resp, err := h.passwordService.CheckPasswordStrength(req)
+	if err != nil {
+		return fmt.Errorf("checking password: %w", err)
+	}
+
+	// A standard or profile replaces the checklist with its own verdict
+	if resp.Compliance != nil {
+		output := h.formatter.FormatCompliance(*resp.Compliance)
+		output += h.formatter.FormatBreachResults(resp.Result.Breaches)
+		fmt.Print(output)
+		return checkVerdict(resp, minStrength)
+	}
 
 	output := h.formatter.FormatPasswordStrengthCheck(resp.Result)
+	output += h.formatter.FormatPatterns(resp.Result.Password, resp.Patterns)
+	output += h.formatter.FormatBreachResults(resp.Result.Breaches)
+	output += h.formatter.FormatGuessEstimate(resp.Estimate)
+	output += h.formatter.FormatCrackTimes(resp.CrackTimes)
+	if len(resp.Estimates) > 0 {
+		output += h.formatter.FormatEstimates(resp.Estimates, resp.Combined, aggregation)
+	}
 	fmt.Print(output)
+
+	return checkVerdict(resp, minStrength)
 }
 
-// HandlePresetPassword handles preset password generation
-func (h *Handler) HandlePresetPassword(cmd *cobra.Command, args []string) {
-	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "Error: exactly one preset type argument required\n")
-		os.Exit(1)
+// checkVerdict returns the error check exits with, if any. A breach outranks
+// a policy violation, which outranks a rating below minStrength. The rating is
+// the estimators' combined one when estimators were selected.
+func checkVerdict(resp application.CheckPasswordResponse, minStrength entities.PasswordStrength) error {
+	if resp.Result.Breached() {
+		return entities.NewPasswordErrorOfKind(entities.KindBreached, "the password appears in a breach corpus")
+	}
+	if len(resp.Result.BannedWords) > 0 {
+		return entities.NewPasswordErrorOfKind(entities.KindPolicyViolation, "the password contains a banned word")
+	}
+	if resp.Compliance != nil && !resp.Compliance.Compliant {
+		return entities.NewPasswordErrorOfKind(entities.KindPolicyViolation,
+			fmt.Sprintf("the password does not comply with %s", resp.Compliance.Standard))
+	}
+
+	rating := resp.Result.Strength
+	if len(resp.Estimates) > 0 {
+		rating = resp.Combined
+	}
+	if rating < minStrength {
+		return entities.NewPasswordErrorOfKind(entities.KindWeakPassword,
+			fmt.Sprintf("the password is rated %s, below the required %s", rating, minStrength))
 	}
+	return nil
+}
+
+// addUserContextFlags registers the account holder flags shared by check and explain
+func addUserContextFlags(cmd *cobra.Command) {
+	cmd.Flags().String("user", "", "Username of the account; similar passwords are penalized")
+	cmd.Flags().String("email", "", "Email address of the account holder")
+	cmd.Flags().String("name", "", "Full name of the account holder")
+	cmd.Flags().String("org", "", "Organization the account belongs to")
+}
+
+// userContextFromFlags collects the account holder's details from the check flags
+func userContextFromFlags(cmd *cobra.Command) entities.UserContext {
+	username, _ := cmd.Flags().GetString("user")
+	email, _ := cmd.Flags().GetString("email")
+	name, _ := cmd.Flags().GetString("name")
+	org, _ := cmd.Flags().GetString("org")
+
+	return entities.UserContext{Username: username, Email: email, Name: name, Organization: org}
+}
+
+// HandleCompare compares two passwords for near-reuse
+func (h *Handler) HandleCompare(cmd *cobra.Command, args []string) error {
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+
+	resp, err := h.passwordService.ComparePasswords(application.ComparePasswordsRequest{
+		First:     args[0],
+		Second:    args[1],
+		Threshold: threshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(h.formatter.FormatSimilarity(resp.Result))
+	return nil
+}
 
+// HandlePresetPassword handles preset password generation
+func (h *Handler) HandlePresetPassword(cmd *cobra.Command, args []string) error {
 	presetType := args[0]
 	resp, err := h.passwordService.GeneratePresetPassword(presetType)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating preset password: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Available presets: secure, simple, pin, alphanumeric\n")
-		os.Exit(1)
+		return fmt.Errorf("generating preset password: %w (available: secure, simple, pin, alphanumeric)", err)
 	}
 
-	output := h.formatter.FormatPasswordGeneration(resp.Analyses, false)
+	output := h.formatter.FormatPasswordGeneration(resp.Analyses, false, false)
 	fmt.Print(output)
+	return nil
 }
 
 // HandleWordPassword handles word-based password generation
-func (h *Handler) HandleWordPassword(cmd *cobra.Command, args []string) {
-	if len(args) != 1 {
-		fmt.Fprintf(os.Stderr, "Error: exactly one word argument required\n")
-		os.Exit(1)
-	}
-
+func (h *Handler) HandleWordPassword(cmd *cobra.Command, args []string) error {
 	word := args[0]
 
 	// Get flags
@@ -150,8 +325,7 @@ func (h *Handler) HandleWordPassword(cmd *cobra.Command, args []string) {
 	case "hybrid":
 		transformationStrategy = entities.StrategyHybrid
 	default:
-		fmt.Fprintf(os.Stderr, "Error: invalid strategy '%s'. Available: leetspeak, mixed-case, suffix, prefix, insert, hybrid\n", strategy)
-		os.Exit(1)
+		return entities.NewPasswordError(fmt.Sprintf("invalid strategy '%s'. Available: leetspeak, mixed-case, suffix, prefix, insert, hybrid", strategy))
 	}
 
 	// Validate complexity
@@ -164,28 +338,69 @@ func (h *Handler) HandleWordPassword(cmd *cobra.Command, args []string) {
 	case "high":
 		complexityLevel = entities.ComplexityHigh
 	default:
-		fmt.Fprintf(os.Stderr, "Error: invalid complexity '%s'. Available: low, medium, high\n", complexity)
-		os.Exit(1)
+		return entities.NewPasswordError(fmt.Sprintf("invalid complexity '%s'. Available: low, medium, high", complexity))
+	}
+
+	bannedWords, err := h.loadBannedWords(cmd)
+	if err != nil {
+		return err
 	}
 
 	// Create request
 	req := application.GenerateWordPasswordRequest{
-		Word:       word,
-		Strategy:   transformationStrategy,
-		Complexity: complexityLevel,
-		Count:      count,
+		Word:        word,
+		Strategy:    transformationStrategy,
+		Complexity:  complexityLevel,
+		Count:       count,
+		BannedWords: bannedWords,
 	}
 
 	// Generate word-based passwords
 	resp, err := h.passwordService.GenerateWordPasswords(req)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating word-based password: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("generating word-based password: %w", err)
 	}
 
 	// Format and display output
 	output := h.formatter.FormatWordPasswordGeneration(resp)
 	fmt.Print(output)
+	return nil
+}
+
+// HandleCalibrate benchmarks local hashing rates and stores them for crack time estimates
+func (h *Handler) HandleCalibrate(cmd *cobra.Command, args []string) error {
+	duration, _ := cmd.Flags().GetDuration("duration")
+	multiplier, _ := cmd.Flags().GetFloat64("multiplier")
+	bcryptCosts, _ := cmd.Flags().GetIntSlice("bcrypt-costs")
+	output, _ := cmd.Flags().GetString("output")
+
+	if multiplier <= 0 {
+		return entities.NewPasswordError("multiplier must be positive")
+	}
+	for _, cost := range bcryptCosts {
+		if cost < calibration.MinBcryptCost || cost > calibration.MaxBcryptCost {
+			return entities.NewPasswordError(fmt.Sprintf("bcrypt cost must be between %d and %d", calibration.MinBcryptCost, calibration.MaxBcryptCost))
+		}
+	}
+
+	store, err := calibration.NewStore(output)
+	if err != nil {
+		return fmt.Errorf("locating calibration data: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Benchmarking hash rates (about %s per algorithm)...\n", duration)
+	result := &entities.Calibration{
+		MeasuredAt: time.Now().UTC(),
+		Multiplier: multiplier,
+		Rates:      calibration.Benchmark(calibration.Options{Duration: duration, BcryptCosts: bcryptCosts}),
+	}
+
+	if err := store.Save(result); err != nil {
+		return fmt.Errorf("saving calibration data: %w", err)
+	}
+
+	fmt.Print(h.formatter.FormatCalibration(result, store.Path(), output != ""))
+	return nil
 }
 
 // addFlags adds command line flags to the root command
@@ -196,9 +411,15 @@ func (h *Handler) addFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&h.config.IncludeNumbers, "numbers", "n", false, "Include numbers")
 	cmd.Flags().BoolVarP(&h.config.IncludeSymbols, "symbols", "s", true, "Include symbols")
 	cmd.Flags().BoolVar(&h.config.ExcludeSimilar, "exclude-similar", false, "Exclude similar characters (il1Lo0O)")
+	cmd.Flags().BoolVar(&h.config.ExcludeConfusable, "exclude-confusable", false, "Exclude characters that look identical to another allowed character (Unicode confusables)")
 	cmd.Flags().StringVar(&h.config.ExcludeChars, "exclude", "", "Characters to exclude from password")
 	cmd.Flags().BoolVar(&h.config.NoRepeat, "no-repeat", false, "Avoid duplicate characters (trades ~2 bits entropy for pattern resistance)")
 	cmd.Flags().IntVarP(&h.config.Count, "count", "c", 1, "Number of passwords to generate")
+	addAttackFlags(cmd)
+	addCharsetFlags(cmd)
+	addBannedFlags(cmd)
+	addStandardFlags(cmd)
+	addProfileFlag(cmd)
 
 	// Add convenience flags
 	cmd.Flags().BoolP("secure", "S", false, "Generate secure password (includes all character types)")
@@ -206,6 +427,50 @@ func (h *Handler) addFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolP("alphanumeric", "a", false, "Generate alphanumeric password (letters and numbers)")
 }
 
+// addAttackFlags registers the attack scenario flags shared by generation and check
+func addAttackFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("attack", nil, attackFlagUsage)
+	cmd.Flags().String("calibration", "", "Calibration file for calibrated scenarios, as written by 'calibrate --output' (default: user config directory)")
+}
+
+// parseAttackFlag reads the --attack scenarios. Calibrated scenarios are
+// resolved against the calibration results in --calibration or the user
+// config directory.
+func (h *Handler) parseAttackFlag(cmd *cobra.Command) ([]entities.AttackScenario, error) {
+	specs, _ := cmd.Flags().GetStringSlice("attack")
+
+	var calibrationData *entities.Calibration
+	for _, spec := range specs {
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(spec)), entities.AttackCalibrated) {
+			var err error
+			path, _ := cmd.Flags().GetString("calibration")
+			if calibrationData, err = h.loadCalibration(path); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	return calibrationData.ParseAttackScenarios(specs)
+}
+
+// loadCalibration reads the calibration results stored at path, or in the
+// user config directory when path is empty
+func (h *Handler) loadCalibration(path string) (*entities.Calibration, error) {
+	store, err := calibration.NewStore(path)
+	if err != nil {
+		return nil, fmt.Errorf("locating calibration data: %w", err)
+	}
+	calibrationData, err := store.Load()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, usageError(fmt.Errorf("reading calibration data: %w", err))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading calibration data: %w", err)
+	}
+	return calibrationData, nil
+}
+
 // handleConvenienceFlags processes convenience flags that modify configuration
 func (h *Handler) handleConvenienceFlags(cmd *cobra.Command) {
 	if secure, _ := cmd.Flags().GetBool("secure"); secure {
@@ -232,13 +497,50 @@ func (h *Handler) handleConvenienceFlags(cmd *cobra.Command) {
 
 // createCheckCommand creates the check subcommand
 func (h *Handler) createCheckCommand() *cobra.Command {
-	return &cobra.Command{
+	checkCmd := &cobra.Command{
 		Use:   "check [password]",
 		Short: "Check password strength",
-		Long:  "Analyze password strength and provide feedback with specific suggestions for improvement.",
-		Args:  cobra.ExactArgs(1),
-		Run:   h.HandleCheckPassword,
+		Long: `Analyze password strength and provide feedback with specific suggestions for improvement.
+
+` + passwordInputHelp + `
+
+Examples:
+  passgen check
+  pass show email | passgen check --stdin
+  passgen check --fd 3 3<secret.txt
+  PASSGEN_PASSWORD=... passgen check --from-env PASSGEN_PASSWORD`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: h.HandleCheckPassword,
+	}
+
+	addAttackFlags(checkCmd)
+	addPasswordInputFlags(checkCmd)
+	addBreachFlags(checkCmd)
+	addBannedFlags(checkCmd)
+	addUserContextFlags(checkCmd)
+	addEstimatorFlag(checkCmd)
+	checkCmd.Flags().String("min-strength", "", "Exit with status 4 when the password is rated below this strength (weak, medium, strong, very-strong, extremely-strong)")
+	addStandardFlags(checkCmd)
+	addProfileFlag(checkCmd)
+
+	return checkCmd
+}
+
+// createCompareCommand creates the compare subcommand
+func (h *Handler) createCompareCommand() *cobra.Command {
+	compareCmd := &cobra.Command{
+		Use:   "compare [password] [password]",
+		Short: "Check whether two passwords are near-duplicates",
+		Long: `Compare two passwords after normalization (case folding, l33t reversal and
+stripping trailing digits/symbols) to catch rotations like Summer2024! and
+Summer2025! that exact reuse checks miss.`,
+		Args: cobra.ExactArgs(2),
+		RunE: h.HandleCompare,
 	}
+
+	compareCmd.Flags().Float64("threshold", 0, "Similarity score (0-1) at which passwords count as near-duplicates (default 0.75)")
+
+	return compareCmd
 }
 
 // createPresetCommand creates the preset subcommand
@@ -248,10 +550,37 @@ func (h *Handler) createPresetCommand() *cobra.Command {
 		Short: "Generate password using predefined presets",
 		Long:  "Generate password using predefined presets: secure, simple, pin, alphanumeric",
 		Args:  cobra.ExactArgs(1),
-		Run:   h.HandlePresetPassword,
+		RunE:  h.HandlePresetPassword,
 	}
 }
 
+// createCalibrateCommand creates the calibrate subcommand
+func (h *Handler) createCalibrateCommand() *cobra.Command {
+	calibrateCmd := &cobra.Command{
+		Use:   "calibrate",
+		Short: "Measure local hash rates for crack time estimates",
+		Long: `Benchmark this machine's hashing speed for bcrypt, PBKDF2-SHA256, scrypt,
+SHA-256 and MD5 and store the results. The multiplier scales the measured
+rates to the attacker's hardware (e.g. 1000 for a GPU cluster).
+
+Use the results with --attack calibrated or --attack calibrated:bcrypt:12.
+
+Examples:
+  passgen calibrate                          # Measure and store local rates
+  passgen calibrate --multiplier 5000        # Model a GPU cluster
+  passgen check "hunter2" --attack calibrated`,
+		Args: cobra.NoArgs,
+		RunE: h.HandleCalibrate,
+	}
+
+	calibrateCmd.Flags().Duration("duration", time.Second, "Minimum benchmark time per algorithm")
+	calibrateCmd.Flags().Float64("multiplier", 1, "Attacker hardware speed relative to this machine")
+	calibrateCmd.Flags().IntSlice("bcrypt-costs", calibration.DefaultBcryptCosts, "bcrypt cost factors to benchmark")
+	calibrateCmd.Flags().String("output", "", "Calibration file (default: user config directory)")
+
+	return calibrateCmd
+}
+
 // createWordCommand creates the word subcommand
 func (h *Handler) createWordCommand() *cobra.Command {
 	wordCmd := &cobra.Command{
@@ -271,13 +600,14 @@ Examples:
   passgen word "security" --complexity high       # Maximum complexity
   passgen word "security" --count 3               # Generate 3 variations`,
 		Args: cobra.ExactArgs(1),
-		Run:  h.HandleWordPassword,
+		RunE: h.HandleWordPassword,
 	}
 
 	// Add word-specific flags
 	wordCmd.Flags().String("strategy", "hybrid", "Transformation strategy (leetspeak, mixed-case, suffix, prefix, insert, hybrid)")
 	wordCmd.Flags().String("complexity", "medium", "Complexity level (low, medium, high)")
 	wordCmd.Flags().IntP("count", "c", 1, "Number of password variations to generate")
+	addBannedFlags(wordCmd)
 
 	return wordCmd
 }
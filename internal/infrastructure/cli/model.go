@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/domain/services"
+	"github.com/kumarasakti/passgen/internal/infrastructure/models"
+	"github.com/spf13/cobra"
+)
+
+// HandleModelTrain trains the pcfg estimator's guess model on a password
+// corpus and stores it
+func (h *Handler) HandleModelTrain(cmd *cobra.Command, args []string) error {
+	withCounts, _ := cmd.Flags().GetBool("counts")
+	minCount, _ := cmd.Flags().GetInt("min-count")
+	output, _ := cmd.Flags().GetString("output")
+
+	if minCount < 1 {
+		return entities.NewPasswordError("min-count must be at least 1")
+	}
+
+	var corpus io.Reader = os.Stdin
+	if args[0] != "-" {
+		file, err := os.Open(args[0])
+		if err != nil {
+			return usageError(err)
+		}
+		defer file.Close()
+		corpus = file
+	}
+
+	store, err := models.NewStore(output)
+	if err != nil {
+		return fmt.Errorf("locating guess model: %w", err)
+	}
+
+	trainer := services.NewPCFGTrainer()
+	if _, err := models.Train(trainer, corpus, withCounts); err != nil {
+		return usageError(fmt.Errorf("reading corpus: %w", err))
+	}
+	model := trainer.Model(minCount)
+	if model.Passwords == 0 {
+		return entities.NewPasswordError("the corpus holds no passwords")
+	}
+
+	if err := store.Save(model); err != nil {
+		return fmt.Errorf("saving guess model: %w", err)
+	}
+
+	fmt.Print(h.formatter.FormatModelTraining(model, store.Path(), output != ""))
+	return nil
+}
+
+// loadGuessModel replaces the built-in pcfg estimator with one using the
+// guess model at path, or the one stored in the user config directory when
+// one has been trained
+func (h *Handler) loadGuessModel(path string) error {
+	store, err := models.NewStore(path)
+	if err != nil {
+		return fmt.Errorf("locating guess model: %w", err)
+	}
+	model, err := store.Load()
+	if errors.Is(err, models.ErrInvalidModel) {
+		return usageError(fmt.Errorf("loading guess model: %w", err))
+	}
+	if err != nil {
+		return fmt.Errorf("loading guess model: %w", err)
+	}
+	if model == nil && path != "" {
+		return usageError(fmt.Errorf("guess model %s does not exist", path))
+	}
+	if model != nil {
+		h.passwordService.UseGuessModel(model)
+	}
+	return nil
+}
+
+// selectsEstimator reports whether name is among the selected estimators
+func selectsEstimator(names []string, name string) bool {
+	for _, selected := range names {
+		if strings.EqualFold(strings.TrimSpace(selected), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// createModelCommand creates the model subcommand
+func (h *Handler) createModelCommand() *cobra.Command {
+	modelCmd := &cobra.Command{
+		Use:   "model",
+		Short: "Train the guess model of the pcfg estimator",
+		Long: `The pcfg estimator (--estimator pcfg) rates a password by how many guesses a
+probabilistic cracker needs: it learns structures such as L6D2S1 (six
+letters, two digits, a symbol), digit and symbol runs, and a character-level
+Markov model of letters from leaked passwords. The built-in model is trained
+on an embedded sample; train your own to model the attackers of your users.`,
+	}
+
+	trainCmd := &cobra.Command{
+		Use:   "train <corpus>",
+		Short: "Train a guess model on a password corpus",
+		Long: `Train a guess model on a corpus with one password per line ('-' reads stdin)
+and store it in the user config directory, where the pcfg estimator picks it
+up in place of the built-in model. A model written elsewhere with --output is
+used by passing it to --model.
+
+Training runs offline and the model holds only aggregate counts: structures,
+digit and symbol runs and letter sequences seen fewer than --min-count times
+are dropped, so the model can be kept after the corpus is destroyed.
+
+Examples:
+  passgen model train cracked.txt
+  sort cracked.txt | uniq -c | passgen model train - --counts
+  passgen check "Tr0ub4dor&3" --estimator pcfg
+  passgen model train cracked.txt --output team.json
+  passgen check "Tr0ub4dor&3" --estimator pcfg --model team.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: h.HandleModelTrain,
+	}
+	trainCmd.Flags().Bool("counts", false, "Each line is a count and a password, as written by 'uniq -c'")
+	trainCmd.Flags().Int("min-count", 2, "Drop structures, runs and letter sequences seen fewer times")
+	trainCmd.Flags().String("output", "", "Model file (default: user config directory)")
+	modelCmd.AddCommand(trainCmd)
+
+	return modelCmd
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kumarasakti/passgen/internal/infrastructure/terminal"
+	"github.com/spf13/cobra"
+)
+
+// passwordInputHelp describes how commands that read a password take it
+const passwordInputHelp = `The password is prompted for without echo. To script it, pipe it with
+--stdin, pass an open file descriptor with --fd, or name an environment
+variable with --from-env (which is unset once read). Giving the password as
+an argument still works but leaves it in shell history and visible to ps.`
+
+// addPasswordInputFlags registers the ways of passing a password other than as an argument
+func addPasswordInputFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("stdin", false, "Read the password from the first line of stdin")
+	cmd.Flags().Int("fd", -1, "Read the password from the first line of file descriptor N (e.g. --fd 3 3<secret.txt)")
+	cmd.Flags().String("from-env", "", "Read the password from environment variable VAR, then unset it")
+}
+
+// readPassword returns the password from whichever source was chosen,
+// prompting on the terminal without echo when none was
+func (h *Handler) readPassword(cmd *cobra.Command, args []string) (string, error) {
+	fromStdin, _ := cmd.Flags().GetBool("stdin")
+	fd, _ := cmd.Flags().GetInt("fd")
+	envVar, _ := cmd.Flags().GetString("from-env")
+
+	sources := 0
+	for _, chosen := range []bool{len(args) > 0, fromStdin, cmd.Flags().Changed("fd"), envVar != ""} {
+		if chosen {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return "", usageError(errors.New("give the password one way only: as an argument, --stdin, --fd or --from-env"))
+	}
+
+	var password string
+	var err error
+	switch {
+	case len(args) > 0:
+		fmt.Fprintf(os.Stderr, "Warning: a password given as an argument is saved in shell history and visible to other users in ps; omit it to be prompted, or use --stdin, --fd or --from-env\n")
+		password = args[0]
+	case fromStdin:
+		password, err = terminal.ReadLine(os.Stdin)
+	case cmd.Flags().Changed("fd"):
+		password, err = readPasswordFromFD(fd)
+	case envVar != "":
+		value, ok := os.LookupEnv(envVar)
+		if !ok {
+			err = fmt.Errorf("environment variable %s is not set", envVar)
+		}
+		// Child processes, such as breach lookups, don't need to inherit it
+		os.Unsetenv(envVar)
+		password = value
+	default:
+		password, err = promptPassword()
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = errors.New("no input")
+	}
+	if err == nil && password == "" {
+		err = errors.New("the password is empty")
+	}
+	if err != nil {
+		return "", usageError(fmt.Errorf("reading password: %w", err))
+	}
+	return password, nil
+}
+
+// readPasswordFromFD reads the first line of an inherited file descriptor
+func readPasswordFromFD(fd int) (string, error) {
+	if fd < 0 {
+		return "", fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	if fd == 0 {
+		return terminal.ReadLine(os.Stdin)
+	}
+	// Reading would close them, and the report still has to be written
+	if fd == 1 || fd == 2 {
+		return "", fmt.Errorf("file descriptor %d is stdout or stderr; pass the password on another descriptor", fd)
+	}
+	file := os.NewFile(uintptr(fd), fmt.Sprintf("fd %d", fd))
+	if file == nil {
+		return "", fmt.Errorf("invalid file descriptor %d", fd)
+	}
+	defer file.Close()
+	return terminal.ReadLine(file)
+}
+
+// promptPassword asks for the password on the terminal with echo turned off.
+// The prompt goes to stderr so stdout stays limited to the report.
+func promptPassword() (string, error) {
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		return "", errors.New("stdin is not a terminal; pipe the password with --stdin, or use --fd or --from-env")
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := terminal.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if errors.Is(err, terminal.ErrUnsupported) {
+		return "", fmt.Errorf("%w; use --stdin, --fd or --from-env", err)
+	}
+	return password, err
+}
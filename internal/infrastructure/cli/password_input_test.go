@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"os"
+	"testing"
+)
+
+func TestReadPasswordFromFD_RejectsOutputStreams(t *testing.T) {
+	for _, fd := range []int{1, 2} {
+		if _, err := readPasswordFromFD(fd); err == nil {
+			t.Errorf("readPasswordFromFD(%d) succeeded, want an error", fd)
+		}
+	}
+	// Both streams must still be open for the report
+	if _, err := os.Stdout.Stat(); err != nil {
+		t.Errorf("stdout: %v", err)
+	}
+	if _, err := os.Stderr.Stat(); err != nil {
+		t.Errorf("stderr: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kumarasakti/passgen/internal/application"
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/domain/services"
+	"github.com/spf13/cobra"
+)
+
+// addStandardFlags registers the compliance standard flags shared by
+// generation, check and policy
+func addStandardFlags(cmd *cobra.Command) {
+	cmd.Flags().String("standard", "", "Evaluate against a compliance standard ("+services.StandardNIST80063B+")")
+	cmd.Flags().Bool("mfa", false, "The password is one factor of multi-factor authentication (relaxes the minimum length)")
+}
+
+// HandlePolicy audits a password policy file against a standard
+func (h *Handler) HandlePolicy(cmd *cobra.Command, args []string) error {
+	standard, _ := cmd.Flags().GetString("standard")
+	mfa, _ := cmd.Flags().GetBool("mfa")
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening policy: %w", err)
+	}
+	policy, err := entities.ParsePasswordPolicy(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.passwordService.AuditPolicy(application.AuditPolicyRequest{Policy: policy, Standard: standard, MFA: mfa})
+	if err != nil {
+		return fmt.Errorf("auditing policy: %w", err)
+	}
+
+	fmt.Print(h.formatter.FormatCompliance(services.ComplianceResult{Standard: resp.Standard, Checks: resp.Checks, Compliant: resp.Compliant}))
+	if !resp.Compliant {
+		return entities.NewPasswordErrorOfKind(entities.KindPolicyViolation,
+			fmt.Sprintf("the policy does not comply with %s", resp.Standard))
+	}
+	return nil
+}
+
+// createPolicyCommand creates the policy subcommand
+func (h *Handler) createPolicyCommand() *cobra.Command {
+	policyCmd := &cobra.Command{
+		Use:   "policy [policy.json]",
+		Short: "Audit a password policy against a standard (default " + services.StandardNIST80063B + ")",
+		Long: `Check a password policy, given as JSON, for rules that conflict with a
+compliance standard: minimum lengths that are too short, maximum lengths that
+are too low, composition rules, forced expiry, missing blocklists, hints and
+security questions.
+
+Policy fields: min_length, max_length, require_lower, require_upper,
+require_numbers, require_symbols, min_char_classes, allow_spaces,
+allow_unicode, expiry_days, blocklist, allow_hints, security_questions.`,
+		Args: cobra.ExactArgs(1),
+		RunE: h.HandlePolicy,
+	}
+
+	addStandardFlags(policyCmd)
+
+	return policyCmd
+}
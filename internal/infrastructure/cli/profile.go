@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/infrastructure/profiles"
+	"github.com/spf13/cobra"
+)
+
+// addProfileFlag registers the compliance profile flag shared by generation and check
+func addProfileFlag(cmd *cobra.Command) {
+	cmd.Flags().String("profile", "", "Compliance profile to satisfy, by name or path to a profile JSON file (see 'passgen profile list')")
+}
+
+// loadProfiles makes the user's profiles available
+func (h *Handler) loadProfiles() error {
+	dir, err := profiles.DefaultDir()
+	if err != nil {
+		return nil
+	}
+	userProfiles, err := profiles.LoadDir(dir)
+	if err != nil {
+		return fmt.Errorf("loading profiles: %w", err)
+	}
+	h.passwordService.AddProfiles(userProfiles...)
+	return nil
+}
+
+// resolveProfile returns the profile name selected by --profile, loading the
+// user's profiles and, when the flag names a JSON file, that file
+func (h *Handler) resolveProfile(cmd *cobra.Command) (string, error) {
+	name, _ := cmd.Flags().GetString("profile")
+	if name == "" {
+		return "", nil
+	}
+	if err := h.loadProfiles(); err != nil {
+		return "", err
+	}
+
+	if strings.HasSuffix(name, ".json") || strings.ContainsRune(name, filepath.Separator) {
+		profile, err := profiles.LoadFile(name)
+		if err != nil {
+			return "", fmt.Errorf("loading profile: %w", err)
+		}
+		h.passwordService.AddProfiles(profile)
+		return profile.Name, nil
+	}
+	return name, nil
+}
+
+// applyProfileDefaults replaces the generation settings the user did not set
+// explicitly with those of the profile
+func (h *Handler) applyProfileDefaults(cmd *cobra.Command, name string) error {
+	profile, err := h.passwordService.GetProfile(name)
+	if err != nil {
+		return err
+	}
+
+	config := profile.Config(h.config.Count)
+	flags := cmd.Flags()
+	if !flags.Changed("length") {
+		h.config.Length = config.Length
+	}
+	if !flags.Changed("lower") {
+		h.config.IncludeLower = config.IncludeLower
+	}
+	if !flags.Changed("upper") {
+		h.config.IncludeUpper = config.IncludeUpper
+	}
+	if !flags.Changed("numbers") {
+		h.config.IncludeNumbers = config.IncludeNumbers
+	}
+	if !flags.Changed("symbols") {
+		h.config.IncludeSymbols = config.IncludeSymbols
+	}
+	if !flags.Changed("exclude-similar") {
+		h.config.ExcludeSimilar = config.ExcludeSimilar
+	}
+	if !flags.Changed("exclude-confusable") {
+		h.config.ExcludeConfusable = config.ExcludeConfusable
+	}
+	return nil
+}
+
+// HandleProfileList lists the available compliance profiles
+func (h *Handler) HandleProfileList(cmd *cobra.Command, args []string) error {
+	if err := h.loadProfiles(); err != nil {
+		return err
+	}
+
+	for _, name := range h.passwordService.ProfileNames() {
+		profile, _ := h.passwordService.GetProfile(name)
+		fmt.Printf("%-16s %s\n", profile.Name, profile.Title)
+	}
+	return nil
+}
+
+// HandleProfileShow prints a compliance profile's rules with their references
+func (h *Handler) HandleProfileShow(cmd *cobra.Command, args []string) error {
+	if err := h.loadProfiles(); err != nil {
+		return err
+	}
+
+	profile, err := h.passwordService.GetProfile(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(h.formatter.FormatProfile(profile))
+	return nil
+}
+
+// createProfileCommand creates the profile subcommand
+func (h *Handler) createProfileCommand() *cobra.Command {
+	profileCmd := &cobra.Command{
+		Use:   "profile",
+		Short: "List and show compliance profiles",
+		Long: `Compliance profiles are named rule sets (PCI DSS, CIS, HIPAA, Active Directory
+defaults, ...) selected with --profile when generating or checking passwords.
+
+Profiles are JSON files. Besides the built-in ones, every *.json file in the
+passgen/profiles directory of your user config directory is loaded, and
+--profile also accepts the path of a profile file.`,
+	}
+
+	profileCmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List the available compliance profiles",
+		Args:  cobra.NoArgs,
+		RunE:  h.HandleProfileList,
+	})
+	profileCmd.AddCommand(&cobra.Command{
+		Use:   "show [name]",
+		Short: "Print a profile's rules and the references they come from",
+		Args:  cobra.ExactArgs(1),
+		RunE:  h.HandleProfileShow,
+	})
+
+	return profileCmd
+}
@@ -0,0 +1,118 @@
+package estimators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// DefaultTimeout bounds how long an external estimator may take per password
+const DefaultTimeout = 10 * time.Second
+
+// Definition describes an external estimator in JSON
+type Definition struct {
+	Name           string   `json:"name"`
+	Command        []string `json:"command"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+}
+
+// CommandEstimator rates passwords by running an external program. The
+// password is written to its stdin, never its arguments, so it stays out of
+// the process table; the program prints a JSON result to stdout.
+type CommandEstimator struct {
+	name    string
+	command []string
+	timeout time.Duration
+}
+
+// result is what an external estimator prints
+type result struct {
+	Strength string   `json:"strength"`
+	Score    float64  `json:"score"`
+	MaxScore float64  `json:"max_score"`
+	Unit     string   `json:"unit"`
+	Factors  []factor `json:"factors"`
+}
+
+type factor struct {
+	Name         string  `json:"name"`
+	Contribution float64 `json:"contribution"`
+	Start        *int    `json:"start"`
+	End          *int    `json:"end"`
+	Rationale    string  `json:"rationale"`
+}
+
+// NewCommandEstimator creates an estimator from its definition
+func NewCommandEstimator(def Definition) (*CommandEstimator, error) {
+	if strings.TrimSpace(def.Name) == "" {
+		return nil, fmt.Errorf("estimator needs a name")
+	}
+	if len(def.Command) == 0 || def.Command[0] == "" {
+		return nil, fmt.Errorf("estimator %s needs a command", def.Name)
+	}
+	timeout := DefaultTimeout
+	if def.TimeoutSeconds > 0 {
+		timeout = time.Duration(def.TimeoutSeconds) * time.Second
+	}
+	return &CommandEstimator{name: def.Name, command: def.Command, timeout: timeout}, nil
+}
+
+// Name returns the name the estimator is selected by
+func (ce *CommandEstimator) Name() string {
+	return ce.name
+}
+
+// Estimate runs the program on one password
+func (ce *CommandEstimator) Estimate(password entities.Password) (services.StrengthEstimate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ce.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, ce.command[0], ce.command[1:]...)
+	cmd.Stdin = strings.NewReader(password.Value + "\n")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return services.StrengthEstimate{}, fmt.Errorf("estimator %s timed out after %s", ce.name, ce.timeout)
+		}
+		return services.StrengthEstimate{}, fmt.Errorf("estimator %s failed: %w: %s", ce.name, err, strings.TrimSpace(stderr.String()))
+	}
+
+	var out result
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		return services.StrengthEstimate{}, fmt.Errorf("estimator %s printed invalid JSON: %w", ce.name, err)
+	}
+	strength, err := entities.ParsePasswordStrength(out.Strength)
+	if err != nil {
+		return services.StrengthEstimate{}, fmt.Errorf("estimator %s: %w", ce.name, err)
+	}
+
+	estimate := services.StrengthEstimate{
+		Estimator: ce.name,
+		Strength:  strength,
+		Score:     out.Score,
+		MaxScore:  out.MaxScore,
+		Unit:      out.Unit,
+	}
+	for _, f := range out.Factors {
+		start, end := -1, -1
+		if f.Start != nil {
+			start, end = *f.Start, *f.Start
+			if f.End != nil {
+				end = *f.End
+			}
+		}
+		estimate.Factors = append(estimate.Factors, services.ScoringFactor{
+			Name: f.Name, Contribution: f.Contribution, Start: start, End: end, Rationale: f.Rationale,
+		})
+	}
+	return estimate, nil
+}
@@ -0,0 +1,66 @@
+package estimators
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// DefaultDir returns the directory of external estimator definitions in the user config directory
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "passgen", "estimators"), nil
+}
+
+// LoadDir reads every *.json estimator definition in dir, in file name order.
+// A missing directory holds no estimators, and a definition may not take the
+// name of a built-in estimator.
+func LoadDir(dir string) ([]services.StrengthEstimator, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	estimators := make([]services.StrengthEstimator, 0, len(paths))
+	for _, path := range paths {
+		estimator, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		estimators = append(estimators, estimator)
+	}
+	return estimators, nil
+}
+
+// LoadFile reads a single estimator definition
+func LoadFile(path string) (*CommandEstimator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var def Definition
+	if err := json.Unmarshal(data, &def); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	estimator, err := NewCommandEstimator(def)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if services.IsBuiltinEstimator(estimator.Name()) {
+		return nil, fmt.Errorf("%s: estimator name %q is taken by a built-in estimator", path, estimator.Name())
+	}
+	return estimator, nil
+}
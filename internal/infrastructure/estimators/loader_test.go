@@ -0,0 +1,93 @@
+package estimators
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	// Rates by length, proving the password arrives on stdin
+	script := `read p; printf '{"strength": "very strong", "score": %d, "unit": "chars", "factors": [{"name": "length", "contribution": %d, "start": 0, "end": 2, "rationale": "in-house"}, {"name": "policy", "rationale": "no span"}]}' ${#p} ${#p}`
+	definition := `{"name": "inhouse", "command": ["sh", "-c", ` + quote(script) + `], "timeout_seconds": 5}`
+	if err := os.WriteFile(filepath.Join(dir, "inhouse.json"), []byte(definition), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(loaded) != 1 || loaded[0].Name() != "inhouse" {
+		t.Fatalf("LoadDir() = %+v, want the inhouse estimator", loaded)
+	}
+
+	estimate, err := loaded[0].Estimate(entities.NewPassword("hunter22"))
+	if err != nil {
+		t.Fatalf("Estimate() error = %v", err)
+	}
+	if estimate.Strength != entities.VeryStrong || estimate.Score != 8 || estimate.Unit != "chars" {
+		t.Errorf("Estimate() = %+v, want Very Strong with score 8 chars", estimate)
+	}
+	if len(estimate.Factors) != 2 || estimate.Factors[0].End != 2 || estimate.Factors[1].HasSpan() {
+		t.Errorf("Factors = %+v, want a spanned and an unspanned factor", estimate.Factors)
+	}
+
+	if loaded, err := LoadDir(filepath.Join(dir, "missing")); err != nil || loaded != nil {
+		t.Errorf("LoadDir(missing) = %v, %v, want no estimators and no error", loaded, err)
+	}
+}
+
+func TestLoadDir_RejectsBuiltinNames(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "fast.json")
+	if err := os.WriteFile(path, []byte(`{"name": "zxcvbn", "command": ["true"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadDir(dir)
+	if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), "zxcvbn") {
+		t.Errorf("LoadDir() = %v, %v, want an error naming %s and zxcvbn", loaded, err, path)
+	}
+}
+
+func TestCommandEstimator_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+	}{
+		{"exit status", []string{"sh", "-c", "echo broken >&2; exit 3"}},
+		{"invalid JSON", []string{"sh", "-c", "echo not json"}},
+		{"unknown strength", []string{"sh", "-c", `echo '{"strength": "mighty"}'`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimator, err := NewCommandEstimator(Definition{Name: "bad", Command: tt.command})
+			if err != nil {
+				t.Fatalf("NewCommandEstimator() error = %v", err)
+			}
+			if _, err := estimator.Estimate(entities.NewPassword("x")); err == nil {
+				t.Error("Estimate() expected error")
+			}
+		})
+	}
+
+	if _, err := NewCommandEstimator(Definition{Name: "empty"}); err == nil {
+		t.Error("NewCommandEstimator() accepted a definition without a command")
+	}
+}
+
+// quote encodes s as a JSON string
+func quote(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// maxLineSize bounds a corpus line; longer lines are a corrupt or binary corpus
+const maxLineSize = 64 * 1024
+
+// Train counts every password in a corpus with one password per line. With
+// withCounts, each line is a count and a password separated by whitespace, as
+// written by 'sort | uniq -c'. Line endings are stripped but other whitespace
+// is part of the password.
+func Train(trainer *services.PCFGTrainer, r io.Reader, withCounts bool) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxLineSize)
+	lines := 0
+	for scanner.Scan() {
+		lines++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		count := 1
+		if withCounts {
+			var err error
+			if count, line, err = splitCount(line); err != nil {
+				return lines, fmt.Errorf("line %d: %w", lines, err)
+			}
+		}
+		trainer.Add(line, count)
+	}
+	return lines, scanner.Err()
+}
+
+// splitCount parses a "count password" line
+func splitCount(line string) (int, string, error) {
+	line = strings.TrimLeft(line, " \t")
+	end := strings.IndexAny(line, " \t")
+	if end < 0 {
+		return 0, "", fmt.Errorf("expected a count and a password")
+	}
+	count, err := strconv.Atoi(line[:end])
+	if err != nil || count < 0 {
+		return 0, "", fmt.Errorf("invalid count %q", line[:end])
+	}
+	// uniq -c separates the count from the password with a single space
+	return count, line[end+1:], nil
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+func TestTrain(t *testing.T) {
+	trainer := services.NewPCFGTrainer()
+	lines, err := Train(trainer, strings.NewReader("hunter2\r\nhunter2\n"), false)
+	if err != nil || lines != 2 {
+		t.Fatalf("Train() = %d, %v, want 2 lines", lines, err)
+	}
+	if model := trainer.Model(1); model.Passwords != 2 || model.Digits["2"] != 2 {
+		t.Errorf("Train() model = %+v, want hunter2 twice", model)
+	}
+
+	trainer = services.NewPCFGTrainer()
+	if _, err := Train(trainer, strings.NewReader("   290 123456\n      7 pass word\n"), true); err != nil {
+		t.Fatalf("Train(counts) error = %v", err)
+	}
+	model := trainer.Model(1)
+	if model.Passwords != 297 || model.Structures["D6"] != 290 || model.Structures["L4S1L4"] != 7 {
+		t.Errorf("Train(counts) model = %+v", model)
+	}
+
+	for _, corpus := range []string{"123456\n", "x1 123456\n"} {
+		if _, err := Train(services.NewPCFGTrainer(), strings.NewReader(corpus), true); err == nil {
+			t.Errorf("Train(%q, counts) expected error", corpus)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "passgen", "model.json")
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if model, err := store.Load(); err != nil || model != nil {
+		t.Fatalf("Load() with no model = %v, %v, want nil, nil", model, err)
+	}
+
+	trainer := services.NewPCFGTrainer()
+	trainer.Add("dragon1", 3)
+	if err := store.Save(trainer.Model(2)); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	model, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if model.Passwords != 3 || model.Structures["L6D1"] != 3 {
+		t.Errorf("Load() = %+v, want the saved model", model)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Load(); !errors.Is(err, ErrInvalidModel) {
+		t.Errorf("Load() of an unsupported model version = %v, want ErrInvalidModel", err)
+	}
+}
+
+func TestStore_RejectsMalformedModels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "model.json")
+	store, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"empty n-gram":       `{"version":1,"passwords":1,"structures":{"L4":1},"letters":{"":1}}`,
+		"long n-gram":        `{"version":1,"passwords":1,"structures":{"L4":1},"letters":{"abcd":1}}`,
+		"negative count":     `{"version":1,"passwords":1,"structures":{"L4":-1}}`,
+		"unparsed structure": `{"version":1,"passwords":1,"structures":{"X4":1}}`,
+		"zero-length run":    `{"version":1,"passwords":1,"structures":{"L0D2":1}}`,
+		"no passwords":       `{"version":1,"passwords":0,"structures":{"L4":1}}`,
+		"not JSON":           `{"version":1,`,
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if model, err := store.Load(); !errors.Is(err, ErrInvalidModel) || !strings.Contains(err.Error(), path) {
+				t.Errorf("Load() = %v, %v, want ErrInvalidModel naming %s", model, err, path)
+			}
+		})
+	}
+}
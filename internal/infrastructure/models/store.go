@@ -0,0 +1,86 @@
+// Package models trains guess models on password corpora and stores them in
+// the user config directory.
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// ErrInvalidModel marks a model file that can't be used, such as one that was
+// edited by hand or written by another version
+var ErrInvalidModel = errors.New("invalid guess model")
+
+// Store persists a trained guess model as JSON
+type Store struct {
+	path string
+}
+
+// NewStore creates a Store at path, or at DefaultPath when path is empty
+func NewStore(path string) (*Store, error) {
+	if path == "" {
+		defaultPath, err := DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return &Store{path: path}, nil
+}
+
+// DefaultPath returns the model file location in the user config directory
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "passgen", "model.json"), nil
+}
+
+// Path returns the file the store reads and writes
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads and validates the stored model; it returns nil without error
+// when none exists
+func (s *Store) Load() (*services.PCFGModel, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var model services.PCFGModel
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", s.path, ErrInvalidModel, err)
+	}
+	if model.Version != services.PCFGModelVersion {
+		return nil, fmt.Errorf("%s: %w: unsupported version %d (want %d)", s.path, ErrInvalidModel, model.Version, services.PCFGModelVersion)
+	}
+	if err := model.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", s.path, ErrInvalidModel, err)
+	}
+	return &model, nil
+}
+
+// Save writes the model, creating the parent directory if needed. The model
+// is written compactly as it can hold hundreds of thousands of counts.
+func (s *Store) Save(model *services.PCFGModel) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(model)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, append(data, '\n'), 0o644)
+}
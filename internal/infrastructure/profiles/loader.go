@@ -0,0 +1,58 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kumarasakti/passgen/internal/domain/entities"
+)
+
+// DefaultDir returns the directory of user-defined profiles in the user config directory
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "passgen", "profiles"), nil
+}
+
+// LoadDir reads every *.json profile in dir, in file name order. A missing
+// directory holds no profiles.
+func LoadDir(dir string) ([]entities.ComplianceProfile, error) {
+	if _, err := os.Stat(dir); errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	profiles := make([]entities.ComplianceProfile, 0, len(paths))
+	for _, path := range paths {
+		profile, err := LoadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// LoadFile reads a single profile
+func LoadFile(path string) (entities.ComplianceProfile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return entities.ComplianceProfile{}, err
+	}
+	defer file.Close()
+
+	profile, err := entities.ParseComplianceProfile(file)
+	if err != nil {
+		return entities.ComplianceProfile{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return profile, nil
+}
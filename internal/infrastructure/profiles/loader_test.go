@@ -0,0 +1,39 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	profile := `{"name": "acme", "title": "ACME baseline", "generation": {"length": 20, "lower": true, "upper": true, "numbers": true},
+		"policy": {"min_length": 16}, "references": {"min_length": "ACME security standard 4.2"}}`
+	if err := os.WriteFile(filepath.Join(dir, "acme.json"), []byte(profile), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(profiles) != 1 || profiles[0].Name != "acme" || profiles[0].Policy.MinLength != 16 {
+		t.Errorf("LoadDir() = %+v, want the acme profile", profiles)
+	}
+
+	if profiles, err := LoadDir(filepath.Join(dir, "missing")); err != nil || profiles != nil {
+		t.Errorf("LoadDir(missing) = %v, %v, want no profiles and no error", profiles, err)
+	}
+
+	invalid := `{"name": "weak", "generation": {"length": 8, "lower": true}, "policy": {"min_length": 12}}`
+	if err := os.WriteFile(filepath.Join(dir, "weak.json"), []byte(invalid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDir(dir); err == nil {
+		t.Error("LoadDir() accepted a profile whose generation violates its policy")
+	}
+}
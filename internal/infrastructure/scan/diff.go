@@ -0,0 +1,92 @@
+package scan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// hunkHeader matches "@@ -12,3 +14,5 @@", capturing the line counts of the
+// old and new versions and the new version's first line
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// ReadDiff returns the lines a unified diff adds, such as the output of
+// git diff --cached, numbered as in the new version of each file. Removed
+// and context lines are left out: only new secrets need reporting.
+func ReadDiff(r io.Reader) ([]services.ScanLine, error) {
+	var lines []services.ScanLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	name := ""
+	number := 0
+	oldLeft, newLeft := 0, 0 // lines of the current hunk still to come
+	for scanner.Scan() {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if oldLeft == 0 && newLeft == 0 {
+			// Between hunks: file headers such as "diff --git", "---" and "+++"
+			switch {
+			case strings.HasPrefix(text, "+++ "):
+				name = diffPath(strings.TrimPrefix(text, "+++ "))
+			case strings.HasPrefix(text, "@@"):
+				m := hunkHeader.FindStringSubmatch(text)
+				if m == nil {
+					return nil, fmt.Errorf("malformed hunk header: %s", text)
+				}
+				oldLeft, newLeft = hunkCount(m[1]), hunkCount(m[3])
+				number, _ = strconv.Atoi(m[2])
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+"):
+			if name != "" && !Skipped(name) && len(text) > 1 {
+				lines = append(lines, services.ScanLine{Path: name, Number: number, Text: text[1:]})
+			}
+			number++
+			newLeft--
+		case strings.HasPrefix(text, "-"):
+			oldLeft--
+		case strings.HasPrefix(text, `\`):
+			// "\ No newline at end of file"
+		default:
+			// Context lines, whose leading space some tools strip when blank
+			number++
+			oldLeft--
+			newLeft--
+		}
+		oldLeft, newLeft = max(oldLeft, 0), max(newLeft, 0)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading diff: %w", err)
+	}
+	return lines, nil
+}
+
+// hunkCount reads a hunk header's optional line count, which defaults to 1
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	count, _ := strconv.Atoi(s)
+	return count
+}
+
+// diffPath returns the file name of a "+++" header, without git's b/ prefix,
+// a trailing timestamp or quotes. It returns "" for deleted files.
+func diffPath(header string) string {
+	if i := strings.IndexByte(header, '\t'); i >= 0 {
+		header = header[:i]
+	}
+	header = strings.Trim(header, `"`)
+	if header == "/dev/null" {
+		return ""
+	}
+	return strings.TrimPrefix(header, "b/")
+}
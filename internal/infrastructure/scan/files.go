@@ -0,0 +1,151 @@
+// Package scan reads the files and diffs that passgen scans for hardcoded
+// secrets
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// maxFileSize bounds the files scanned; larger files are usually data or
+// build output
+const maxFileSize = 1 << 20
+
+// maxLineLength bounds a single scanned line; minified files can exceed it
+const maxLineLength = 1 << 20
+
+// sniffLength is how much of a file is checked for NUL bytes to detect binaries
+const sniffLength = 8000
+
+// skippedFiles hold checksums and resolved versions that look like tokens
+var skippedFiles = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"Cargo.lock": true, "poetry.lock": true, "composer.lock": true, "Gemfile.lock": true,
+}
+
+// Skipped reports whether a file is never scanned because of its name
+func Skipped(name string) bool {
+	return skippedFiles[path.Base(filepath.ToSlash(name))]
+}
+
+// Walk calls visit with the lines of every text file under root, skipping
+// the .git directory, paths excluded by .gitignore files and
+// .git/info/exclude, binaries, oversized files and lock files. Reported
+// paths start with root as given. When root is a file, only that file is read.
+func Walk(root string, visit func(lines []services.ScanLine) error) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		lines, err := readFile(root)
+		if err != nil {
+			return err
+		}
+		return visit(lines)
+	}
+
+	ignore := &Ignore{}
+	if err := addIgnoreFile(ignore, "", filepath.Join(root, ".git", "info", "exclude")); err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(root, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if entry.IsDir() {
+			if rel == "." {
+				return addIgnoreFile(ignore, "", filepath.Join(p, ".gitignore"))
+			}
+			if entry.Name() == ".git" || ignore.Match(rel, true) {
+				return filepath.SkipDir
+			}
+			return addIgnoreFile(ignore, rel, filepath.Join(p, ".gitignore"))
+		}
+		if !entry.Type().IsRegular() || ignore.Match(rel, false) || Skipped(rel) {
+			return nil
+		}
+		lines, err := readFile(p)
+		if err != nil {
+			return err
+		}
+		return visit(lines)
+	})
+}
+
+// addIgnoreFile adds the rules of an ignore file, if it exists
+func addIgnoreFile(ignore *Ignore, base, name string) error {
+	file, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := ignore.Add(base, file); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	return nil
+}
+
+// readFile returns the lines of a text file, or none for binaries and files
+// over maxFileSize
+func readFile(name string) ([]services.ScanLine, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxFileSize {
+		return nil, nil
+	}
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if bytes.IndexByte(content[:min(len(content), sniffLength)], 0) >= 0 {
+		return nil, nil
+	}
+	return ReadLines(filepath.ToSlash(name), bytes.NewReader(content))
+}
+
+// ReadLines reads the lines of a text file, tolerating CRLF line endings
+func ReadLines(name string, r io.Reader) ([]services.ScanLine, error) {
+	var lines []services.ScanLine
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if text != "" {
+			lines = append(lines, services.ScanLine{Path: name, Number: number, Text: text})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,111 @@
+package scan
+
+import (
+	"bufio"
+	"io"
+	"path"
+	"strings"
+)
+
+// ignoreRule is one pattern from a .gitignore file
+type ignoreRule struct {
+	base     string // slash-separated directory of the .gitignore, relative to the scan root; "" for the root
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool // the pattern contains a slash, so it matches paths relative to base rather than names
+}
+
+// Ignore decides which paths .gitignore files exclude. It supports comments,
+// negation, directory-only and anchored patterns, and *, ?, [...] and **
+// wildcards.
+type Ignore struct {
+	rules []ignoreRule
+}
+
+// Add reads the rules of a .gitignore file in the directory base, given
+// slash-separated and relative to the scan root
+func (ig *Ignore) Add(base string, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(strings.TrimSuffix(scanner.Text(), "\r"), " ")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`)
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+		rule.pattern = line
+		ig.rules = append(ig.rules, rule)
+	}
+	return scanner.Err()
+}
+
+// Match reports whether the slash-separated path rel, relative to the scan
+// root, is ignored. The last matching rule wins, as in git.
+func (ig *Ignore) Match(rel string, isDir bool) bool {
+	ignored := false
+	for _, rule := range ig.rules {
+		if rule.matches(rel, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+// matches reports whether the rule applies to rel
+func (r ignoreRule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	sub := rel
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		sub = strings.TrimPrefix(rel, r.base+"/")
+	}
+	if !r.anchored {
+		matched, _ := path.Match(r.pattern, path.Base(sub))
+		return matched
+	}
+	return matchSegments(strings.Split(r.pattern, "/"), strings.Split(sub, "/"))
+}
+
+// matchSegments matches path segments against pattern segments, where **
+// stands for any number of segments
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if matched, _ := path.Match(pattern[0], segments[0]); !matched {
+		return false
+	}
+	return matchSegments(pattern[1:], segments[1:])
+}
@@ -0,0 +1,18 @@
+package scan
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+// WriteJSON writes the findings as an indented JSON array
+func WriteJSON(w io.Writer, findings []services.SecretFinding) error {
+	if findings == nil {
+		findings = []services.SecretFinding{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(findings)
+}
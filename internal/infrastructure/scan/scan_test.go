@@ -0,0 +1,146 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/kumarasakti/passgen/internal/domain/services"
+)
+
+func TestIgnore_Match(t *testing.T) {
+	ignore := &Ignore{}
+	rules := "# build output\nbuild/\n*.log\n!keep.log\n/root-only.txt\ndocs/**/draft.md\n"
+	if err := ignore.Add("", strings.NewReader(rules)); err != nil {
+		t.Fatal(err)
+	}
+	if err := ignore.Add("sub", strings.NewReader("secret.env\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"app.log", false, true},
+		{"logs/app.log", false, true},
+		{"keep.log", false, false},
+		{"root-only.txt", false, true},
+		{"src/root-only.txt", false, false},
+		{"docs/draft.md", false, true},
+		{"docs/a/b/draft.md", false, true},
+		{"sub/secret.env", false, true},
+		{"sub/deeper/secret.env", false, true},
+		{"secret.env", false, false},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		if got := ignore.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":          "ignored/\n*.tmp\n",
+		"values.yaml":         "password: hunter2\n",
+		"ignored/values.yaml": "password: hunter2\n",
+		"scratch.tmp":         "password: hunter2\n",
+		"nested/.gitignore":   "local.env\n",
+		"nested/local.env":    "password: hunter2\n",
+		"nested/app.env":      "\r\nPASSWORD=hunter2\r\n",
+		"go.sum":              "h1:abc=\n",
+		"image.bin":           "password: \x00",
+		".git/config":         "password = hunter2\n",
+		".git/info/exclude":   "excluded.txt\n",
+		"excluded.txt":        "password: hunter2\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var got []string
+	err := Walk(root, func(lines []services.ScanLine) error {
+		for _, line := range lines {
+			rel, _ := filepath.Rel(root, filepath.FromSlash(line.Path))
+			got = append(got, filepath.ToSlash(rel)+":"+line.Text)
+			if line.Text == "PASSWORD=hunter2" && line.Number != 2 {
+				t.Errorf("line number = %d, want 2", line.Number)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+
+	sort.Strings(got)
+	want := []string{".gitignore:*.tmp", ".gitignore:ignored/", "nested/.gitignore:local.env", "nested/app.env:PASSWORD=hunter2", "values.yaml:password: hunter2"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Walk() read\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestReadDiff(t *testing.T) {
+	diff := `diff --git a/values.yaml b/values.yaml
+index 1111111..2222222 100644
+--- a/values.yaml
++++ b/values.yaml
+@@ -2,4 +2,5 @@ postgresql:
+   auth:
+-    password: hunter2
++    password: hunter3
+     user: app
++++ leading pluses
+ 
+\ No newline at end of file
+diff --git a/old.env b/old.env
+deleted file mode 100644
+--- a/old.env
++++ /dev/null
+@@ -1 +0,0 @@
+-PASSWORD=gone
+diff --git a/go.sum b/go.sum
+--- a/go.sum
++++ b/go.sum
+@@ -0,0 +1 @@
++h1:abc=
+`
+	lines, err := ReadDiff(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("ReadDiff() error = %v", err)
+	}
+
+	want := []services.ScanLine{
+		{Path: "values.yaml", Number: 3, Text: "    password: hunter3"},
+		{Path: "values.yaml", Number: 5, Text: "++ leading pluses"},
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("ReadDiff() = %+v, want %+v", lines, want)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestReadDiff_MalformedHunk(t *testing.T) {
+	if _, err := ReadDiff(strings.NewReader("+++ b/a\n@@ bogus @@\n")); err == nil {
+		t.Error("ReadDiff() of a malformed hunk header succeeded, want an error")
+	}
+}
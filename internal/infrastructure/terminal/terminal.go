@@ -0,0 +1,42 @@
+// Package terminal reads secrets from the user without echoing them
+package terminal
+
+import (
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrUnsupported is returned where reading without echo isn't implemented
+var ErrUnsupported = errors.New("reading without echo is not supported on this platform")
+
+// maxLineLength bounds a secret read from a terminal or stream
+const maxLineLength = 4096
+
+// ReadLine reads up to the first newline from r, one byte at a time so
+// nothing past the line is consumed, and strips the line ending. Input ending
+// without a newline counts as a line; no input at all is io.EOF.
+func ReadLine(r io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for len(line) < maxLineLength {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return strings.TrimSuffix(string(line), "\r"), nil
+			}
+			line = append(line, buf[0])
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			if len(line) == 0 {
+				return "", io.EOF
+			}
+			return strings.TrimSuffix(string(line), "\r"), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", errors.New("line too long")
+}
@@ -0,0 +1,83 @@
+//go:build linux
+
+package terminal
+
+import (
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"unsafe"
+)
+
+// IsTerminal reports whether fd refers to a terminal
+func IsTerminal(fd int) bool {
+	_, err := getTermios(fd)
+	return err == nil
+}
+
+// ReadPassword reads a line from the terminal fd with echo turned off,
+// restoring the terminal afterwards, or before exiting if interrupted
+func ReadPassword(fd int) (string, error) {
+	old, err := getTermios(fd)
+	if err != nil {
+		return "", err
+	}
+
+	noEcho := *old
+	noEcho.Lflag &^= syscall.ECHO
+	noEcho.Lflag |= syscall.ICANON | syscall.ISIG
+	noEcho.Iflag |= syscall.ICRNL
+	if err := setTermios(fd, &noEcho); err != nil {
+		return "", err
+	}
+	defer setTermios(fd, old)
+
+	// Ctrl-C would otherwise leave the shell without echo
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			setTermios(fd, old)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	defer func() {
+		signal.Stop(signals)
+		close(done)
+	}()
+
+	return ReadLine(fdReader(fd))
+}
+
+// fdReader reads from a file descriptor without taking ownership of it
+type fdReader int
+
+func (fd fdReader) Read(p []byte) (int, error) {
+	n, err := syscall.Read(int(fd), p)
+	if n < 0 {
+		n = 0
+	}
+	if n == 0 && err == nil {
+		return 0, io.EOF
+	}
+	return n, err
+}
+
+func getTermios(fd int) (*syscall.Termios, error) {
+	termios := &syscall.Termios{}
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.TCGETS, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return nil, errno
+	}
+	return termios, nil
+}
+
+func setTermios(fd int, termios *syscall.Termios) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), syscall.TCSETS, uintptr(unsafe.Pointer(termios))); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+//go:build !linux
+
+package terminal
+
+import "os"
+
+// IsTerminal reports whether fd is a standard stream attached to a character
+// device such as a terminal
+func IsTerminal(fd int) bool {
+	var file *os.File
+	switch fd {
+	case 0:
+		file = os.Stdin
+	case 1:
+		file = os.Stdout
+	case 2:
+		file = os.Stderr
+	default:
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// ReadPassword is not implemented on this platform; pass the password on
+// stdin or a file descriptor instead
+func ReadPassword(fd int) (string, error) {
+	return "", ErrUnsupported
+}
@@ -0,0 +1,69 @@
+package terminal
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadLine(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"newline", "hunter2\n", "hunter2"},
+		{"crlf", "hunter2\r\n", "hunter2"},
+		{"no trailing newline", "hunter2", "hunter2"},
+		{"keeps spaces", "  correct horse  \n", "  correct horse  "},
+		{"empty line", "\nsecond\n", ""},
+		{"unicode", "пароль🔑\n", "пароль🔑"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadLine(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ReadLine() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ReadLine() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadLine_ConsumesOnlyFirstLine(t *testing.T) {
+	r := strings.NewReader("first\nsecond\n")
+	if _, err := ReadLine(r); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadLine(r)
+	if err != nil || got != "second" {
+		t.Errorf("second ReadLine() = %q, %v, want %q", got, err, "second")
+	}
+}
+
+func TestReadLine_Errors(t *testing.T) {
+	if _, err := ReadLine(strings.NewReader("")); !errors.Is(err, io.EOF) {
+		t.Errorf("ReadLine(empty) error = %v, want io.EOF", err)
+	}
+	if _, err := ReadLine(strings.NewReader(strings.Repeat("a", maxLineLength+1))); err == nil {
+		t.Error("ReadLine() of an overlong line succeeded, want an error")
+	}
+}
+
+func TestIsTerminal_Pipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if IsTerminal(int(r.Fd())) {
+		t.Error("IsTerminal() = true for a pipe")
+	}
+}